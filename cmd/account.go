@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/infra/mailer"
+	"gostarter/infra/pgdatabase"
+	"gostarter/internals/domain"
+	"gostarter/internals/service"
+	"gostarter/internals/storage/pgstorage"
+	"gostarter/pkg/testUtils"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// accountCmd represents the account command
+var accountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "Delete, restore, purge and export accounts",
+}
+
+var accountDeleteCmd = &cobra.Command{
+	Use:   "delete <id>",
+	Short: "Delete an account, it can be restored until the grace period has passed",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		accountService, _, logger := newAccountServices()
+
+		err := accountService.DeleteAccount(context.Background(), parseAccountId(args[0], logger))
+		if err != nil {
+			logger.Error("failed to delete account", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var accountRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Restore a deleted account that was not purged yet",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		accountService, _, logger := newAccountServices()
+
+		err := accountService.RestoreAccount(context.Background(), parseAccountId(args[0], logger))
+		if err != nil {
+			logger.Error("failed to restore account", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var accountPurgeCmd = &cobra.Command{
+	Use:   "purge [id]",
+	Short: "Erase an account and its data right away, or with --expired the accounts past the grace period",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		accountService, _, logger := newAccountServices()
+
+		expired, _ := cmd.Flags().GetBool("expired")
+		if expired == (len(args) == 1) {
+			logger.Error("either an account id or --expired is required")
+			os.Exit(1)
+		}
+
+		if expired {
+			purged, err := accountService.PurgeDeletedAccounts(context.Background())
+			if err != nil {
+				logger.Error("failed to purge deleted accounts", "error", err)
+				os.Exit(1)
+			}
+
+			fmt.Println("purged:", purged)
+			return
+		}
+
+		err := accountService.PurgeAccount(context.Background(), parseAccountId(args[0], logger))
+		if err != nil {
+			logger.Error("failed to purge account", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var accountExportCmd = &cobra.Command{
+	Use:   "export <id>",
+	Short: "Export the data of an account as a ZIP archive, or JSON for a .json output",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		_, exportService, logger := newAccountServices()
+
+		export, err := exportService.Export(context.Background(), parseAccountId(args[0], logger))
+		if err != nil {
+			logger.Error("failed to export account", "error", err)
+			os.Exit(1)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+
+		var w io.Writer = os.Stdout
+		if output != "-" {
+			file, err := os.Create(output)
+			if err != nil {
+				logger.Error("failed to create output file", "error", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			w = file
+		}
+
+		if strings.HasSuffix(output, ".json") {
+			encoder := json.NewEncoder(w)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(export)
+		} else {
+			err = exportService.WriteArchive(export, w)
+		}
+
+		if err != nil {
+			logger.Error("failed to write account export", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func parseAccountId(value string, logger *slog.Logger) int {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		logger.Error("invalid account id", "id", value)
+		os.Exit(1)
+	}
+	return id
+}
+
+func newAccountServices() (domain.AccountService, domain.AccountExportService, *slog.Logger) {
+	cfg := config.NewConfig()
+	sqlConn := pgdatabase.NewConnection(cfg.Database.Postgres.Connection)
+	// Logs go to stderr, the export may be written to stdout
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tracer := testUtils.NewNoopTracer()
+
+	container := &infra.Container{
+		Cfg:    cfg,
+		Logger: logger,
+		DbConn: sqlConn,
+		Mailer: mailer.NewMailer(cfg.Mailer),
+		Tracer: tracer,
+	}
+
+	accountRepo := pgstorage.NewAccountRepository(container)
+	accountTokenRepo := pgstorage.NewAccountTokenRepository(container)
+	refreshTokenRepo := pgstorage.NewRefreshTokenRepository(container)
+	verificationService := service.NewVerificationService(container, accountRepo, accountTokenRepo)
+	auditService := service.NewAuditService(container, pgstorage.NewAuditRepository(container))
+	lockoutService := service.NewLockoutService(container, accountRepo, pgstorage.NewLockoutRepository(container), auditService)
+	passwordPolicyService := service.NewPasswordPolicyService(container, pgstorage.NewPasswordHistoryRepository(container))
+
+	// Sessions are revoked in the shared postgres store, the memory store lives in the server process
+	tokenService := service.NewTokenService(
+		container,
+		refreshTokenRepo,
+		pgstorage.NewTokenRevocationRepository(container),
+	)
+	accountService := service.NewAccountService(container, accountRepo, tokenService, verificationService, lockoutService, passwordPolicyService, auditService)
+	exportService := service.NewAccountExportService(container, accountService, refreshTokenRepo, auditService)
+
+	return accountService, exportService, logger
+}
+
+func init() {
+	rootCmd.AddCommand(accountCmd)
+	accountCmd.AddCommand(accountDeleteCmd, accountRestoreCmd, accountPurgeCmd, accountExportCmd)
+
+	accountPurgeCmd.Flags().Bool("expired", false, "Purge every account deleted longer ago than account_deletion_grace_days")
+	accountExportCmd.Flags().StringP("output", "o", "-", "File to write, - for stdout")
+}
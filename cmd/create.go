@@ -25,6 +25,7 @@ import (
 	"context"
 	"gostarter/infra"
 	"gostarter/infra/config"
+	"gostarter/infra/mailer"
 	"gostarter/infra/pgdatabase"
 	"gostarter/internals/domain"
 	"gostarter/internals/service"
@@ -32,6 +33,7 @@ import (
 	"gostarter/pkg/testUtils"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -55,23 +57,42 @@ var adminCmd = &cobra.Command{
 			Cfg:    cfg,
 			Logger: logger,
 			DbConn: sqlConn,
+			Mailer: mailer.NewMailer(cfg.Mailer),
 			Tracer: tracer,
 		}
 
 		accountRepo := pgstorage.NewAccountRepository(container)
-		accountService := service.NewAccountService(container, accountRepo)
+		accountTokenRepo := pgstorage.NewAccountTokenRepository(container)
+		verificationService := service.NewVerificationService(container, accountRepo, accountTokenRepo)
+		auditService := service.NewAuditService(container, pgstorage.NewAuditRepository(container))
+		lockoutService := service.NewLockoutService(container, accountRepo, pgstorage.NewLockoutRepository(container), auditService)
+		passwordPolicyService := service.NewPasswordPolicyService(container, pgstorage.NewPasswordHistoryRepository(container))
+		tokenService := service.NewTokenService(
+			container,
+			pgstorage.NewRefreshTokenRepository(container),
+			pgstorage.NewTokenRevocationRepository(container),
+		)
+		accountService := service.NewAccountService(container, accountRepo, tokenService, verificationService, lockoutService, passwordPolicyService, auditService)
 
 		email, _ := cmd.Flags().GetString("email")
+		username, _ := cmd.Flags().GetString("username")
 		password, _ := cmd.Flags().GetString("password")
 
+		// Admins created from the cli are trusted, skip email verification
+		now := time.Now()
 		acc := &domain.Account{
-			Username: email,
-			Email:    email,
-			Password: password,
-			Roles:    []string{domain.ROLE_ADMIN},
+			Username:        username,
+			Email:           email,
+			Password:        password,
+			Roles:           []string{domain.ROLE_ADMIN},
+			EmailVerifiedAt: &now,
 		}
 
-		accountService.Register(context.Background(), acc)
+		err := accountService.Register(context.Background(), acc)
+		if err != nil {
+			logger.Error("failed to create admin", "error", err)
+			return
+		}
 
 	},
 }
@@ -81,6 +102,7 @@ func init() {
 	createCmd.AddCommand(adminCmd)
 
 	adminCmd.Flags().StringP("email", "e", "", "Email of the admin")
+	adminCmd.Flags().StringP("username", "u", "", "Optional username of the admin")
 	adminCmd.Flags().StringP("password", "p", "", "Password of the admin")
 
 }
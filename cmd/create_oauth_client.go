@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/infra/mailer"
+	"gostarter/infra/pgdatabase"
+	"gostarter/internals/domain"
+	"gostarter/internals/service"
+	"gostarter/internals/storage/memory"
+	"gostarter/internals/storage/pgstorage"
+	"gostarter/pkg/testUtils"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var oauthClientCmd = &cobra.Command{
+	Use:   "oauth-client",
+	Short: "Register an application that signs in with gostarter",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.NewConfig()
+		sqlConn := pgdatabase.NewConnection(cfg.Database.Postgres.Connection)
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+		tracer := testUtils.NewNoopTracer()
+
+		container := &infra.Container{
+			Cfg:    cfg,
+			Logger: logger,
+			DbConn: sqlConn,
+			Mailer: mailer.NewMailer(cfg.Mailer),
+			Tracer: tracer,
+		}
+
+		accountRepo := pgstorage.NewAccountRepository(container)
+		accountTokenRepo := pgstorage.NewAccountTokenRepository(container)
+		verificationService := service.NewVerificationService(container, accountRepo, accountTokenRepo)
+		auditService := service.NewAuditService(container, pgstorage.NewAuditRepository(container))
+		lockoutService := service.NewLockoutService(container, accountRepo, pgstorage.NewLockoutRepository(container), auditService)
+		passwordPolicyService := service.NewPasswordPolicyService(container, pgstorage.NewPasswordHistoryRepository(container))
+		tokenService := service.NewTokenService(
+			container,
+			pgstorage.NewRefreshTokenRepository(container),
+			memory.NewTokenRevocationRepository(container),
+		)
+		accountService := service.NewAccountService(container, accountRepo, tokenService, verificationService, lockoutService, passwordPolicyService, auditService)
+		oauthService := service.NewOAuthService(
+			container,
+			accountService,
+			tokenService,
+			pgstorage.NewOAuthClientRepository(container),
+			pgstorage.NewOAuthGrantRepository(container),
+		)
+
+		name, _ := cmd.Flags().GetString("name")
+		redirectURIs, _ := cmd.Flags().GetStringSlice("redirect-uri")
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		public, _ := cmd.Flags().GetBool("public")
+
+		client := &domain.OAuthClient{
+			Name:         name,
+			RedirectURIs: redirectURIs,
+			Scopes:       scopes,
+		}
+
+		secret, err := oauthService.RegisterClient(context.Background(), client, public)
+		if err != nil {
+			logger.Error("failed to register oauth client", "error", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("client_id:", client.ClientId)
+		if !public {
+			// The secret is stored hashed and cannot be shown again
+			fmt.Println("client_secret:", secret)
+		}
+	},
+}
+
+func init() {
+	createCmd.AddCommand(oauthClientCmd)
+
+	oauthClientCmd.Flags().StringP("name", "n", "", "Name shown on the consent page")
+	oauthClientCmd.Flags().StringSliceP("redirect-uri", "r", nil, "Allowed redirect uri, can be repeated")
+	oauthClientCmd.Flags().StringSliceP("scope", "s", nil, "Allowed scopes, defaults to openid, email and profile")
+	oauthClientCmd.Flags().Bool("public", false, "Register a public client without a secret, PKCE is required")
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"gostarter/infra/config"
+	"gostarter/pkg/keyring"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultKeyRetireGrace = 24 * time.Hour
+
+// keysCmd represents the keys command
+var keysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage the JWT signing keyring",
+}
+
+var keysRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Generate a new signing key and retire the active one after a grace period",
+	Long: `Generates a new signing key that becomes the active key after the publish delay.
+Until then the key only verifies tokens, so every server and the JWKS endpoint know
+the key before any server signs with it. The delay defaults to the keyring reload
+interval, raise it for OIDC clients that cache the JWKS longer. The previous key
+keeps verifying tokens until the grace period after that ends.
+
+The first rotation imports the configured key pair into the keyring, so tokens
+signed before the rotation stay valid.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.NewConfig()
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+		dir := cfg.JWT.KeyringDir
+		if dir == "" {
+			logger.Error("jwt_keyring_dir is not configured")
+			os.Exit(1)
+		}
+
+		grace, _ := cmd.Flags().GetDuration("grace")
+		if grace <= 0 {
+			grace = time.Hour * time.Duration(cfg.JWT.KeyRetireGraceHours)
+		}
+		if grace <= 0 {
+			grace = defaultKeyRetireGrace
+		}
+
+		delay, _ := cmd.Flags().GetDuration("publish-delay")
+		if delay <= 0 {
+			delay = time.Second * time.Duration(cfg.JWT.KeyringReloadSeconds)
+		}
+		if delay <= 0 {
+			delay = time.Second * config.DEFAULT_KEYRING_RELOAD_SECONDS
+		}
+
+		now := time.Now()
+		if !keyring.Exists(dir) {
+			privateKey, _, err := utils.LoadECDSAKeyPair(cfg.JWT.PrivateKeyPath, cfg.JWT.PublicKeyPath)
+			if err == nil {
+				imported, err := keyring.Import(dir, privateKey, now)
+				if err != nil {
+					logger.Error("failed to import the configured key pair", "error", err)
+					os.Exit(1)
+				}
+				fmt.Println("imported:", imported.Kid)
+			}
+		}
+
+		key, err := keyring.Rotate(dir, grace, delay, now)
+		if err != nil {
+			logger.Error("failed to rotate signing key", "error", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("next:", key.Kid)
+		fmt.Println("active from:", key.ActiveFrom.Format(time.RFC3339))
+		fmt.Println("previous key retires at:", key.ActiveFrom.Add(grace).Format(time.RFC3339))
+	},
+}
+
+var keysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the keys of the signing keyring",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.NewConfig()
+		logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+		ring, err := keyring.Load(cfg.JWT.KeyringDir, time.Now())
+		if err != nil {
+			logger.Error("failed to load keyring", "error", err)
+			os.Exit(1)
+		}
+
+		for _, key := range ring.Keys() {
+			status := "verify"
+			if key.Kid == ring.Active().Kid {
+				status = "active"
+			} else if key.ActiveFrom != nil && time.Now().Before(*key.ActiveFrom) {
+				status = "next, active from " + key.ActiveFrom.Format(time.RFC3339)
+			}
+
+			retireAt := "-"
+			if key.RetireAt != nil {
+				retireAt = key.RetireAt.Format(time.RFC3339)
+			}
+
+			fmt.Printf("%s\t%s\tcreated %s\tretires %s\n", key.Kid, status, key.CreatedAt.Format(time.RFC3339), retireAt)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(keysCmd)
+	keysCmd.AddCommand(keysRotateCmd)
+	keysCmd.AddCommand(keysListCmd)
+
+	keysRotateCmd.Flags().Duration("grace", 0, "How long the previous key keeps verifying tokens, defaults to jwt_key_retire_grace_hours")
+	keysRotateCmd.Flags().Duration("publish-delay", 0, "How long the new key only verifies tokens before it signs, defaults to jwt_keyring_reload_interval_seconds")
+}
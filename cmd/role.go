@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/infra/mailer"
+	"gostarter/infra/pgdatabase"
+	"gostarter/internals/domain"
+	"gostarter/internals/service"
+	"gostarter/internals/storage/pgstorage"
+	"gostarter/pkg/testUtils"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// cliActorId marks audit entries of changes made from the command line
+const cliActorId = 0
+
+// roleCmd represents the role command
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Manage roles and role memberships",
+}
+
+var roleCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roleService, logger := newRoleService()
+
+		permissions, _ := cmd.Flags().GetStringSlice("permission")
+
+		role, err := roleService.CreateRole(context.Background(), cliActorId, args[0], permissions)
+		if err != nil {
+			logger.Error("failed to create role", "error", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("created:", role.Name)
+	},
+}
+
+var roleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List roles with their permissions",
+	Run: func(cmd *cobra.Command, args []string) {
+		roleService, logger := newRoleService()
+
+		roles, err := roleService.ListRoles(context.Background())
+		if err != nil {
+			logger.Error("failed to list roles", "error", err)
+			os.Exit(1)
+		}
+
+		for _, role := range roles {
+			fmt.Printf("%s\t%s\n", role.Name, strings.Join(role.Permissions, ","))
+		}
+	},
+}
+
+var roleGrantCmd = &cobra.Command{
+	Use:   "grant <role>",
+	Short: "Assign a role to an account or grant a permission to a role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roleService, logger := newRoleService()
+
+		accountId, _ := cmd.Flags().GetInt("account")
+		permission, _ := cmd.Flags().GetString("permission")
+
+		var err error
+		switch {
+		case accountId > 0:
+			err = roleService.AssignRole(context.Background(), cliActorId, accountId, args[0])
+		case permission != "":
+			err = roleService.GrantPermission(context.Background(), cliActorId, args[0], permission)
+		default:
+			logger.Error("either --account or --permission is required")
+			os.Exit(1)
+		}
+
+		if err != nil {
+			logger.Error("failed to grant role", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var roleRevokeCmd = &cobra.Command{
+	Use:   "revoke <role>",
+	Short: "Remove a role from an account or revoke a permission from a role",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roleService, logger := newRoleService()
+
+		accountId, _ := cmd.Flags().GetInt("account")
+		permission, _ := cmd.Flags().GetString("permission")
+
+		var err error
+		switch {
+		case accountId > 0:
+			err = roleService.UnassignRole(context.Background(), cliActorId, accountId, args[0])
+		case permission != "":
+			err = roleService.RevokePermission(context.Background(), cliActorId, args[0], permission)
+		default:
+			logger.Error("either --account or --permission is required")
+			os.Exit(1)
+		}
+
+		if err != nil {
+			logger.Error("failed to revoke role", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func newRoleService() (domain.RoleService, *slog.Logger) {
+	cfg := config.NewConfig()
+	sqlConn := pgdatabase.NewConnection(cfg.Database.Postgres.Connection)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	tracer := testUtils.NewNoopTracer()
+
+	container := &infra.Container{
+		Cfg:    cfg,
+		Logger: logger,
+		DbConn: sqlConn,
+		Mailer: mailer.NewMailer(cfg.Mailer),
+		Tracer: tracer,
+	}
+
+	accountRepo := pgstorage.NewAccountRepository(container)
+	accountTokenRepo := pgstorage.NewAccountTokenRepository(container)
+	verificationService := service.NewVerificationService(container, accountRepo, accountTokenRepo)
+	auditService := service.NewAuditService(container, pgstorage.NewAuditRepository(container))
+	lockoutService := service.NewLockoutService(container, accountRepo, pgstorage.NewLockoutRepository(container), auditService)
+	passwordPolicyService := service.NewPasswordPolicyService(container, pgstorage.NewPasswordHistoryRepository(container))
+
+	// Sessions are revoked in the shared postgres store, the memory store lives in the server process
+	tokenService := service.NewTokenService(
+		container,
+		pgstorage.NewRefreshTokenRepository(container),
+		pgstorage.NewTokenRevocationRepository(container),
+	)
+	accountService := service.NewAccountService(container, accountRepo, tokenService, verificationService, lockoutService, passwordPolicyService, auditService)
+	// Permission changes reach running servers once their permission cache expires
+	permissionService := service.NewPermissionService(container, pgstorage.NewPermissionRepository(container))
+
+	roleService := service.NewRoleService(
+		container,
+		accountService,
+		tokenService,
+		permissionService,
+		pgstorage.NewRoleRepository(container),
+		auditService,
+	)
+
+	return roleService, logger
+}
+
+func init() {
+	rootCmd.AddCommand(roleCmd)
+	roleCmd.AddCommand(roleCreateCmd, roleListCmd, roleGrantCmd, roleRevokeCmd)
+
+	roleCreateCmd.Flags().StringSliceP("permission", "p", nil, "Permission granted to the role, can be repeated")
+
+	for _, cmd := range []*cobra.Command{roleGrantCmd, roleRevokeCmd} {
+		cmd.Flags().IntP("account", "a", 0, "Id of the account")
+		cmd.Flags().StringP("permission", "p", "", "Name of the permission")
+		cmd.MarkFlagsMutuallyExclusive("account", "permission")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"gostarter/infra"
 	"gostarter/infra/config"
 	"gostarter/infra/logging"
+	"gostarter/infra/mailer"
 	"gostarter/infra/observability"
 	"gostarter/infra/pgdatabase"
 	"gostarter/internals/delivery/http/server"
@@ -39,6 +40,7 @@ var serveCmd = &cobra.Command{
 		container := &infra.Container{
 			Cfg:    cfg,
 			DbConn: sqlConn,
+			Mailer: mailer.NewMailer(cfg.Mailer),
 			Logger: logger,
 			Tracer: tracer,
 			Meter:  meter,
@@ -0,0 +1,7 @@
+package config
+
+type AuditConfig struct {
+	// RetentionDays is how long audit events are kept, zero keeps them forever
+	RetentionDays        int `mapstructure:"retention_days"`
+	PruneIntervalMinutes int `mapstructure:"prune_interval_minutes"`
+}
@@ -0,0 +1,33 @@
+package config
+
+type AuthConfig struct {
+	// UnverifiedPolicy is one of UNVERIFIED_POLICY_ALLOW, UNVERIFIED_POLICY_BLOCK_LOGIN or UNVERIFIED_POLICY_PROFILE_ONLY
+	UnverifiedPolicy               string `mapstructure:"unverified_policy"`
+	VerificationExpirationHours    int    `mapstructure:"verification_expiration_hours"`
+	PasswordResetExpirationMinutes int    `mapstructure:"password_reset_expiration_minutes"`
+
+	// MFAIssuer is the name shown in authenticator apps
+	MFAIssuer string `mapstructure:"mfa_issuer"`
+	// MFARequiredRoles lists roles that must enroll in MFA before using the app
+	MFARequiredRoles            []string `mapstructure:"mfa_required_roles"`
+	MFAPendingExpirationMinutes int      `mapstructure:"mfa_pending_expiration_minutes"`
+
+	// Failed logins lock the identifier and the client address once they pass their threshold,
+	// each further failure doubles the lock from LockoutBaseSeconds up to LockoutMaxSeconds
+	LockoutAccountThreshold int `mapstructure:"lockout_account_threshold"`
+	LockoutIPThreshold      int `mapstructure:"lockout_ip_threshold"`
+	LockoutBaseSeconds      int `mapstructure:"lockout_base_seconds"`
+	LockoutMaxSeconds       int `mapstructure:"lockout_max_seconds"`
+	// LockoutWindowMinutes is how long failures are remembered after the last one
+	LockoutWindowMinutes int `mapstructure:"lockout_window_minutes"`
+
+	// PermissionCacheSeconds is how long the permissions of a role are cached before they are read again
+	PermissionCacheSeconds int `mapstructure:"permission_cache_seconds"`
+
+	// InvitationExpirationHours is how long an organization invite link can be accepted
+	InvitationExpirationHours int `mapstructure:"invitation_expiration_hours"`
+
+	// AccountDeletionGraceDays is how long a deleted account can be restored before it is purged
+	AccountDeletionGraceDays    int `mapstructure:"account_deletion_grace_days"`
+	AccountPurgeIntervalMinutes int `mapstructure:"account_purge_interval_minutes"`
+}
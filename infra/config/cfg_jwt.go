@@ -1,7 +1,23 @@
 package config
 
+// DEFAULT_KEYRING_RELOAD_SECONDS is how often servers read the keyring when jwt_keyring_reload_interval_seconds is not set
+const DEFAULT_KEYRING_RELOAD_SECONDS = 60
+
 type JWTConfig struct {
-	PrivateKeyPath  string `mapstructure:"jwt_private_key_path"`
-	PublicKeyPath   string `mapstructure:"jwt_public_key_path"`
-	ExpirationHours int    `mapstructure:"jwt_expiration_hours"`
+	PrivateKeyPath          string `mapstructure:"jwt_private_key_path"`
+	PublicKeyPath           string `mapstructure:"jwt_public_key_path"`
+	AccessExpirationMinutes int    `mapstructure:"jwt_access_expiration_minutes"`
+	RefreshExpirationHours  int    `mapstructure:"jwt_refresh_expiration_hours"`
+
+	// TokenPrecedence is "header" or "cookie", the source read first when both are sent
+	TokenPrecedence string `mapstructure:"jwt_token_precedence"`
+
+	// RevocationStore selects where revoked tokens are kept, "postgres" or "memory"
+	RevocationStore        string `mapstructure:"jwt_revocation_store"`
+	RevocationPruneMinutes int    `mapstructure:"jwt_revocation_prune_interval_minutes"`
+
+	// KeyringDir holds the rotated signing keys, the key pair above signs until a keyring exists
+	KeyringDir           string `mapstructure:"jwt_keyring_dir"`
+	KeyringReloadSeconds int    `mapstructure:"jwt_keyring_reload_interval_seconds"`
+	KeyRetireGraceHours  int    `mapstructure:"jwt_key_retire_grace_hours"`
 }
@@ -0,0 +1,19 @@
+package config
+
+type MailerConfig struct {
+	// Driver is "file" for dev and tests or "smtp"
+	Driver string `mapstructure:"driver"`
+	From   string `mapstructure:"from"`
+
+	// FilePath is used by the file driver, empty or "-" writes to stdout
+	FilePath string `mapstructure:"file_path"`
+
+	SMTP SMTPConfig `mapstructure:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
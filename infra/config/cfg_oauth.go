@@ -0,0 +1,8 @@
+package config
+
+// OAuthConfig configures gostarter as an OAuth2 authorization server and OIDC provider
+type OAuthConfig struct {
+	AuthorizationCodeExpirationMinutes int `mapstructure:"authorization_code_expiration_minutes"`
+	AccessTokenExpirationMinutes       int `mapstructure:"access_token_expiration_minutes"`
+	RefreshTokenExpirationHours        int `mapstructure:"refresh_token_expiration_hours"`
+}
@@ -0,0 +1,18 @@
+package config
+
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig configures an external OpenID Connect issuer used for social login.
+// The redirect uri registered with the provider is <base_url>/auth/oidc/<name>/callback.
+type OIDCProviderConfig struct {
+	Name         string   `mapstructure:"name"`
+	DisplayName  string   `mapstructure:"display_name"`
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	Scopes       []string `mapstructure:"scopes"`
+	// AutoCreate registers a new account on first login when no account matches the email
+	AutoCreate bool `mapstructure:"auto_create"`
+}
@@ -0,0 +1,18 @@
+package config
+
+type PasswordConfig struct {
+	MinLength int `mapstructure:"min_length"`
+	MaxLength int `mapstructure:"max_length"`
+
+	RequireUpper  bool `mapstructure:"require_upper"`
+	RequireLower  bool `mapstructure:"require_lower"`
+	RequireDigit  bool `mapstructure:"require_digit"`
+	RequireSymbol bool `mapstructure:"require_symbol"`
+
+	// HistoryCount is how many previous passwords cannot be reused, zero allows reuse
+	HistoryCount int `mapstructure:"history_count"`
+	// DenyIdentifiers rejects passwords containing the email or username of the account
+	DenyIdentifiers bool `mapstructure:"deny_identifiers"`
+	// BreachCorpusPath is a directory of SHA-1 range files or an ordered SHA-1 file, empty disables the check
+	BreachCorpusPath string `mapstructure:"breach_corpus_path"`
+}
@@ -1,7 +1,17 @@
 package config
 
+import "strings"
+
 type ServerConfig struct {
 	Port         string `mapstructure:"port"`
 	AllowOrigins string `mapstructure:"allow_origins"`
 	BaseURL      string `mapstructure:"base_url"`
 }
+
+// GetBaseURL returns the base url with a scheme, defaulting to http
+func (s ServerConfig) GetBaseURL() string {
+	if strings.HasPrefix(s.BaseURL, "http://") || strings.HasPrefix(s.BaseURL, "https://") {
+		return strings.TrimSuffix(s.BaseURL, "/")
+	}
+	return "http://" + strings.TrimSuffix(s.BaseURL, "/")
+}
@@ -1,7 +1,34 @@
 package config
 
 const (
-	AUTH_COOKIE_NAME = "gostarter_auth"
-	TEMPLATE_DIR     = "web/views"
-	STATIC_DIR       = "web/assets"
+	AUTH_COOKIE_NAME    = "gostarter_auth"
+	REFRESH_COOKIE_NAME = "gostarter_refresh"
+	OIDC_COOKIE_NAME    = "gostarter_oidc"
+	TEMPLATE_DIR        = "web/views"
+	STATIC_DIR          = "web/assets"
+
+	// IMPERSONATOR_COOKIE_NAME keeps the access token of the admin while the web session impersonates
+	IMPERSONATOR_COOKIE_NAME = "gostarter_impersonator"
+)
+
+const (
+	STORE_POSTGRES = "postgres"
+	STORE_MEMORY   = "memory"
+)
+
+const (
+	MAILER_DRIVER_FILE = "file"
+	MAILER_DRIVER_SMTP = "smtp"
+)
+
+const (
+	UNVERIFIED_POLICY_ALLOW        = "allow"
+	UNVERIFIED_POLICY_BLOCK_LOGIN  = "block_login"
+	UNVERIFIED_POLICY_PROFILE_ONLY = "profile_only"
+)
+
+// Where JWTMiddleware looks first when a request carries both a bearer header and the auth cookie
+const (
+	TOKEN_PRECEDENCE_HEADER = "header"
+	TOKEN_PRECEDENCE_COOKIE = "cookie"
 )
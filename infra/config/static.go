@@ -7,6 +7,12 @@ type Config struct {
 	Server        ServerConfig        `mapstructure:"server"`
 	Database      DatabaseConfig      `mapstructure:"database"`
 	JWT           JWTConfig           `mapstructure:"jwt"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	Password      PasswordConfig      `mapstructure:"password"`
+	Audit         AuditConfig         `mapstructure:"audit"`
+	Mailer        MailerConfig        `mapstructure:"mailer"`
+	OIDC          OIDCConfig          `mapstructure:"oidc"`
+	OAuth         OAuthConfig         `mapstructure:"oauth"`
 	Encryption    EncryptionConfig    `mapstructure:"encryption"`
 	Observability ObservabilityConfig `mapstructure:"observability"`
 	Vault         VaultConfig         `mapstructure:"vault"`
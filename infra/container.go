@@ -5,6 +5,7 @@ import (
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"gostarter/infra/config"
+	"gostarter/infra/mailer"
 	"log/slog"
 )
 
@@ -12,6 +13,7 @@ type Container struct {
 	Cfg *config.Config
 
 	DbConn *sql.DB
+	Mailer mailer.Mailer
 
 	Logger *slog.Logger
 	Tracer trace.Tracer
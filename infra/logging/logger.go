@@ -1,8 +1,11 @@
 package logging
 
 import (
+	"context"
 	"log/slog"
 	"os"
+
+	"go.opentelemetry.io/otel/baggage"
 )
 
 func NewFileLogger(path string) *slog.Logger {
@@ -10,5 +13,26 @@ func NewFileLogger(path string) *slog.Logger {
 	if err != nil {
 		panic(err)
 	}
-	return slog.New(slog.NewJSONHandler(file, nil))
+	return slog.New(&tagHandler{Handler: slog.NewJSONHandler(file, nil)})
+}
+
+// tagHandler adds the request wide tags of the context, set with observability.WithTag, to
+// records logged with a context
+type tagHandler struct {
+	slog.Handler
+}
+
+func (h *tagHandler) Handle(ctx context.Context, record slog.Record) error {
+	for _, member := range baggage.FromContext(ctx).Members() {
+		record.AddAttrs(slog.String(member.Key(), member.Value()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *tagHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &tagHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *tagHandler) WithGroup(name string) slog.Handler {
+	return &tagHandler{Handler: h.Handler.WithGroup(name)}
 }
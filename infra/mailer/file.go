@@ -0,0 +1,50 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileMailer writes messages to a file or stdout instead of delivering them
+type fileMailer struct {
+	mu   sync.Mutex
+	from string
+	path string
+}
+
+func NewFileMailer(from, path string) Mailer {
+	return &fileMailer{
+		from: from,
+		path: path,
+	}
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var out io.Writer = os.Stdout
+	if m.path != "" && m.path != "-" {
+		file, err := os.OpenFile(m.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	_, err := fmt.Fprintf(out,
+		"Date: %s\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC1123Z),
+		m.from,
+		strings.Join(msg.To, ", "),
+		msg.Subject,
+		msg.Body,
+	)
+	return err
+}
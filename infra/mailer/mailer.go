@@ -0,0 +1,26 @@
+package mailer
+
+import (
+	"context"
+	"gostarter/infra/config"
+)
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer returns the mailer for the configured driver, defaulting to the file driver
+func NewMailer(cfg config.MailerConfig) Mailer {
+	switch cfg.Driver {
+	case config.MAILER_DRIVER_SMTP:
+		return NewSMTPMailer(cfg.From, cfg.SMTP)
+	default:
+		return NewFileMailer(cfg.From, cfg.FilePath)
+	}
+}
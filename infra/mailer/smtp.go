@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"gostarter/infra/config"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type smtpMailer struct {
+	from string
+	addr string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(from string, cfg config.SMTPConfig) Mailer {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &smtpMailer{
+		from: from,
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		auth: auth,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	headers := []string{
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"From: " + m.from,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + msg.Subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.Join(headers, "\r\n") + "\r\n\r\n" + msg.Body
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := smtp.SendMail(m.addr, m.auth, envelopeAddress(m.from), msg.To, []byte(body))
+	if err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+// envelopeAddress extracts "user@host" from "Name <user@host>"
+func envelopeAddress(from string) string {
+	start := strings.LastIndex(from, "<")
+	end := strings.LastIndex(from, ">")
+	if start >= 0 && end > start {
+		return from[start+1 : end]
+	}
+	return from
+}
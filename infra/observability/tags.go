@@ -0,0 +1,40 @@
+package observability
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// WithTag adds a request wide tag to the context. Tags travel as baggage, every span started
+// from the context gets them as attributes and the logger adds them to records logged with it.
+func WithTag(ctx context.Context, key, value string) context.Context {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx
+	}
+
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// tagSpanProcessor copies the tags of the parent context onto each span as it starts
+type tagSpanProcessor struct{}
+
+func (tagSpanProcessor) OnStart(parent context.Context, span sdktrace.ReadWriteSpan) {
+	for _, member := range baggage.FromContext(parent).Members() {
+		span.SetAttributes(attribute.String(member.Key(), member.Value()))
+	}
+}
+
+func (tagSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (tagSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (tagSpanProcessor) ForceFlush(context.Context) error { return nil }
@@ -87,6 +87,7 @@ func newTracerProvider(cfg *config.ObservabilityConfig) (*sdktrace.TracerProvide
 	tp := sdktrace.NewTracerProvider(
 		sdktrace.WithSampler(sdktrace.AlwaysSample()),
 		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithSpanProcessor(tagSpanProcessor{}),
 		sdktrace.WithResource(
 			newResource(),
 		),
@@ -1,6 +1,9 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"gostarter/infra"
 	"gostarter/infra/config"
 	"gostarter/internals/delivery/http/helpers"
@@ -15,26 +18,42 @@ type AccountHandler struct {
 	logger *slog.Logger
 	tracer trace.Tracer
 
-	accountService domain.AccountService
-	tokenService   domain.TokenService
+	unverifiedPolicy string
+	accessExpiresIn  int
+
+	accountService      domain.AccountService
+	tokenService        domain.TokenService
+	verificationService domain.VerificationService
+	mfaService          domain.MFAService
+	exportService       domain.AccountExportService
 }
 
 func NewAccountHandler(
 	container *infra.Container,
 	accountService domain.AccountService,
 	tokenService domain.TokenService,
+	verificationService domain.VerificationService,
+	mfaService domain.MFAService,
+	exportService domain.AccountExportService,
 ) domain.AccountHandler {
 	logger := container.Logger.With("path", "AccountHandler")
 	return &AccountHandler{
-		logger:         logger,
-		tracer:         container.Tracer,
-		accountService: accountService,
-		tokenService:   tokenService,
+		logger:              logger,
+		tracer:              container.Tracer,
+		unverifiedPolicy:    container.Cfg.Auth.UnverifiedPolicy,
+		accessExpiresIn:     container.Cfg.JWT.AccessExpirationMinutes * 60,
+		accountService:      accountService,
+		tokenService:        tokenService,
+		verificationService: verificationService,
+		mfaService:          mfaService,
+		exportService:       exportService,
 	}
 }
 
 type RegisterAccountRequest struct {
-	Email    string `json:"email"`
+	Email string `json:"email"`
+	// Username is optional, it allows logging in without the email
+	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
@@ -45,12 +64,15 @@ type RegisterAccountResponse struct {
 // @Router /v1/auth/register [post]
 // @Tags Account
 // @Summary Register a new account
-// @Description Register a new account
+// @Description Register a new account. A password failing the password policy is rejected with every violated rule.
+// @Description The optional username has 3 to 32 letters, digits, dots, dashes or underscores and starts with a letter
+// @Description or digit. Emails and usernames are unique regardless of case, taken ones are answered with a 409.
 // @Accept json
 // @Produce json
 // @Param account body RegisterAccountRequest true "Account to register"
 // @Success 200 {object} RegisterAccountResponse
-// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.PasswordPolicyResponse
+// @Failure 409 {object} helpers.GeneralResponse
 // @Failure 500 {object} helpers.GeneralResponse
 func (a *AccountHandler) Register(w http.ResponseWriter, r *http.Request) {
 	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.Register")
@@ -70,7 +92,7 @@ func (a *AccountHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	acc := &domain.Account{
-		Username: req.Email,
+		Username: req.Username,
 		Email:    req.Email,
 		Password: req.Password,
 		Roles:    []string{domain.ROLE_USER},
@@ -78,6 +100,9 @@ func (a *AccountHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	// Register account
 	err = a.accountService.Register(ctx, acc)
+	if helpers.WritePasswordPolicyError(w, err) || helpers.WriteIdentifierError(w, err) {
+		return
+	}
 	if err != nil {
 		errorResponse := helpers.GeneralResponse{
 			Message: "failed to register account",
@@ -89,8 +114,17 @@ func (a *AccountHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Unverified accounts cannot login until the email is confirmed
+	if !acc.IsEmailVerified() && a.unverifiedPolicy == config.UNVERIFIED_POLICY_BLOCK_LOGIN {
+		resp := RegisterAccountResponse{
+			Message: "account registered, verify your email to login",
+		}
+		_ = helpers.WriteResponse(w, http.StatusOK, resp)
+		return
+	}
+
 	// Generate JWT
-	token, err := a.tokenService.GenerateJWT(acc.Id, acc.Email, acc.Roles)
+	token, err := a.tokenService.GenerateJWT(acc)
 	if err != nil {
 		errorResponse := helpers.GeneralResponse{
 			Message: "failed to generate token",
@@ -102,14 +136,21 @@ func (a *AccountHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set token in http only cookie
-	http.SetCookie(w, &http.Cookie{
-		Path:     "/",
-		Name:     config.AUTH_COOKIE_NAME,
-		Value:    token,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	// Generate refresh token
+	refreshToken, err := a.tokenService.GenerateRefreshToken(ctx, acc.Id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to generate token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Set tokens in http only cookies
+	helpers.SetAuthCookies(w, token, refreshToken)
 
 	// Response
 	resp := RegisterAccountResponse{
@@ -120,19 +161,56 @@ func (a *AccountHandler) Register(w http.ResponseWriter, r *http.Request) {
 }
 
 type LoginRequest struct {
+	// Email or Username identifies the account, the email is used when both are sent
 	Email    string `json:"email"`
+	Username string `json:"username"`
 	Password string `json:"password"`
+	// ReturnTokens returns the tokens in the body instead of cookies, for clients sending them as a bearer header
+	ReturnTokens bool `json:"return_tokens"`
+}
+
+// TokenResponse carries the session tokens for clients that cannot use cookies
+type TokenResponse struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	TokenType    string `json:"token_type,omitempty"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+}
+
+func newTokenResponse(token, refreshToken string, expiresIn int) *TokenResponse {
+	return &TokenResponse{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    expiresIn,
+	}
+}
+
+type LoginResponse struct {
+	Message     string `json:"message"`
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+	*TokenResponse
 }
 
 // @Router /v1/auth/login [post]
 // @Tags Account
 // @Summary Login an account
-// @Description Login an account
+// @Description Login an account. When MFA is enabled no session is started, the returned mfa_token
+// @Description must be sent with a code to /v1/auth/mfa/verify instead. With return_tokens the
+// @Description tokens are returned in the body instead of cookies, to be sent as `Authorization: Bearer`.
+// @Description The account is identified by its email or username, both matched regardless of case.
+// @Description Unknown accounts and wrong passwords get the same 401, repeated failures for the account or
+// @Description from the client address are locked with a 429 and a Retry-After header.
+// @Description Disabled accounts get a 403 once the password is right.
 // @Accept json
 // @Produce json
 // @Param account body LoginRequest true "Login Details"
-// @Success 200 {object} helpers.GeneralResponse
+// @Success 200 {object} LoginResponse
 // @Failure 400 {object} helpers.GeneralResponse
+// @Failure 401 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 429 {object} helpers.GeneralResponse
 // @Failure 500 {object} helpers.GeneralResponse
 func (a *AccountHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.Login")
@@ -151,21 +229,93 @@ func (a *AccountHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	identifier := req.Email
+	if identifier == "" {
+		identifier = req.Username
+	}
+
 	// Authenticate account
-	acc, err := a.accountService.Authenticate(ctx, req.Email, req.Password)
-	if err != nil {
+	acc, err := a.accountService.Authenticate(ctx, identifier, req.Password, helpers.GetClientIP(r))
+	if helpers.WriteLockout(w, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidCredentials) {
 		errorResponse := helpers.GeneralResponse{
 			Message: "invalid credentials",
 			Errors: []string{
 				err.Error(),
 			},
 		}
+		_ = helpers.WriteResponse(w, http.StatusUnauthorized, errorResponse)
+		return
+	}
+	if errors.Is(err, domain.ErrAccountDisabled) {
+		errorResponse := helpers.GeneralResponse{
+			Message: "account disabled",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusForbidden, errorResponse)
+		return
+	}
+	if errors.Is(err, domain.ErrEmailNotVerified) {
+		errorResponse := helpers.GeneralResponse{
+			Message: "email not verified",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusForbidden, errorResponse)
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to login",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Accounts with MFA get a pending token instead of a session
+	mfaEnabled, err := a.mfaService.IsEnabled(ctx, acc.Id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to check mfa",
+			Errors: []string{
+				err.Error(),
+			},
+		}
 		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
 		return
 	}
+	if mfaEnabled {
+		mfaToken, err := a.tokenService.GenerateMFAPendingToken(acc)
+		if err != nil {
+			errorResponse := helpers.GeneralResponse{
+				Message: "failed to generate token",
+				Errors: []string{
+					err.Error(),
+				},
+			}
+			_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+			return
+		}
+
+		resp := LoginResponse{
+			Message:     "mfa required",
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}
+		_ = helpers.WriteResponse(w, http.StatusOK, resp)
+		return
+	}
 
 	// Generate JWT
-	token, err := a.tokenService.GenerateJWT(acc.Id, acc.Email, acc.Roles)
+	token, err := a.tokenService.GenerateJWT(acc)
 	if err != nil {
 		errorResponse := helpers.GeneralResponse{
 			Message: "failed to generate token",
@@ -177,43 +327,86 @@ func (a *AccountHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Set token in http only cookie
-	http.SetCookie(w, &http.Cookie{
-		Path:     "/",
-		Name:     config.AUTH_COOKIE_NAME,
-		Value:    token,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	// Generate refresh token
+	refreshToken, err := a.tokenService.GenerateRefreshToken(ctx, acc.Id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to generate token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
 
 	// Response
-	resp := helpers.GeneralResponse{
+	resp := LoginResponse{
 		Message: "login successful",
 	}
 
+	if req.ReturnTokens {
+		resp.TokenResponse = newTokenResponse(token, refreshToken, a.accessExpiresIn)
+	} else {
+		// Set tokens in http only cookies
+		helpers.SetAuthCookies(w, token, refreshToken)
+	}
+
 	_ = helpers.WriteResponse(w, http.StatusOK, resp)
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// refreshTokenFromRequest reads the refresh token cookie, or the body of clients that keep the tokens themselves
+func refreshTokenFromRequest(r *http.Request) (string, bool) {
+	if refreshToken := helpers.GetCookieValue(r, config.REFRESH_COOKIE_NAME); refreshToken != "" {
+		return refreshToken, false
+	}
+
+	req, err := helpers.ParseRequest[RefreshTokenRequest](r.Body)
+	if err != nil {
+		return "", false
+	}
+	return req.RefreshToken, req.RefreshToken != ""
+}
+
 // @Router /v1/auth/logout [post]
 // @Tags Account
 // @Summary Logout an account
-// @Description Logout an account
+// @Description Logout an account, revoking the current access and refresh tokens. Clients without
+// @Description cookies send the refresh token in the body.
 // @Accept json
 // @Produce json
+// @Param request body RefreshTokenRequest false "Refresh token when not sent as a cookie"
 // @Success 200 {object} helpers.GeneralResponse
 // @Failure 500 {object} helpers.GeneralResponse
 func (a *AccountHandler) Logout(w http.ResponseWriter, r *http.Request) {
-	_, span := a.tracer.Start(r.Context(), "AccountHandler.Logout")
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.Logout")
 	defer span.End()
 
-	// Set token in http only cookie
-	http.SetCookie(w, &http.Cookie{
-		Path:     "/",
-		Name:     config.AUTH_COOKIE_NAME,
-		Value:    "",
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	refreshToken, _ := refreshTokenFromRequest(r)
+
+	// Revoke tokens of the current session
+	err := a.tokenService.RevokeSession(
+		ctx,
+		helpers.GetAccessTokenFromContext(ctx),
+		refreshToken,
+	)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to logout",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Clear token cookies
+	helpers.ClearAuthCookies(w)
 
 	// Response
 	resp := helpers.GeneralResponse{
@@ -223,10 +416,162 @@ func (a *AccountHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	_ = helpers.WriteResponse(w, http.StatusOK, resp)
 }
 
+// @Router /v1/auth/logout-all [post]
+// @Tags Account
+// @Summary Logout an account everywhere
+// @Description Revoke every access and refresh token issued to the account, refused to impersonation sessions
+// @Accept json
+// @Produce json
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.LogoutAll")
+	defer span.End()
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Revoke all sessions
+	err = a.tokenService.RevokeAllSessions(ctx, acc.Id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrImpersonationForbidden) {
+			status = http.StatusForbidden
+		}
+
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to logout",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, status, errorResponse)
+		return
+	}
+
+	// Clear token cookies
+	helpers.ClearAuthCookies(w)
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "logged out from all sessions",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/auth/refresh [post]
+// @Tags Account
+// @Summary Refresh the access token
+// @Description Exchange the refresh token cookie for a new access and refresh token pair. A refresh
+// @Description token sent in the body is answered with the new tokens in the body.
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest false "Refresh token when not sent as a cookie"
+// @Success 200 {object} LoginResponse
+// @Failure 401 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.Refresh")
+	defer span.End()
+
+	presentedToken, fromBody := refreshTokenFromRequest(r)
+	if presentedToken == "" {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid refresh token",
+			Errors: []string{
+				"refresh token not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusUnauthorized, errorResponse)
+		return
+	}
+
+	// Rotate refresh token
+	accountId, refreshToken, err := a.tokenService.RotateRefreshToken(ctx, presentedToken)
+	if err != nil {
+		helpers.ClearAuthCookies(w)
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid refresh token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusUnauthorized, errorResponse)
+		return
+	}
+
+	acc, err := a.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		helpers.ClearAuthCookies(w)
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusUnauthorized, errorResponse)
+		return
+	}
+
+	// Refresh tokens of MFA accounts are only issued after the second factor
+	acc.MFAVerified, err = a.mfaService.IsEnabled(ctx, acc.Id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to check mfa",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Generate JWT
+	token, err := a.tokenService.GenerateJWT(acc)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to generate token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Response
+	resp := LoginResponse{
+		Message: "token refreshed",
+	}
+
+	if fromBody {
+		resp.TokenResponse = newTokenResponse(token, refreshToken, a.accessExpiresIn)
+	} else {
+		// Set tokens in http only cookies
+		helpers.SetAuthCookies(w, token, refreshToken)
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
 type ProfileResponse struct {
-	ID    int      `json:"id"`
-	Email string   `json:"email"`
-	Roles []string `json:"roles"`
+	ID            int      `json:"id"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	MFAVerified   bool     `json:"mfa_verified"`
+	Roles         []string `json:"roles"`
 }
 
 // @Router /v1/auth/profile [get]
@@ -256,10 +601,93 @@ func (a *AccountHandler) Profile(w http.ResponseWriter, r *http.Request) {
 
 	// Response
 	resp := ProfileResponse{
-		ID:    acc.Id,
-		Email: acc.Email,
-		Roles: acc.Roles,
+		ID:            acc.Id,
+		Email:         acc.Email,
+		EmailVerified: acc.IsEmailVerified(),
+		MFAVerified:   acc.MFAVerified,
+		Roles:         acc.Roles,
 	}
 
 	_ = helpers.WriteResponse(w, http.StatusOK, resp)
 }
+
+// @Router /v1/account/export [get]
+// @Tags Account
+// @Summary Download my data
+// @Description Download the data kept about the account: the profile, roles, sessions and audit events.
+// @Description Sent as a ZIP archive with a JSON file per section, or as a single JSON document with format=json.
+// @Description Refused to impersonation sessions.
+// @Produce application/zip
+// @Produce json
+// @Param format query string false "zip or json, zip by default"
+// @Success 200 {object} domain.AccountExport
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.Export")
+	defer span.End()
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "zip"
+	}
+	if format != "zip" && format != "json" {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"format must be zip or json",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	export, err := a.exportService.Export(ctx, acc.Id)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, domain.ErrImpersonationForbidden) {
+			status = http.StatusForbidden
+		}
+
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to export account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, status, errorResponse)
+		return
+	}
+
+	filename := fmt.Sprintf("account-%d-export.%s", acc.Id, format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(export)
+	} else {
+		w.Header().Set("Content-Type", "application/zip")
+		w.WriteHeader(http.StatusOK)
+		err = a.exportService.WriteArchive(export, w)
+	}
+
+	// The status is sent, a failure part way only ends the download early
+	if err != nil {
+		a.logger.Error("failed to write account export", "error", err)
+	}
+}
@@ -0,0 +1,405 @@
+package api
+
+import (
+	"errors"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"net/http"
+	"time"
+)
+
+// accountErrorStatus maps the errors of self service account changes, invalid input and
+// lockouts are answered by the helpers before
+func accountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAccountTokenNotFound),
+		errors.Is(err, domain.ErrAccountTokenExpired),
+		errors.Is(err, domain.ErrAccountTokenUsed):
+		return http.StatusBadRequest
+	// A wrong current password is not a 401, clients would take it for an expired session
+	case errors.Is(err, domain.ErrInvalidCredentials),
+		errors.Is(err, domain.ErrImpersonationForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type AccountResponse struct {
+	ID            int        `json:"id"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"email_verified"`
+	Roles         []string   `json:"roles"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`
+}
+
+func newAccountResponse(acc *domain.Account) *AccountResponse {
+	return &AccountResponse{
+		ID:            acc.Id,
+		Username:      acc.Username,
+		Email:         acc.Email,
+		EmailVerified: acc.IsEmailVerified(),
+		Roles:         acc.Roles,
+		CreatedAt:     acc.CreatedAt,
+		UpdatedAt:     acc.UpdatedAt,
+		DeletedAt:     acc.DeletedAt,
+		DisabledAt:    acc.DisabledAt,
+	}
+}
+
+type UpdateAccountRequest struct {
+	// Username is left unchanged when missing and removed when empty
+	Username *string `json:"username"`
+}
+
+// @Router /v1/account [patch]
+// @Tags Account
+// @Summary Update my profile
+// @Description Change the profile fields of the account, missing fields are left unchanged. An empty username removes it,
+// @Description the account then logs in with its email. The email is changed through /v1/account/email.
+// @Accept json
+// @Produce json
+// @Param account body UpdateAccountRequest true "Profile fields"
+// @Success 200 {object} AccountResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.UpdateAccount")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[UpdateAccountRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	current, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// The session only carries part of the account
+	acc, err := a.accountService.GetAccountByID(ctx, current.Id)
+	if err == nil {
+		if req.Username != nil {
+			acc.Username = *req.Username
+		}
+		err = a.accountService.UpdateAccount(ctx, acc)
+	}
+	if helpers.WriteIdentifierError(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to update account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, accountErrorStatus(err), errorResponse)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, newAccountResponse(acc))
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// @Router /v1/account/password [post]
+// @Tags Account
+// @Summary Change my password
+// @Description Set a new password after checking the current one. Every session of the account ends, including this one,
+// @Description so the client logs in again. Wrong current passwords count towards the login lockout. Refused to impersonation sessions.
+// @Accept json
+// @Produce json
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.PasswordPolicyResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 429 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.ChangePassword")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[ChangePasswordRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = a.accountService.ChangePassword(ctx, acc.Id, req.CurrentPassword, req.NewPassword)
+	if helpers.WriteLockout(w, err) || helpers.WritePasswordPolicyError(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to change password",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, accountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// The session was revoked with the others
+	helpers.ClearAuthCookies(w)
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "password changed, login with the new password",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// @Router /v1/account/email [post]
+// @Tags Account
+// @Summary Change my email
+// @Description Request a change of the account email. The password is checked and a confirmation link is mailed to the new
+// @Description address, the current address gets a notice. The email changes once the link is confirmed through
+// @Description /v1/account/email/confirm. Refused to impersonation sessions.
+// @Accept json
+// @Produce json
+// @Param request body ChangeEmailRequest true "New email and current password"
+// @Success 202 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 429 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.ChangeEmail")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[ChangeEmailRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = a.accountService.RequestEmailChange(ctx, acc.Id, req.Password, req.Email)
+	if helpers.WriteLockout(w, err) || helpers.WriteIdentifierError(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to change email",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, accountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "confirm the change with the link sent to the new address",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusAccepted, resp)
+}
+
+// @Router /v1/account/email/confirm [post]
+// @Tags Account
+// @Summary Confirm an email change
+// @Description Apply an email change with the token mailed to the new address, the address counts as verified.
+// @Description Access tokens issued before carry the old address and are revoked, refresh them to continue.
+// @Accept json
+// @Produce json
+// @Param token body VerifyEmailRequest true "Email change token"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.ConfirmEmailChange")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[VerifyEmailRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	_, err = a.accountService.ConfirmEmailChange(ctx, req.Token)
+	if helpers.WriteIdentifierError(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid email change token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, accountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "email changed",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// @Router /v1/account [delete]
+// @Tags Account
+// @Summary Delete my account
+// @Description Delete the account after checking the password, every session ends. The account can be restored by an
+// @Description admin until the grace period has passed, then it is purged with its data. Accounts created through an
+// @Description identity provider set a password with a password reset first. Refused to impersonation sessions.
+// @Accept json
+// @Produce json
+// @Param request body DeleteAccountRequest true "Current password"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 429 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.DeleteAccount")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[DeleteAccountRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// The admin of an impersonation session does not know the password, DeleteAccount refuses it as well
+	err = domain.ForbidImpersonation(ctx)
+	if err == nil {
+		err = a.accountService.VerifyPassword(ctx, acc.Id, req.Password)
+	}
+	if err == nil {
+		err = a.accountService.DeleteAccount(ctx, acc.Id)
+	}
+	if helpers.WriteLockout(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to delete account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, accountErrorStatus(err), errorResponse)
+		return
+	}
+
+	helpers.ClearAuthCookies(w)
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "account deleted",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"gostarter/internals/delivery/http/helpers"
+	"net/http"
+)
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// @Router /v1/auth/verify-email [post]
+// @Tags Account
+// @Summary Verify an email address
+// @Description Confirm the email address with the token sent by mail
+// @Accept json
+// @Produce json
+// @Param token body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+func (a *AccountHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.VerifyEmail")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[VerifyEmailRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	_, err = a.verificationService.VerifyEmail(ctx, req.Token)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid verification token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "email verified",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+// @Router /v1/auth/verify-email/resend [post]
+// @Tags Account
+// @Summary Resend the verification email
+// @Description Send a new verification link. The response is the same whether or not the email is registered.
+// @Accept json
+// @Produce json
+// @Param email body ResendVerificationRequest true "Account email"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+func (a *AccountHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "AccountHandler.ResendVerification")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[ResendVerificationRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := a.accountService.GetAccountByEmail(ctx, req.Email)
+	if err == nil && !acc.IsEmailVerified() {
+		err = a.verificationService.SendVerificationEmail(ctx, acc)
+		if err != nil {
+			a.logger.Error("failed to resend verification email", "error", err)
+		}
+	}
+
+	// Response does not reveal whether the account exists
+	resp := helpers.GeneralResponse{
+		Message: "if the account exists and is unverified, a verification email has been sent",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,730 @@
+package api
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxAccountPageSize bounds the page size of account listings and searches
+const maxAccountPageSize = 100
+
+// defaultAccountSearchLimit is the number of search results when no limit is given
+const defaultAccountSearchLimit = 20
+
+type AdminAccountHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accountService domain.AccountService
+	roleService    domain.RoleService
+}
+
+func NewAdminAccountHandler(
+	container *infra.Container,
+	accountService domain.AccountService,
+	roleService domain.RoleService,
+) domain.AdminAccountHandler {
+	logger := container.Logger.With("path", "AdminAccountHandler")
+	return &AdminAccountHandler{
+		logger:         logger,
+		tracer:         container.Tracer,
+		accountService: accountService,
+		roleService:    roleService,
+	}
+}
+
+// adminAccountErrorStatus maps the errors of account administration, invalid and taken
+// identifiers are answered by the helpers before
+func adminAccountErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidCursorPagination),
+		errors.Is(err, domain.ErrInvalidAccountStatus),
+		errors.Is(err, domain.ErrInvalidAccountSort),
+		errors.Is(err, domain.ErrInvalidSearchQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrAccountNotFound),
+		errors.Is(err, domain.ErrRoleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrAccountDisabled),
+		errors.Is(err, domain.ErrAccountNotDisabled),
+		errors.Is(err, domain.ErrOwnAccount):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrImpersonationForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type ListAccountsResponse struct {
+	Accounts []*AccountResponse `json:"accounts"`
+	// Pagination is set for listings by page and limit
+	Pagination *domain.Pagination `json:"pagination,omitempty"`
+	// PageInfo is set for listings by cursor, TotalCount only when it was asked for
+	PageInfo   *domain.ConnectionPageInfo `json:"page_info,omitempty"`
+	TotalCount *int                       `json:"total_count,omitempty"`
+}
+
+type AccountSearchResultResponse struct {
+	Account *AccountResponse `json:"account"`
+	// Rank orders the results, it cannot be compared across searches
+	Rank float64 `json:"rank"`
+	// Highlights are HTML escaped with the matches in <mark> tags
+	UsernameHighlight string `json:"username_highlight"`
+	EmailHighlight    string `json:"email_highlight"`
+}
+
+type SearchAccountsResponse struct {
+	Results []*AccountSearchResultResponse `json:"results"`
+}
+
+type AdminUpdateAccountRequest struct {
+	// Username is left unchanged when missing and removed when empty
+	Username *string `json:"username"`
+	// Email is changed right away, without a confirmation mail
+	Email         *string `json:"email"`
+	EmailVerified *bool   `json:"email_verified"`
+}
+
+type SetRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+// accountIdParam reads the account id of the path, answering a 400 when it is not one
+func accountIdParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"invalid account id",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return 0, false
+	}
+	return id, true
+}
+
+// parseAccountFilter reads the filter and sort query parameters, times are RFC 3339
+func parseAccountFilter(query url.Values) (*domain.AccountFilter, []domain.AccountSort, error) {
+	filter := &domain.AccountFilter{
+		Status:         query.Get("status"),
+		Role:           query.Get("role"),
+		EmailPrefix:    query.Get("emailPrefix"),
+		UsernamePrefix: query.Get("usernamePrefix"),
+		Query:          query.Get("q"),
+	}
+
+	times := map[string]**time.Time{
+		"createdFrom": &filter.CreatedFrom,
+		"createdTo":   &filter.CreatedTo,
+		"updatedFrom": &filter.UpdatedFrom,
+		"updatedTo":   &filter.UpdatedTo,
+	}
+	for name, t := range times {
+		parsed, err := helpers.ParseFilterTime(name, query.Get(name))
+		if err != nil {
+			return nil, nil, err
+		}
+		*t = parsed
+	}
+
+	err := filter.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort, err := domain.ParseAccountSort(query.Get("sort"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return filter, sort, nil
+}
+
+// @Router /v1/admin/accounts [get]
+// @Tags Admin
+// @Summary List accounts
+// @Description List the accounts that are not deleted, by id unless sorted otherwise. The first, prev, next and last pages
+// @Description are sent in a Link header and the total in X-Total-Count. Large listings page faster by cursor: first with the end_cursor of a page as
+// @Description after reads forward, last with the start_cursor as before reads backward. The total of cursor listings is only
+// @Description counted with total=true. Cursors only read on in the sort they were made for.
+// @Description Requires the admin role and the accounts:read permission.
+// @Accept json
+// @Produce json
+// @Param status query string false "Status" Enums(active, disabled, unverified, deleted)
+// @Param role query string false "Role the accounts have"
+// @Param createdFrom query string false "RFC 3339 time, inclusive"
+// @Param createdTo query string false "RFC 3339 time, exclusive"
+// @Param updatedFrom query string false "RFC 3339 time, inclusive"
+// @Param updatedTo query string false "RFC 3339 time, exclusive"
+// @Param emailPrefix query string false "Start of the email, regardless of case"
+// @Param usernamePrefix query string false "Start of the username, regardless of case"
+// @Param q query string false "Text in the email or username, regardless of case"
+// @Param sort query string false "Comma separated id, email, username, created_at or updated_at, descending with a - prefix, like -created_at,email"
+// @Param page query int false "Page, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @Param first query int false "Page size reading forward, at most 100"
+// @Param after query string false "Cursor to read forward from"
+// @Param last query int false "Page size reading backward, at most 100"
+// @Param before query string false "Cursor to read backward from"
+// @Param total query bool false "Count the total of a cursor listing"
+// @Success 200 {object} ListAccountsResponse
+// @Header 200 {int} X-Total-Count "Number of accounts"
+// @Header 200 {string} Link "Links to the first, prev, next and last pages"
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.List")
+	defer span.End()
+
+	filter, sort, err := parseAccountFilter(r.URL.Query())
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	cursorPagination, byCursor, err := helpers.GetCursorPaginationParams(r)
+	if err == nil && byCursor && cursorPagination.Limit() > maxAccountPageSize {
+		err = errors.New("first and last must be at most 100")
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+	if byCursor {
+		h.listByCursor(w, r, filter, sort, &cursorPagination)
+		return
+	}
+
+	params := helpers.GetPaginationParams(r)
+	if params.Page < 1 || params.Size < 1 || params.Size > maxAccountPageSize {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"page must be positive and limit between 1 and 100",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	pagination := domain.Pagination{
+		Page: params.Page,
+		Size: params.Size,
+	}
+
+	accounts, err := h.accountService.ListAccounts(ctx, filter, sort, &pagination)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to list accounts",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := ListAccountsResponse{
+		Accounts:   make([]*AccountResponse, 0, len(accounts)),
+		Pagination: &pagination,
+	}
+	for _, acc := range accounts {
+		resp.Accounts = append(resp.Accounts, newAccountResponse(acc))
+	}
+
+	helpers.WritePaginationHeaders(w, r, &pagination)
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+func (h *AdminAccountHandler) listByCursor(
+	w http.ResponseWriter,
+	r *http.Request,
+	filter *domain.AccountFilter,
+	sort []domain.AccountSort,
+	pagination *domain.CursorPagination,
+) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.listByCursor")
+	defer span.End()
+
+	connection, err := h.accountService.ListAccountsByCursor(ctx, filter, sort, pagination)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to list accounts",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := ListAccountsResponse{
+		Accounts:   make([]*AccountResponse, 0, len(connection.Edges)),
+		PageInfo:   connection.PageInfo,
+		TotalCount: connection.TotalCount,
+	}
+	for _, edge := range connection.Edges {
+		resp.Accounts = append(resp.Accounts, newAccountResponse(edge.Node))
+	}
+
+	helpers.WriteCursorPaginationHeaders(w, r, connection.PageInfo, pagination.Limit(), connection.TotalCount)
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/accounts/{id} [get]
+// @Tags Admin
+// @Summary Get an account
+// @Description Get an account that is not deleted. Requires the admin role and the accounts:read permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Success 200 {object} AccountResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.Get")
+	defer span.End()
+
+	id, ok := accountIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	acc, err := h.accountService.GetAccountByID(ctx, id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to get account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, newAccountResponse(acc))
+}
+
+// @Router /v1/admin/accounts/{id} [patch]
+// @Tags Admin
+// @Summary Update an account
+// @Description Change the username, email or email verification of an account, missing fields are left unchanged.
+// @Description The email is changed without a confirmation mail and the access tokens of the account are revoked.
+// @Description Requires the admin role and the accounts:write permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Param account body AdminUpdateAccountRequest true "Account fields"
+// @Success 200 {object} AccountResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.Update")
+	defer span.End()
+
+	id, ok := accountIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse request
+	req, err := helpers.ParseRequest[AdminUpdateAccountRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := h.accountService.GetAccountByID(ctx, id)
+	if err == nil {
+		if req.Username != nil {
+			acc.Username = *req.Username
+		}
+		if req.Email != nil {
+			acc.Email = *req.Email
+		}
+		if req.EmailVerified != nil && *req.EmailVerified != acc.IsEmailVerified() {
+			acc.EmailVerifiedAt = nil
+			if *req.EmailVerified {
+				now := time.Now()
+				acc.EmailVerifiedAt = &now
+			}
+		}
+		err = h.accountService.UpdateAccount(ctx, acc)
+	}
+	if helpers.WriteIdentifierError(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to update account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, newAccountResponse(acc))
+}
+
+// @Router /v1/admin/accounts/{id}/disable [post]
+// @Tags Admin
+// @Summary Disable an account
+// @Description Disable an account, every session ends and logins are refused until it is enabled. Its API keys stop
+// @Description working and OAuth clients get no new tokens. Admins cannot disable their own account.
+// @Description Requires the admin role and the accounts:write permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.Disable")
+	defer span.End()
+
+	id, ok := accountIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// An admin locking itself out would need the command line to get back in
+	err = domain.ErrOwnAccount
+	if acc.Id != id {
+		err = h.accountService.DisableAccount(ctx, id)
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to disable account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "account disabled",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/accounts/{id}/enable [post]
+// @Tags Admin
+// @Summary Enable an account
+// @Description Enable a disabled account, it can log in again. Requires the admin role and the accounts:write permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.Enable")
+	defer span.End()
+
+	id, ok := accountIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.accountService.EnableAccount(ctx, id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to enable account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "account enabled",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/accounts/{id}/roles [put]
+// @Tags Admin
+// @Summary Set the roles of an account
+// @Description Assign and remove roles so the account has exactly the given ones. Its access tokens are revoked, so existing
+// @Description sessions get the new roles on their next refresh. Requires the admin role and the roles:manage permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Param request body SetRolesRequest true "Role names"
+// @Success 200 {object} AccountResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) SetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.SetRoles")
+	defer span.End()
+
+	id, ok := accountIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	// Parse request
+	req, err := helpers.ParseRequest[SetRolesRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = h.roleService.SetRoles(ctx, acc.Id, id, req.Roles)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to set roles",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	updated, err := h.accountService.GetAccountByID(ctx, id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to get account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, newAccountResponse(updated))
+}
+
+// @Router /v1/admin/accounts/{id} [delete]
+// @Tags Admin
+// @Summary Delete an account
+// @Description Delete an account, every session ends. It is purged once the grace period has passed, or right away with
+// @Description purge=true. Admins cannot delete their own account here. Requires the admin role and the accounts:write permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Param purge query bool false "Erase the account and its data right away"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.Delete")
+	defer span.End()
+
+	id, ok := accountIdParam(w, r)
+	if !ok {
+		return
+	}
+
+	purge := false
+	if value := r.URL.Query().Get("purge"); value != "" {
+		var err error
+		purge, err = strconv.ParseBool(value)
+		if err != nil {
+			errorResponse := helpers.GeneralResponse{
+				Message: "invalid request",
+				Errors: []string{
+					"invalid purge",
+				},
+			}
+			_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+			return
+		}
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Admins delete their own account through /v1/account, which checks the password
+	switch {
+	case acc.Id == id:
+		err = domain.ErrOwnAccount
+	case purge:
+		err = h.accountService.PurgeAccount(ctx, id)
+	default:
+		err = h.accountService.DeleteAccount(ctx, id)
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to delete account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "account deleted",
+	}
+	if purge {
+		resp.Message = "account purged"
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/accounts/search [get]
+// @Tags Admin
+// @Summary Search accounts
+// @Description Search the accounts that are not deleted, best matches first. Words of the query match the words of usernames
+// @Description and emails by prefix, misspelled words by similarity. Requires the admin role and the accounts:read permission.
+// @Accept json
+// @Produce json
+// @Param q query string true "Words to search for"
+// @Param limit query int false "Number of results, at most 100, 20 by default"
+// @Success 200 {object} SearchAccountsResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AdminAccountHandler) Search(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AdminAccountHandler.Search")
+	defer span.End()
+
+	limit := defaultAccountSearchLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxAccountPageSize {
+			errorResponse := helpers.GeneralResponse{
+				Message: "invalid request",
+				Errors: []string{
+					"limit must be between 1 and 100",
+				},
+			}
+			_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+			return
+		}
+	}
+
+	results, err := h.accountService.SearchAccounts(ctx, r.URL.Query().Get("q"), limit)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to search accounts",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, adminAccountErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := SearchAccountsResponse{
+		Results: make([]*AccountSearchResultResponse, 0, len(results)),
+	}
+	for _, result := range results {
+		resp.Results = append(resp.Results, &AccountSearchResultResponse{
+			Account:           newAccountResponse(result.Account),
+			Rank:              result.Rank,
+			UsernameHighlight: result.UsernameHighlight,
+			EmailHighlight:    result.EmailHighlight,
+		})
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type APIKeyHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	apiKeyService domain.APIKeyService
+}
+
+func NewAPIKeyHandler(
+	container *infra.Container,
+	apiKeyService domain.APIKeyService,
+) domain.APIKeyHandler {
+	logger := container.Logger.With("path", "APIKeyHandler")
+	return &APIKeyHandler{
+		logger:        logger,
+		tracer:        container.Tracer,
+		apiKeyService: apiKeyService,
+	}
+}
+
+func apiKeyErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrAPIKeyNameRequired),
+		errors.Is(err, domain.ErrAPIKeyExpired):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrAPIKeyInvalidScope),
+		errors.Is(err, domain.ErrImpersonationForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays is optional, keys without it do not expire
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+type CreateAPIKeyResponse struct {
+	Message string         `json:"message"`
+	Token   string         `json:"token"`
+	APIKey  *domain.APIKey `json:"api_key"`
+}
+
+type ListAPIKeysResponse struct {
+	APIKeys []*domain.APIKey `json:"api_keys"`
+}
+
+// @Router /v1/auth/api-keys [post]
+// @Tags API Keys
+// @Summary Create a personal access token
+// @Description Create an API key for scripts and CI jobs, sent as `Authorization: Bearer <token>`. Scopes are roles of the account, all roles of the session when empty. The token is only shown in this response.
+// @Accept json
+// @Produce json
+// @Param request body CreateAPIKeyRequest true "Name, scopes and expiry"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "APIKeyHandler.Create")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[CreateAPIKeyRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays > 0 {
+		expiry := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &expiry
+	}
+
+	key, token, err := a.apiKeyService.Create(ctx, acc, req.Name, req.Scopes, expiresAt)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to create api key",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, apiKeyErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := CreateAPIKeyResponse{
+		Message: "api key created",
+		Token:   token,
+		APIKey:  key,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusCreated, resp)
+}
+
+// @Router /v1/auth/api-keys [get]
+// @Tags API Keys
+// @Summary List personal access tokens
+// @Description List the API keys of the account with their scopes, expiry and last use
+// @Accept json
+// @Produce json
+// @Success 200 {object} ListAPIKeysResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "APIKeyHandler.List")
+	defer span.End()
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	keys, err := a.apiKeyService.List(ctx, acc.Id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to list api keys",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, ListAPIKeysResponse{APIKeys: keys})
+}
+
+// @Router /v1/auth/api-keys/{id} [delete]
+// @Tags API Keys
+// @Summary Revoke a personal access token
+// @Description Delete an API key of the account, requests made with it are rejected from then on
+// @Accept json
+// @Produce json
+// @Param id path int true "API key id"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "APIKeyHandler.Revoke")
+	defer span.End()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"invalid api key id",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = a.apiKeyService.Revoke(ctx, acc.Id, id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to revoke api key",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, apiKeyErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "api key revoked",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxAuditPageSize bounds the page size of audit queries
+const maxAuditPageSize = 100
+
+type AuditHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	auditService domain.AuditService
+}
+
+func NewAuditHandler(
+	container *infra.Container,
+	auditService domain.AuditService,
+) domain.AuditHandler {
+	logger := container.Logger.With("path", "AuditHandler")
+	return &AuditHandler{
+		logger:       logger,
+		tracer:       container.Tracer,
+		auditService: auditService,
+	}
+}
+
+type ListAuditEventsResponse struct {
+	Events     []*domain.AuditEvent `json:"events"`
+	Pagination domain.Pagination    `json:"pagination"`
+}
+
+// parseAuditFilter reads the filter query parameters, times are RFC 3339
+func parseAuditFilter(query url.Values) (*domain.AuditFilter, error) {
+	filter := &domain.AuditFilter{
+		Action: query.Get("action"),
+	}
+
+	ids := map[string]*int{
+		"actorId":        &filter.ActorId,
+		"subjectId":      &filter.SubjectId,
+		"organizationId": &filter.OrganizationId,
+		"accountId":      &filter.AccountId,
+	}
+	for name, id := range ids {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return nil, errors.New("invalid " + name)
+		}
+		*id = parsed
+	}
+
+	var err error
+	filter.From, err = helpers.ParseFilterTime("from", query.Get("from"))
+	if err != nil {
+		return nil, err
+	}
+	filter.To, err = helpers.ParseFilterTime("to", query.Get("to"))
+	if err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// @Router /v1/admin/audit-events [get]
+// @Tags Admin
+// @Summary List audit events
+// @Description Search the security audit log, newest first. An action also matches the actions below it, `account.login` matches `account.login.failed`.
+// @Description Requires the audit:read permission.
+// @Accept json
+// @Produce json
+// @Param action query string false "Action or action prefix"
+// @Param actorId query int false "Account that made the change"
+// @Param subjectId query int false "Account the change was made to"
+// @Param organizationId query int false "Organization of the change"
+// @Param accountId query int false "Account that made, received or was impersonated in the change"
+// @Param from query string false "RFC 3339 time, inclusive"
+// @Param to query string false "RFC 3339 time, exclusive"
+// @Param page query int false "Page, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @Success 200 {object} ListAuditEventsResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AuditHandler.List")
+	defer span.End()
+
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	params := helpers.GetPaginationParams(r)
+	if params.Page < 1 || params.Size < 1 || params.Size > maxAuditPageSize {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"page must be positive and limit between 1 and 100",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	pagination := domain.Pagination{
+		Page: params.Page,
+		Size: params.Size,
+	}
+
+	events, err := h.auditService.ListEvents(ctx, filter, &pagination)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to list audit events",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Response
+	resp := ListAuditEventsResponse{
+		Events:     events,
+		Pagination: pagination,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/audit-events/export [get]
+// @Tags Admin
+// @Summary Export audit events
+// @Description Download the matching audit events as newline delimited JSON, oldest first. Takes the filters of the list endpoint.
+// @Description Requires the audit:read permission.
+// @Produce application/x-ndjson
+// @Param action query string false "Action or action prefix"
+// @Param actorId query int false "Account that made the change"
+// @Param subjectId query int false "Account the change was made to"
+// @Param organizationId query int false "Organization of the change"
+// @Param accountId query int false "Account that made, received or was impersonated in the change"
+// @Param from query string false "RFC 3339 time, inclusive"
+// @Param to query string false "RFC 3339 time, exclusive"
+// @Success 200 {object} domain.AuditEvent "One event per line"
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "AuditHandler.Export")
+	defer span.End()
+
+	filter, err := parseAuditFilter(r.URL.Query())
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-events.ndjson"`)
+	w.WriteHeader(http.StatusOK)
+
+	// The status is sent, a failure part way only ends the stream early
+	encoder := json.NewEncoder(w)
+	err = h.auditService.ExportEvents(ctx, filter, func(event *domain.AuditEvent) error {
+		return encoder.Encode(event)
+	})
+	if err != nil {
+		h.logger.Error("failed to export audit events", "error", err)
+	}
+}
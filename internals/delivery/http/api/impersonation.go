@@ -0,0 +1,166 @@
+package api
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ImpersonationHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accessExpiresIn int
+
+	impersonationService domain.ImpersonationService
+}
+
+func NewImpersonationHandler(
+	container *infra.Container,
+	impersonationService domain.ImpersonationService,
+) domain.ImpersonationHandler {
+	logger := container.Logger.With("path", "ImpersonationHandler")
+	return &ImpersonationHandler{
+		logger:               logger,
+		tracer:               container.Tracer,
+		accessExpiresIn:      container.Cfg.JWT.AccessExpirationMinutes * 60,
+		impersonationService: impersonationService,
+	}
+}
+
+func impersonationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrImpersonateSelf),
+		errors.Is(err, domain.ErrNotImpersonating):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrImpersonationNested),
+		errors.Is(err, domain.ErrImpersonationEscalation):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrAccountNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrAccountDisabled):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type ImpersonationResponse struct {
+	Message string `json:"message"`
+	*TokenResponse
+}
+
+// @Router /v1/admin/accounts/{id}/impersonate [post]
+// @Tags Admin
+// @Summary Impersonate an account
+// @Description Issue an access token acting as the account, it carries the admin as the actor and cannot be refreshed.
+// @Description Changing the password, deleting the account and other sensitive operations are refused to it.
+// @Description Accounts granted a permission the admin lacks cannot be impersonated. Requires the accounts:impersonate permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Success 200 {object} ImpersonationResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *ImpersonationHandler) Start(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ImpersonationHandler.Start")
+	defer span.End()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"invalid account id",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	token, err := h.impersonationService.Impersonate(ctx, acc, id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to impersonate account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, impersonationErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := ImpersonationResponse{
+		Message:       "impersonating account",
+		TokenResponse: newTokenResponse(token, "", h.accessExpiresIn),
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/auth/impersonation/stop [post]
+// @Tags Account
+// @Summary Stop impersonating
+// @Description Revoke the access token of the impersonation session, the admin continues with its own token.
+// @Accept json
+// @Produce json
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *ImpersonationHandler) Stop(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "ImpersonationHandler.Stop")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = h.impersonationService.StopImpersonation(ctx, acc, helpers.GetAccessTokenFromContext(ctx))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to stop impersonating",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, impersonationErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "impersonation stopped",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,378 @@
+package api
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type InvitationHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	invitationService domain.InvitationService
+}
+
+func NewInvitationHandler(
+	container *infra.Container,
+	invitationService domain.InvitationService,
+) domain.InvitationHandler {
+	logger := container.Logger.With("path", "InvitationHandler")
+	return &InvitationHandler{
+		logger:            logger,
+		tracer:            container.Tracer,
+		invitationService: invitationService,
+	}
+}
+
+func invitationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvitationEmailRequired),
+		errors.Is(err, domain.ErrInvalidEmail),
+		errors.Is(err, domain.ErrInvalidOrgRole),
+		errors.Is(err, domain.ErrNoTenant):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrPermissionDenied),
+		errors.Is(err, domain.ErrInvitationEmailMismatch):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrInvitationNotFound),
+		errors.Is(err, domain.ErrOrganizationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrInvitationUsed),
+		errors.Is(err, domain.ErrInvitationAccountExists),
+		errors.Is(err, domain.ErrAlreadyOrganizationMember):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvitationExpired):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+type InvitationResponse struct {
+	Message    string             `json:"message"`
+	Invitation *domain.Invitation `json:"invitation"`
+}
+
+type ListInvitationsResponse struct {
+	Invitations []*domain.Invitation `json:"invitations"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+type RegisterInvitationRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type AcceptInvitationResponse struct {
+	Message string                     `json:"message"`
+	Member  *domain.OrganizationMember `json:"member"`
+}
+
+// @Router /v1/organization/invitations [post]
+// @Tags Organization
+// @Summary Invite to the organization
+// @Description Mail an invite link for the organization selected by the X-Org-ID header, replacing a pending invitation of the email. Requires the admin role, the owner role can only be given by owners.
+// @Accept json
+// @Produce json
+// @Param X-Org-ID header int true "Organization id"
+// @Param request body CreateInvitationRequest true "Email and organization role"
+// @Success 201 {object} InvitationResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "InvitationHandler.Create")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[CreateInvitationRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	member, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid organization",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	invitation, err := h.invitationService.Invite(ctx, member, req.Email, req.Role)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to create invitation",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, invitationErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := InvitationResponse{
+		Message:    "invitation sent",
+		Invitation: invitation,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusCreated, resp)
+}
+
+// @Router /v1/organization/invitations [get]
+// @Tags Organization
+// @Summary List invitations
+// @Description List the pending and accepted invitations of the organization selected by the X-Org-ID header. Requires the admin role.
+// @Accept json
+// @Produce json
+// @Param X-Org-ID header int true "Organization id"
+// @Success 200 {object} ListInvitationsResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "InvitationHandler.List")
+	defer span.End()
+
+	member, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid organization",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	invitations, err := h.invitationService.ListInvitations(ctx, member.OrganizationId)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to list invitations",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, invitationErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := ListInvitationsResponse{
+		Invitations: invitations,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/organization/invitations/{id} [delete]
+// @Tags Organization
+// @Summary Revoke an invitation
+// @Description Delete an invitation of the organization selected by the X-Org-ID header, its link stops working. Requires the admin role.
+// @Accept json
+// @Produce json
+// @Param X-Org-ID header int true "Organization id"
+// @Param id path int true "Invitation id"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *InvitationHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "InvitationHandler.Revoke")
+	defer span.End()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"invalid invitation id",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	member, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid organization",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	err = h.invitationService.RevokeInvitation(ctx, member, id)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to revoke invitation",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, invitationErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "invitation revoked",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/invitations/accept [post]
+// @Tags Organization
+// @Summary Accept an invitation
+// @Description Join the organization of an invite link with the logged in account, its email must be the invited one.
+// @Accept json
+// @Produce json
+// @Param request body AcceptInvitationRequest true "Invitation token"
+// @Success 200 {object} AcceptInvitationResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 410 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *InvitationHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "InvitationHandler.Accept")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[AcceptInvitationRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	member, err := h.invitationService.Accept(ctx, req.Token, acc)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to accept invitation",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, invitationErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := AcceptInvitationResponse{
+		Message: "invitation accepted",
+		Member:  member,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/invitations/register [post]
+// @Tags Organization
+// @Summary Register through an invitation
+// @Description Create the account of the invited email and join the organization. The email counts as verified, login afterwards with it and the password.
+// @Accept json
+// @Produce json
+// @Param request body RegisterInvitationRequest true "Invitation token and password"
+// @Success 201 {object} AcceptInvitationResponse
+// @Failure 400 {object} helpers.PasswordPolicyResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 410 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *InvitationHandler) Register(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "InvitationHandler.Register")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[RegisterInvitationRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc := &domain.Account{
+		Password: req.Password,
+		Roles:    []string{domain.ROLE_USER},
+	}
+
+	member, err := h.invitationService.AcceptWithRegistration(ctx, req.Token, acc)
+	if helpers.WritePasswordPolicyError(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to register account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, invitationErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := AcceptInvitationResponse{
+		Message: "account registered, login to continue",
+		Member:  member,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusCreated, resp)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type LockoutHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	lockoutService domain.LockoutService
+}
+
+func NewLockoutHandler(
+	container *infra.Container,
+	lockoutService domain.LockoutService,
+) domain.LockoutHandler {
+	logger := container.Logger.With("path", "LockoutHandler")
+	return &LockoutHandler{
+		logger:         logger,
+		tracer:         container.Tracer,
+		lockoutService: lockoutService,
+	}
+}
+
+// @Router /v1/admin/accounts/{id}/unlock [post]
+// @Tags Admin
+// @Summary Unlock an account
+// @Description Clear the failed login and second factor attempts of an account, lifting its lockout. Requires the accounts:unlock permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (l *LockoutHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	ctx, span := l.tracer.Start(r.Context(), "LockoutHandler.Unlock")
+	defer span.End()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"invalid account id",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	err = l.lockoutService.Unlock(ctx, id)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to unlock account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusNotFound, errorResponse)
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to unlock account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "account unlocked",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,449 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type MFAHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accessExpiresIn int
+
+	accountService domain.AccountService
+	tokenService   domain.TokenService
+	mfaService     domain.MFAService
+}
+
+func NewMFAHandler(
+	container *infra.Container,
+	accountService domain.AccountService,
+	tokenService domain.TokenService,
+	mfaService domain.MFAService,
+) domain.MFAHandler {
+	logger := container.Logger.With("path", "MFAHandler")
+	return &MFAHandler{
+		logger:          logger,
+		tracer:          container.Tracer,
+		accessExpiresIn: container.Cfg.JWT.AccessExpirationMinutes * 60,
+		accountService:  accountService,
+		tokenService:    tokenService,
+		mfaService:      mfaService,
+	}
+}
+
+func mfaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMFACode),
+		errors.Is(err, domain.ErrMFANotEnrolled),
+		errors.Is(err, domain.ErrMFANotEnabled):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrMFAAlreadyEnabled):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrMFARequired),
+		errors.Is(err, domain.ErrImpersonationForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// startSession issues a new access and refresh token pair for the account in http only
+// cookies, or in the returned token response when the client asked for the tokens
+func (a *MFAHandler) startSession(ctx context.Context, w http.ResponseWriter, acc *domain.Account, returnTokens bool) (*TokenResponse, error) {
+	token, err := a.tokenService.GenerateJWT(acc)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := a.tokenService.GenerateRefreshToken(ctx, acc.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if returnTokens {
+		return newTokenResponse(token, refreshToken, a.accessExpiresIn), nil
+	}
+
+	helpers.SetAuthCookies(w, token, refreshToken)
+	return nil, nil
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+	// ReturnTokens returns the tokens in the body instead of cookies, like login
+	ReturnTokens bool `json:"return_tokens"`
+}
+
+// @Router /v1/auth/mfa/verify [post]
+// @Tags MFA
+// @Summary Complete a login with a second factor
+// @Description Exchange the mfa_token returned by login and a TOTP or recovery code for the session cookies,
+// @Description or for tokens in the body with return_tokens
+// @Accept json
+// @Produce json
+// @Param request body MFAVerifyRequest true "Pending token and code"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 401 {object} helpers.GeneralResponse
+// @Failure 429 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *MFAHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "MFAHandler.Verify")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[MFAVerifyRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	accountId, err := a.tokenService.ValidateMFAPendingToken(ctx, req.MFAToken)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid mfa token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusUnauthorized, errorResponse)
+		return
+	}
+
+	// Verify second factor
+	err = a.mfaService.VerifyLogin(ctx, accountId, req.Code, helpers.GetClientIP(r))
+	if helpers.WriteLockout(w, err) {
+		return
+	}
+	if err != nil {
+		status := mfaErrorStatus(err)
+		if status == http.StatusBadRequest {
+			status = http.StatusUnauthorized
+		}
+		errorResponse := helpers.GeneralResponse{
+			Message: "mfa verification failed",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, status, errorResponse)
+		return
+	}
+
+	// The pending token is single use
+	err = a.tokenService.RevokeJWT(ctx, req.MFAToken)
+	if err != nil {
+		a.logger.Error("failed to revoke mfa token", "error", err)
+	}
+
+	acc, err := a.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusUnauthorized, errorResponse)
+		return
+	}
+	acc.MFAVerified = true
+
+	tokens, err := a.startSession(ctx, w, acc, req.ReturnTokens)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to generate token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Response
+	resp := LoginResponse{
+		Message:       "login successful",
+		TokenResponse: tokens,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/auth/mfa/enroll [post]
+// @Tags MFA
+// @Summary Start MFA enrollment
+// @Description Generate a TOTP secret with its otpauth uri and QR code. MFA is enabled once the secret is confirmed.
+// @Accept json
+// @Produce json
+// @Success 200 {object} domain.MFAEnrollment
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *MFAHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "MFAHandler.Enroll")
+	defer span.End()
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	enrollment, err := a.mfaService.Enroll(ctx, acc)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to enroll mfa",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, mfaErrorStatus(err), errorResponse)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, enrollment)
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesResponse struct {
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+	*TokenResponse
+}
+
+// @Router /v1/auth/mfa/confirm [post]
+// @Tags MFA
+// @Summary Confirm MFA enrollment
+// @Description Enable MFA with a code from the authenticator app. The recovery codes are only shown in this response.
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "TOTP code"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *MFAHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "MFAHandler.Confirm")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[MFACodeRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	codes, err := a.mfaService.Confirm(ctx, acc.Id, req.Code)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to confirm mfa",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, mfaErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Replace the current session with one that passed the second factor,
+	// bearer sessions get the new tokens in the body
+	accessToken := helpers.GetAccessTokenFromContext(ctx)
+	err = a.tokenService.RevokeSession(
+		ctx,
+		accessToken,
+		helpers.GetCookieValue(r, config.REFRESH_COOKIE_NAME),
+	)
+	if err != nil {
+		a.logger.Error("failed to revoke session", "error", err)
+	}
+
+	acc.MFAVerified = true
+	tokens, err := a.startSession(ctx, w, acc, accessToken != "" && accessToken == helpers.GetBearerToken(r))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to generate token",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Response
+	resp := RecoveryCodesResponse{
+		Message:       "mfa enabled",
+		RecoveryCodes: codes,
+		TokenResponse: tokens,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/auth/mfa/disable [post]
+// @Tags MFA
+// @Summary Disable MFA
+// @Description Disable MFA with a TOTP or recovery code. Not allowed for roles that require MFA.
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *MFAHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "MFAHandler.Disable")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[MFACodeRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = a.mfaService.Disable(ctx, acc, req.Code)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to disable mfa",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, mfaErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "mfa disabled",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/auth/mfa/recovery-codes [post]
+// @Tags MFA
+// @Summary Regenerate recovery codes
+// @Description Replace every recovery code of the account. The new codes are only shown in this response.
+// @Accept json
+// @Produce json
+// @Param request body MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (a *MFAHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "MFAHandler.RegenerateRecoveryCodes")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[MFACodeRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Get account from context
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	codes, err := a.mfaService.RegenerateRecoveryCodes(ctx, acc.Id, req.Code)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to regenerate recovery codes",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, mfaErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := RecoveryCodesResponse{
+		Message:       "recovery codes regenerated",
+		RecoveryCodes: codes,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
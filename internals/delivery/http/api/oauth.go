@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OAuthHandler serves the OAuth2 and OpenID Connect provider endpoints. They follow the
+// request and error formats of RFC 6749 and OpenID Connect rather than GeneralResponse.
+type OAuthHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	oauthService domain.OAuthService
+}
+
+func NewOAuthHandler(
+	container *infra.Container,
+	oauthService domain.OAuthService,
+) domain.OAuthHandler {
+	logger := container.Logger.With("path", "OAuthHandler")
+	return &OAuthHandler{
+		logger:       logger,
+		tracer:       container.Tracer,
+		oauthService: oauthService,
+	}
+}
+
+// writeOAuthError writes the error in the RFC 6749 format, unexpected errors are hidden from the client
+func (a *OAuthHandler) writeOAuthError(w http.ResponseWriter, err error) {
+	var oauthErr *domain.OAuthError
+	if !errors.As(err, &oauthErr) {
+		a.logger.Error("oauth request failed", "error", err)
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, domain.NewOAuthError("server_error", ""))
+		return
+	}
+
+	status := http.StatusBadRequest
+	if oauthErr.Code == domain.OAUTH_ERR_INVALID_CLIENT {
+		w.Header().Set("WWW-Authenticate", `Basic realm="gostarter"`)
+		status = http.StatusUnauthorized
+	}
+
+	_ = helpers.WriteResponse(w, status, oauthErr)
+}
+
+// clientCredentials reads client_secret_basic credentials, falling back to the form
+func clientCredentials(r *http.Request) (string, string) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	// Basic credentials are form encoded, RFC 6749 section 2.3.1
+	if decoded, err := url.QueryUnescape(clientId); err == nil {
+		clientId = decoded
+	}
+	if decoded, err := url.QueryUnescape(clientSecret); err == nil {
+		clientSecret = decoded
+	}
+
+	return clientId, clientSecret
+}
+
+// Discovery serves the OpenID Connect discovery document
+func (a *OAuthHandler) Discovery(w http.ResponseWriter, r *http.Request) {
+	_, span := a.tracer.Start(r.Context(), "OAuthHandler.Discovery")
+	defer span.End()
+
+	_ = helpers.WriteResponse(w, http.StatusOK, a.oauthService.Discovery())
+}
+
+type JWKSResponse struct {
+	Keys []domain.JSONWebKey `json:"keys"`
+}
+
+// JWKS serves the public keys that verify ID tokens
+func (a *OAuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	_, span := a.tracer.Start(r.Context(), "OAuthHandler.JWKS")
+	defer span.End()
+
+	resp := JWKSResponse{
+		Keys: a.oauthService.JWKS(),
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// Token exchanges an authorization code or a refresh token
+func (a *OAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "OAuthHandler.Token")
+	defer span.End()
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	err := r.ParseForm()
+	if err != nil {
+		a.writeOAuthError(w, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_REQUEST, err.Error()))
+		return
+	}
+
+	clientId, clientSecret := clientCredentials(r)
+	req := &domain.OAuthTokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+	}
+
+	resp, err := a.oauthService.Exchange(ctx, req)
+	if err != nil {
+		a.writeOAuthError(w, err)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// UserInfo returns the claims of the account the bearer access token was issued for
+func (a *OAuthHandler) UserInfo(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "OAuthHandler.UserInfo")
+	defer span.End()
+
+	claims, err := a.oauthService.UserInfo(ctx, helpers.GetBearerToken(r))
+	if err != nil {
+		var oauthErr *domain.OAuthError
+		if !errors.As(err, &oauthErr) {
+			a.writeOAuthError(w, err)
+			return
+		}
+
+		status := http.StatusUnauthorized
+		if oauthErr.Code == "insufficient_scope" {
+			status = http.StatusForbidden
+		}
+		w.Header().Set("WWW-Authenticate", `Bearer error="`+oauthErr.Code+`"`)
+		_ = helpers.WriteResponse(w, status, oauthErr)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, claims)
+}
+
+// Introspect reports whether a token of the calling client is active, RFC 7662
+func (a *OAuthHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "OAuthHandler.Introspect")
+	defer span.End()
+
+	err := r.ParseForm()
+	if err != nil {
+		a.writeOAuthError(w, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_REQUEST, err.Error()))
+		return
+	}
+
+	clientId, clientSecret := clientCredentials(r)
+	resp, err := a.oauthService.Introspect(ctx, clientId, clientSecret, r.PostForm.Get("token"))
+	if err != nil {
+		a.writeOAuthError(w, err)
+		return
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// Revoke invalidates a token of the calling client, RFC 7009
+func (a *OAuthHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx, span := a.tracer.Start(r.Context(), "OAuthHandler.Revoke")
+	defer span.End()
+
+	err := r.ParseForm()
+	if err != nil {
+		a.writeOAuthError(w, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_REQUEST, err.Error()))
+		return
+	}
+
+	clientId, clientSecret := clientCredentials(r)
+	err = a.oauthService.Revoke(ctx, clientId, clientSecret, r.PostForm.Get("token"))
+	if err != nil {
+		a.writeOAuthError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type PasswordResetHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	passwordResetService domain.PasswordResetService
+}
+
+func NewPasswordResetHandler(
+	container *infra.Container,
+	passwordResetService domain.PasswordResetService,
+) domain.PasswordResetHandler {
+	logger := container.Logger.With("path", "PasswordResetHandler")
+	return &PasswordResetHandler{
+		logger:               logger,
+		tracer:               container.Tracer,
+		passwordResetService: passwordResetService,
+	}
+}
+
+type RequestPasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// @Router /v1/auth/password-reset/request [post]
+// @Tags Account
+// @Summary Request a password reset
+// @Description Mail a password reset link. The response is the same whether or not the email is registered.
+// @Accept json
+// @Produce json
+// @Param email body RequestPasswordResetRequest true "Account email"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+func (p *PasswordResetHandler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx, span := p.tracer.Start(r.Context(), "PasswordResetHandler.RequestPasswordReset")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[RequestPasswordResetRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	err = p.passwordResetService.RequestPasswordReset(ctx, req.Email)
+	if err != nil {
+		p.logger.Error("failed to request password reset", "error", err)
+	}
+
+	// Response does not reveal whether the account exists
+	resp := helpers.GeneralResponse{
+		Message: "if the account exists, a password reset link has been sent",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+type ConfirmPasswordResetRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// @Router /v1/auth/password-reset/confirm [post]
+// @Tags Account
+// @Summary Confirm a password reset
+// @Description Set a new password with the token from the reset email. All existing sessions are logged out.
+// @Description A password failing the password policy is rejected with every violated rule and the token stays valid.
+// @Accept json
+// @Produce json
+// @Param reset body ConfirmPasswordResetRequest true "Reset token and new password"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.PasswordPolicyResponse
+func (p *PasswordResetHandler) ConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	ctx, span := p.tracer.Start(r.Context(), "PasswordResetHandler.ConfirmPasswordReset")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[ConfirmPasswordResetRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	err = p.passwordResetService.ResetPassword(ctx, req.Token, req.Password)
+	if helpers.WritePasswordPolicyError(w, err) {
+		return
+	}
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to reset password",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "password has been reset",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
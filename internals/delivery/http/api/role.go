@@ -0,0 +1,417 @@
+package api
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type RoleHandler struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	roleService domain.RoleService
+}
+
+func NewRoleHandler(
+	container *infra.Container,
+	roleService domain.RoleService,
+) domain.RoleHandler {
+	logger := container.Logger.With("path", "RoleHandler")
+	return &RoleHandler{
+		logger:      logger,
+		tracer:      container.Tracer,
+		roleService: roleService,
+	}
+}
+
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRoleName):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrRoleNotFound),
+		errors.Is(err, domain.ErrPermissionNotFound),
+		errors.Is(err, domain.ErrAccountNotFound),
+		errors.Is(err, domain.ErrRoleNotAssigned),
+		errors.Is(err, domain.ErrPermissionNotGranted):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrRoleExists),
+		errors.Is(err, domain.ErrRoleAlreadyAssigned),
+		errors.Is(err, domain.ErrPermissionAlreadyGranted):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleResponse struct {
+	Message string       `json:"message"`
+	Role    *domain.Role `json:"role"`
+}
+
+type ListRolesResponse struct {
+	Roles []*domain.Role `json:"roles"`
+}
+
+type GrantPermissionRequest struct {
+	Permission string `json:"permission"`
+}
+
+type AssignRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// @Router /v1/admin/roles [post]
+// @Tags Admin
+// @Summary Create a role
+// @Description Create a role with an optional set of permissions. Requires the roles:manage permission.
+// @Accept json
+// @Produce json
+// @Param request body CreateRoleRequest true "Role name and permissions"
+// @Success 201 {object} RoleResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *RoleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RoleHandler.Create")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[CreateRoleRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	role, err := h.roleService.CreateRole(ctx, acc.Id, req.Name, req.Permissions)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to create role",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, roleErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := RoleResponse{
+		Message: "role created",
+		Role:    role,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusCreated, resp)
+}
+
+// @Router /v1/admin/roles [get]
+// @Tags Admin
+// @Summary List roles
+// @Description List every role with the permissions granted to it. Requires the roles:manage permission.
+// @Accept json
+// @Produce json
+// @Success 200 {object} ListRolesResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *RoleHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RoleHandler.List")
+	defer span.End()
+
+	roles, err := h.roleService.ListRoles(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to list roles",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	// Response
+	resp := ListRolesResponse{
+		Roles: roles,
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/roles/{role}/permissions [post]
+// @Tags Admin
+// @Summary Grant a permission to a role
+// @Description Grant a permission to a role, it applies to existing sessions of the role members. Requires the roles:manage permission.
+// @Accept json
+// @Produce json
+// @Param role path string true "Role name"
+// @Param request body GrantPermissionRequest true "Permission name"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *RoleHandler) GrantPermission(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RoleHandler.GrantPermission")
+	defer span.End()
+
+	// Parse request
+	req, err := helpers.ParseRequest[GrantPermissionRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = h.roleService.GrantPermission(ctx, acc.Id, chi.URLParam(r, "role"), req.Permission)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to grant permission",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, roleErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "permission granted",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/roles/{role}/permissions/{permission} [delete]
+// @Tags Admin
+// @Summary Revoke a permission from a role
+// @Description Revoke a permission from a role, it applies to existing sessions of the role members. Requires the roles:manage permission.
+// @Accept json
+// @Produce json
+// @Param role path string true "Role name"
+// @Param permission path string true "Permission name"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *RoleHandler) RevokePermission(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RoleHandler.RevokePermission")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = h.roleService.RevokePermission(ctx, acc.Id, chi.URLParam(r, "role"), chi.URLParam(r, "permission"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to revoke permission",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, roleErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "permission revoked",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/accounts/{id}/roles [post]
+// @Tags Admin
+// @Summary Assign a role to an account
+// @Description Assign a role to an account. Its access tokens are revoked, so existing sessions get the role on their next refresh. Requires the roles:manage permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Param request body AssignRoleRequest true "Role name"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 409 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *RoleHandler) AssignRole(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RoleHandler.AssignRole")
+	defer span.End()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"invalid account id",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Parse request
+	req, err := helpers.ParseRequest[AssignRoleRequest](r.Body)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = h.roleService.AssignRole(ctx, acc.Id, id, req.Role)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to assign role",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, roleErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "role assigned",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
+
+// @Router /v1/admin/accounts/{id}/roles/{role} [delete]
+// @Tags Admin
+// @Summary Remove a role from an account
+// @Description Remove a role from an account. Its access tokens are revoked, so existing sessions lose the role on their next refresh. Requires the roles:manage permission.
+// @Accept json
+// @Produce json
+// @Param id path int true "Account id"
+// @Param role path string true "Role name"
+// @Success 200 {object} helpers.GeneralResponse
+// @Failure 400 {object} helpers.GeneralResponse
+// @Failure 403 {object} helpers.GeneralResponse
+// @Failure 404 {object} helpers.GeneralResponse
+// @Failure 500 {object} helpers.GeneralResponse
+func (h *RoleHandler) UnassignRole(w http.ResponseWriter, r *http.Request) {
+	ctx, span := h.tracer.Start(r.Context(), "RoleHandler.UnassignRole")
+	defer span.End()
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid request",
+			Errors: []string{
+				"invalid account id",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "invalid account",
+			Errors: []string{
+				"account not found",
+			},
+		}
+		_ = helpers.WriteResponse(w, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	err = h.roleService.UnassignRole(ctx, acc.Id, id, chi.URLParam(r, "role"))
+	if err != nil {
+		errorResponse := helpers.GeneralResponse{
+			Message: "failed to remove role",
+			Errors: []string{
+				err.Error(),
+			},
+		}
+		_ = helpers.WriteResponse(w, roleErrorStatus(err), errorResponse)
+		return
+	}
+
+	// Response
+	resp := helpers.GeneralResponse{
+		Message: "role removed",
+	}
+
+	_ = helpers.WriteResponse(w, http.StatusOK, resp)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
 	"gostarter/pkg/utils"
 	"slices"
 
@@ -36,12 +37,34 @@ func HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, roles
 		rolesParsed[i] = utils.ParseNullString(r)
 	}
 
-	hasRole := slices.ContainsFunc(acc.Roles, func(r string) bool {
-		return slices.Contains(rolesParsed, r)
-	})
+	// Account.HasRole limits API key sessions to the key scopes
+	hasRole := slices.ContainsFunc(rolesParsed, acc.HasRole)
 	if !hasRole {
 		return nil, errors.New("unauthorized")
 	}
 
 	return next(ctx)
 }
+
+// HasPermission returns the directive checking the permissions granted to the effective roles of the session
+func HasPermission(permissionService domain.PermissionService) func(ctx context.Context, obj interface{}, next graphql.Resolver, permission string) (interface{}, error) {
+	return func(ctx context.Context, obj interface{}, next graphql.Resolver, permission string) (interface{}, error) {
+		acc, err := helpers.GetAccountFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if acc == nil {
+			return nil, errors.New("must be authenticated")
+		}
+
+		allowed, err := permissionService.HasPermission(ctx, acc, permission)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, domain.ErrPermissionDenied
+		}
+
+		return next(ctx)
+	}
+}
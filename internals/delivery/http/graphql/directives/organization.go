@@ -0,0 +1,59 @@
+package directives
+
+import (
+	"context"
+	"errors"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// fieldOrganizationId returns the organization a field acts on from its organizationId or id argument
+func fieldOrganizationId(ctx context.Context) (int, bool) {
+	fieldCtx := graphql.GetFieldContext(ctx)
+	if fieldCtx == nil {
+		return 0, false
+	}
+
+	for _, name := range []string{"organizationId", "id"} {
+		if id, ok := fieldCtx.Args[name].(int); ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// HasOrgRole returns the directive checking the organization role of the session. The membership
+// is put in the context and the resolver is scoped to the organization.
+func HasOrgRole(organizationService domain.OrganizationService) func(ctx context.Context, obj interface{}, next graphql.Resolver, role string) (interface{}, error) {
+	return func(ctx context.Context, obj interface{}, next graphql.Resolver, role string) (interface{}, error) {
+		acc, err := helpers.GetAccountFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if acc == nil {
+			return nil, errors.New("must be authenticated")
+		}
+
+		organizationId, ok := fieldOrganizationId(ctx)
+		if !ok {
+			// Fall back to the organization selected with the X-Org-ID header
+			member, err := helpers.GetOrganizationFromContext(ctx)
+			if err != nil {
+				return nil, err
+			}
+			organizationId = member.OrganizationId
+		}
+
+		member, err := organizationService.GetMembership(ctx, organizationId, acc.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !member.HasRole(role) {
+			return nil, domain.ErrPermissionDenied
+		}
+
+		return next(helpers.WithOrganization(ctx, member))
+	}
+}
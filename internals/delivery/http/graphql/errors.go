@@ -0,0 +1,28 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"gostarter/internals/domain"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errCodePasswordPolicy = "PASSWORD_POLICY"
+
+// errorPresenter adds the violated rules of a rejected password to the error extensions
+func errorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		gqlErr.Message = domain.ErrPasswordPolicy.Error()
+		gqlErr.Extensions = map[string]interface{}{
+			"code":       errCodePasswordPolicy,
+			"violations": policyErr.Violations,
+		}
+	}
+
+	return gqlErr
+}
@@ -5,6 +5,7 @@ import (
 	"gostarter/internals/delivery/http/graphql/directives"
 	"gostarter/internals/delivery/http/graphql/generated"
 	"gostarter/internals/delivery/http/graphql/resolver"
+	"gostarter/internals/di"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/playground"
@@ -25,8 +26,10 @@ func NewGQLHandler(
 			ServiceDi: serviceDi,
 		},
 		Directives: generated.DirectiveRoot{
-			Auth:    directives.Auth,
-			HasRole: directives.HasRole,
+			Auth:          directives.Auth,
+			HasRole:       directives.HasRole,
+			HasPermission: directives.HasPermission(serviceDi.PermissionService),
+			HasOrgRole:    directives.HasOrgRole(serviceDi.OrganizationService),
 		},
 	}
 	return &GQLHandler{
@@ -37,6 +40,7 @@ func NewGQLHandler(
 func (h *GQLHandler) SetupRoutes(r chi.Router) {
 
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(h.config))
+	srv.SetErrorPresenter(errorPresenter)
 
 	// Playground handler
 	r.Get("/playground", playground.Handler("Fitness Hub Graphql Server", "/query"))
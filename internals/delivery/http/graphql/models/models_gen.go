@@ -9,6 +9,52 @@ import (
 	"strconv"
 )
 
+type AccountFilterInput struct {
+	Status         *AccountStatus `json:"status,omitempty"`
+	Role           *string        `json:"role,omitempty"`
+	CreatedFrom    *string        `json:"createdFrom,omitempty"`
+	CreatedTo      *string        `json:"createdTo,omitempty"`
+	UpdatedFrom    *string        `json:"updatedFrom,omitempty"`
+	UpdatedTo      *string        `json:"updatedTo,omitempty"`
+	EmailPrefix    *string        `json:"emailPrefix,omitempty"`
+	UsernamePrefix *string        `json:"usernamePrefix,omitempty"`
+	Query          *string        `json:"query,omitempty"`
+}
+
+type AccountSortInput struct {
+	Field     AccountSortField `json:"field"`
+	Direction *SortDirection   `json:"direction,omitempty"`
+}
+
+type AuditEventFilter struct {
+	Action         *string `json:"action,omitempty"`
+	ActorID        *int    `json:"actorId,omitempty"`
+	SubjectID      *int    `json:"subjectId,omitempty"`
+	OrganizationID *int    `json:"organizationId,omitempty"`
+	AccountID      *int    `json:"accountId,omitempty"`
+	From           *string `json:"from,omitempty"`
+	To             *string `json:"to,omitempty"`
+}
+
+type CreateAPIKeyInput struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes,omitempty"`
+	ExpiresInDays *int     `json:"expiresInDays,omitempty"`
+}
+
+type CreateRoleInput struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+type CreatedAPIKey struct {
+	Token  string         `json:"token"`
+	APIKey *domain.APIKey `json:"apiKey"`
+}
+
+type Mutation struct {
+}
+
 type PageInfo struct {
 	Page  int `json:"page"`
 	Size  int `json:"size"`
@@ -20,46 +66,143 @@ type PaginatedAccounts struct {
 	PageInfo *PageInfo         `json:"pageInfo"`
 }
 
+type PaginatedAuditEvents struct {
+	Events   []*domain.AuditEvent `json:"events"`
+	PageInfo *PageInfo            `json:"pageInfo"`
+}
+
 type Query struct {
 }
 
-type Role string
+type AccountSortField string
+
+const (
+	AccountSortFieldID        AccountSortField = "ID"
+	AccountSortFieldEmail     AccountSortField = "EMAIL"
+	AccountSortFieldUsername  AccountSortField = "USERNAME"
+	AccountSortFieldCreatedAt AccountSortField = "CREATED_AT"
+	AccountSortFieldUpdatedAt AccountSortField = "UPDATED_AT"
+)
+
+var AllAccountSortField = []AccountSortField{
+	AccountSortFieldID,
+	AccountSortFieldEmail,
+	AccountSortFieldUsername,
+	AccountSortFieldCreatedAt,
+	AccountSortFieldUpdatedAt,
+}
+
+func (e AccountSortField) IsValid() bool {
+	switch e {
+	case AccountSortFieldID, AccountSortFieldEmail, AccountSortFieldUsername, AccountSortFieldCreatedAt, AccountSortFieldUpdatedAt:
+		return true
+	}
+	return false
+}
+
+func (e AccountSortField) String() string {
+	return string(e)
+}
+
+func (e *AccountSortField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AccountSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AccountSortField", str)
+	}
+	return nil
+}
+
+func (e AccountSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type AccountStatus string
+
+const (
+	AccountStatusActive     AccountStatus = "ACTIVE"
+	AccountStatusDisabled   AccountStatus = "DISABLED"
+	AccountStatusUnverified AccountStatus = "UNVERIFIED"
+	AccountStatusDeleted    AccountStatus = "DELETED"
+)
+
+var AllAccountStatus = []AccountStatus{
+	AccountStatusActive,
+	AccountStatusDisabled,
+	AccountStatusUnverified,
+	AccountStatusDeleted,
+}
+
+func (e AccountStatus) IsValid() bool {
+	switch e {
+	case AccountStatusActive, AccountStatusDisabled, AccountStatusUnverified, AccountStatusDeleted:
+		return true
+	}
+	return false
+}
+
+func (e AccountStatus) String() string {
+	return string(e)
+}
+
+func (e *AccountStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AccountStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AccountStatus", str)
+	}
+	return nil
+}
+
+func (e AccountStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortDirection string
 
 const (
-	RoleUser  Role = "USER"
-	RoleAdmin Role = "ADMIN"
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
 )
 
-var AllRole = []Role{
-	RoleUser,
-	RoleAdmin,
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
 }
 
-func (e Role) IsValid() bool {
+func (e SortDirection) IsValid() bool {
 	switch e {
-	case RoleUser, RoleAdmin:
+	case SortDirectionAsc, SortDirectionDesc:
 		return true
 	}
 	return false
 }
 
-func (e Role) String() string {
+func (e SortDirection) String() string {
 	return string(e)
 }
 
-func (e *Role) UnmarshalGQL(v interface{}) error {
+func (e *SortDirection) UnmarshalGQL(v interface{}) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = Role(str)
+	*e = SortDirection(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid Role", str)
+		return fmt.Errorf("%s is not a valid SortDirection", str)
 	}
 	return nil
 }
 
-func (e Role) MarshalGQL(w io.Writer) {
+func (e SortDirection) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
@@ -7,19 +7,9 @@ package resolver
 import (
 	"context"
 	"gostarter/internals/delivery/http/graphql/generated"
-	"gostarter/internals/delivery/http/graphql/models"
 	"gostarter/internals/domain"
 )
 
-// Roles is the resolver for the roles field.
-func (r *accountResolver) Roles(ctx context.Context, obj *domain.Account) ([]models.Role, error) {
-	var roles []models.Role
-	for _, role := range obj.Roles {
-		roles = append(roles, models.Role(role))
-	}
-	return roles, nil
-}
-
 // CreatedAt is the resolver for the createdAt field.
 func (r *accountResolver) CreatedAt(ctx context.Context, obj *domain.Account) (string, error) {
 	timeString := obj.CreatedAt.Format("2006-01-02 15:04:05")
@@ -0,0 +1,77 @@
+package resolver
+
+import (
+	"gostarter/internals/delivery/http/graphql/models"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"strings"
+)
+
+// defaultSearchLimit is the number of searchAccounts results without a limit, which
+// maxConnectionSize bounds
+const defaultSearchLimit = 20
+
+// accountFilter converts the optional GraphQL filter, missing fields match everything
+func accountFilter(input *models.AccountFilterInput) (*domain.AccountFilter, error) {
+	filter := &domain.AccountFilter{}
+	if input == nil {
+		return filter, nil
+	}
+
+	// The enum values are the domain statuses in upper case
+	if input.Status != nil {
+		filter.Status = strings.ToLower(string(*input.Status))
+	}
+	if input.Role != nil {
+		filter.Role = *input.Role
+	}
+	if input.EmailPrefix != nil {
+		filter.EmailPrefix = *input.EmailPrefix
+	}
+	if input.UsernamePrefix != nil {
+		filter.UsernamePrefix = *input.UsernamePrefix
+	}
+	if input.Query != nil {
+		filter.Query = *input.Query
+	}
+
+	var err error
+	if input.CreatedFrom != nil {
+		filter.CreatedFrom, err = helpers.ParseFilterTime("createdFrom", *input.CreatedFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if input.CreatedTo != nil {
+		filter.CreatedTo, err = helpers.ParseFilterTime("createdTo", *input.CreatedTo)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if input.UpdatedFrom != nil {
+		filter.UpdatedFrom, err = helpers.ParseFilterTime("updatedFrom", *input.UpdatedFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if input.UpdatedTo != nil {
+		filter.UpdatedTo, err = helpers.ParseFilterTime("updatedTo", *input.UpdatedTo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+// accountSort converts the GraphQL sort, the enum values are the domain fields in upper case
+func accountSort(input []*models.AccountSortInput) []domain.AccountSort {
+	sort := make([]domain.AccountSort, 0, len(input))
+	for _, s := range input {
+		sort = append(sort, domain.AccountSort{
+			Field:      strings.ToLower(string(s.Field)),
+			Descending: s.Direction != nil && *s.Direction == models.SortDirectionDesc,
+		})
+	}
+	return sort
+}
@@ -0,0 +1,33 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"gostarter/internals/delivery/http/graphql/generated"
+	"gostarter/internals/domain"
+)
+
+// ExpiresAt is the resolver for the expiresAt field.
+func (r *aPIKeyResolver) ExpiresAt(ctx context.Context, obj *domain.APIKey) (*string, error) {
+	return formatOptionalTime(obj.ExpiresAt), nil
+}
+
+// LastUsedAt is the resolver for the lastUsedAt field.
+func (r *aPIKeyResolver) LastUsedAt(ctx context.Context, obj *domain.APIKey) (*string, error) {
+	return formatOptionalTime(obj.LastUsedAt), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *aPIKeyResolver) CreatedAt(ctx context.Context, obj *domain.APIKey) (string, error) {
+	timeString := obj.CreatedAt.Format("2006-01-02 15:04:05")
+
+	return timeString, nil
+}
+
+// APIKey returns generated.APIKeyResolver implementation.
+func (r *Resolver) APIKey() generated.APIKeyResolver { return &aPIKeyResolver{r} }
+
+type aPIKeyResolver struct{ *Resolver }
@@ -0,0 +1,34 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"encoding/json"
+	"gostarter/internals/delivery/http/graphql/generated"
+	"gostarter/internals/domain"
+)
+
+// Metadata is the resolver for the metadata field.
+func (r *auditEventResolver) Metadata(ctx context.Context, obj *domain.AuditEvent) (string, error) {
+	metadata, err := json.Marshal(obj.Metadata)
+	if err != nil {
+		return "", err
+	}
+
+	return string(metadata), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *auditEventResolver) CreatedAt(ctx context.Context, obj *domain.AuditEvent) (string, error) {
+	timeString := obj.CreatedAt.Format("2006-01-02 15:04:05")
+
+	return timeString, nil
+}
+
+// AuditEvent returns generated.AuditEventResolver implementation.
+func (r *Resolver) AuditEvent() generated.AuditEventResolver { return &auditEventResolver{r} }
+
+type auditEventResolver struct{ *Resolver }
@@ -0,0 +1,47 @@
+package resolver
+
+import (
+	"gostarter/internals/delivery/http/graphql/models"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+)
+
+// auditFilter converts the optional GraphQL filter, missing fields match everything
+func auditFilter(input *models.AuditEventFilter) (*domain.AuditFilter, error) {
+	filter := &domain.AuditFilter{}
+	if input == nil {
+		return filter, nil
+	}
+
+	if input.Action != nil {
+		filter.Action = *input.Action
+	}
+	if input.ActorID != nil {
+		filter.ActorId = *input.ActorID
+	}
+	if input.SubjectID != nil {
+		filter.SubjectId = *input.SubjectID
+	}
+	if input.OrganizationID != nil {
+		filter.OrganizationId = *input.OrganizationID
+	}
+	if input.AccountID != nil {
+		filter.AccountId = *input.AccountID
+	}
+
+	var err error
+	if input.From != nil {
+		filter.From, err = helpers.ParseFilterTime("from", *input.From)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if input.To != nil {
+		filter.To, err = helpers.ParseFilterTime("to", *input.To)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
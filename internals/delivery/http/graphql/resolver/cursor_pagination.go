@@ -0,0 +1,45 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"gostarter/internals/domain"
+	"slices"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// Connections without first and last return defaultConnectionSize items, maxConnectionSize
+// bounds both arguments
+const (
+	defaultConnectionSize = 10
+	maxConnectionSize     = 100
+)
+
+// cursorPagination converts the connection arguments, the total is only counted when the
+// totalCount field is selected
+func cursorPagination(ctx context.Context, first *int, after *string, last *int, before *string) (*domain.CursorPagination, error) {
+	pagination := &domain.CursorPagination{
+		WithTotal: slices.Contains(graphql.CollectAllFields(ctx), "totalCount"),
+	}
+
+	if first != nil {
+		pagination.First = *first
+	}
+	if after != nil {
+		pagination.After = *after
+	}
+	if last != nil {
+		pagination.Last = *last
+	}
+	if before != nil {
+		pagination.Before = *before
+	}
+
+	pagination.SetDefaultSize(defaultConnectionSize)
+	if pagination.Limit() > maxConnectionSize {
+		return nil, errors.New("first and last must be at most 100")
+	}
+
+	return pagination, nil
+}
@@ -0,0 +1,13 @@
+package resolver
+
+import "time"
+
+// formatOptionalTime formats like the other time fields, nil stays null
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	timeString := t.Format("2006-01-02 15:04:05")
+	return &timeString
+}
@@ -0,0 +1,35 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"gostarter/internals/delivery/http/graphql/generated"
+	"gostarter/internals/domain"
+)
+
+// ExpiresAt is the resolver for the expiresAt field.
+func (r *invitationResolver) ExpiresAt(ctx context.Context, obj *domain.Invitation) (string, error) {
+	timeString := obj.ExpiresAt.Format("2006-01-02 15:04:05")
+
+	return timeString, nil
+}
+
+// AcceptedAt is the resolver for the acceptedAt field.
+func (r *invitationResolver) AcceptedAt(ctx context.Context, obj *domain.Invitation) (*string, error) {
+	return formatOptionalTime(obj.AcceptedAt), nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *invitationResolver) CreatedAt(ctx context.Context, obj *domain.Invitation) (string, error) {
+	timeString := obj.CreatedAt.Format("2006-01-02 15:04:05")
+
+	return timeString, nil
+}
+
+// Invitation returns generated.InvitationResolver implementation.
+func (r *Resolver) Invitation() generated.InvitationResolver { return &invitationResolver{r} }
+
+type invitationResolver struct{ *Resolver }
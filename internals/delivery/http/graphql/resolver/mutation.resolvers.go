@@ -0,0 +1,308 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"gostarter/internals/delivery/http/graphql/generated"
+	"gostarter/internals/delivery/http/graphql/models"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"time"
+)
+
+// RequestPasswordReset is the resolver for the requestPasswordReset field.
+func (r *mutationResolver) RequestPasswordReset(ctx context.Context, email string) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.RequestPasswordReset")
+	defer span.End()
+
+	// Failures are logged but not reported, so the result does not reveal whether the account exists
+	err := r.ServiceDi.PasswordResetService.RequestPasswordReset(ctx, email)
+	if err != nil {
+		r.Container.Logger.Error("failed to request password reset", "error", err)
+	}
+
+	return true, nil
+}
+
+// ResetPassword is the resolver for the resetPassword field.
+func (r *mutationResolver) ResetPassword(ctx context.Context, token string, password string) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.ResetPassword")
+	defer span.End()
+
+	err := r.ServiceDi.PasswordResetService.ResetPassword(ctx, token, password)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateAPIKey is the resolver for the createAPIKey field.
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, input models.CreateAPIKeyInput) (*models.CreatedAPIKey, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.CreateAPIKey")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if input.ExpiresInDays != nil && *input.ExpiresInDays > 0 {
+		expiry := time.Now().AddDate(0, 0, *input.ExpiresInDays)
+		expiresAt = &expiry
+	}
+
+	key, token, err := r.ServiceDi.APIKeyService.Create(ctx, acc, input.Name, input.Scopes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CreatedAPIKey{
+		Token:  token,
+		APIKey: key,
+	}, nil
+}
+
+// RevokeAPIKey is the resolver for the revokeAPIKey field.
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, id int) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.RevokeAPIKey")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.APIKeyService.Revoke(ctx, acc.Id, id)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateRole is the resolver for the createRole field.
+func (r *mutationResolver) CreateRole(ctx context.Context, input models.CreateRoleInput) (*domain.Role, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.CreateRole")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.RoleService.CreateRole(ctx, acc.Id, input.Name, input.Permissions)
+}
+
+// GrantPermission is the resolver for the grantPermission field.
+func (r *mutationResolver) GrantPermission(ctx context.Context, role string, permission string) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.GrantPermission")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.RoleService.GrantPermission(ctx, acc.Id, role, permission)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RevokePermission is the resolver for the revokePermission field.
+func (r *mutationResolver) RevokePermission(ctx context.Context, role string, permission string) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.RevokePermission")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.RoleService.RevokePermission(ctx, acc.Id, role, permission)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AssignRole is the resolver for the assignRole field.
+func (r *mutationResolver) AssignRole(ctx context.Context, accountID int, role string) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.AssignRole")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.RoleService.AssignRole(ctx, acc.Id, accountID, role)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// UnassignRole is the resolver for the unassignRole field.
+func (r *mutationResolver) UnassignRole(ctx context.Context, accountID int, role string) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.UnassignRole")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.RoleService.UnassignRole(ctx, acc.Id, accountID, role)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ImpersonateAccount is the resolver for the impersonateAccount field.
+func (r *mutationResolver) ImpersonateAccount(ctx context.Context, accountID int) (string, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.ImpersonateAccount")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return r.ServiceDi.ImpersonationService.Impersonate(ctx, acc, accountID)
+}
+
+// StopImpersonation is the resolver for the stopImpersonation field.
+func (r *mutationResolver) StopImpersonation(ctx context.Context) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.StopImpersonation")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.ImpersonationService.StopImpersonation(ctx, acc, helpers.GetAccessTokenFromContext(ctx))
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateOrganization is the resolver for the createOrganization field.
+func (r *mutationResolver) CreateOrganization(ctx context.Context, name string) (*domain.Organization, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.CreateOrganization")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.OrganizationService.CreateOrganization(ctx, acc, name)
+}
+
+// AddOrganizationMember is the resolver for the addOrganizationMember field.
+func (r *mutationResolver) AddOrganizationMember(ctx context.Context, organizationID int, accountID int, role string) (*domain.OrganizationMember, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.AddOrganizationMember")
+	defer span.End()
+
+	actor, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.OrganizationService.AddMember(ctx, actor, accountID, role)
+}
+
+// UpdateOrganizationMember is the resolver for the updateOrganizationMember field.
+func (r *mutationResolver) UpdateOrganizationMember(ctx context.Context, organizationID int, accountID int, role string) (*domain.OrganizationMember, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.UpdateOrganizationMember")
+	defer span.End()
+
+	actor, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.OrganizationService.UpdateMemberRole(ctx, actor, accountID, role)
+}
+
+// RemoveOrganizationMember is the resolver for the removeOrganizationMember field.
+func (r *mutationResolver) RemoveOrganizationMember(ctx context.Context, organizationID int, accountID int) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.RemoveOrganizationMember")
+	defer span.End()
+
+	actor, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.OrganizationService.RemoveMember(ctx, actor, accountID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// InviteToOrganization is the resolver for the inviteToOrganization field.
+func (r *mutationResolver) InviteToOrganization(ctx context.Context, organizationID int, email string, role string) (*domain.Invitation, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.InviteToOrganization")
+	defer span.End()
+
+	actor, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.InvitationService.Invite(ctx, actor, email, role)
+}
+
+// RevokeInvitation is the resolver for the revokeInvitation field.
+func (r *mutationResolver) RevokeInvitation(ctx context.Context, organizationID int, id int) (bool, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.RevokeInvitation")
+	defer span.End()
+
+	actor, err := helpers.GetOrganizationFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = r.ServiceDi.InvitationService.RevokeInvitation(ctx, actor, id)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// AcceptInvitation is the resolver for the acceptInvitation field.
+func (r *mutationResolver) AcceptInvitation(ctx context.Context, token string) (*domain.OrganizationMember, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "MutationResolver.AcceptInvitation")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.InvitationService.Accept(ctx, token, acc)
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+type mutationResolver struct{ *Resolver }
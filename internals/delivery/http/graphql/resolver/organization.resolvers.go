@@ -0,0 +1,56 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"gostarter/internals/delivery/http/graphql/generated"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+)
+
+// Members is the resolver for the members field.
+func (r *organizationResolver) Members(ctx context.Context, obj *domain.Organization) ([]*domain.OrganizationMember, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "OrganizationResolver.Members")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Members are only listed to members of the organization
+	_, err = r.ServiceDi.OrganizationService.GetMembership(ctx, obj.Id, acc.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.OrganizationService.ListMembers(ctx, obj.Id)
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *organizationResolver) CreatedAt(ctx context.Context, obj *domain.Organization) (string, error) {
+	timeString := obj.CreatedAt.Format("2006-01-02 15:04:05")
+
+	return timeString, nil
+}
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *organizationMemberResolver) CreatedAt(ctx context.Context, obj *domain.OrganizationMember) (string, error) {
+	timeString := obj.CreatedAt.Format("2006-01-02 15:04:05")
+
+	return timeString, nil
+}
+
+// Organization returns generated.OrganizationResolver implementation.
+func (r *Resolver) Organization() generated.OrganizationResolver { return &organizationResolver{r} }
+
+// OrganizationMember returns generated.OrganizationMemberResolver implementation.
+func (r *Resolver) OrganizationMember() generated.OrganizationMemberResolver {
+	return &organizationMemberResolver{r}
+}
+
+type organizationResolver struct{ *Resolver }
+type organizationMemberResolver struct{ *Resolver }
@@ -6,9 +6,11 @@ package resolver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"gostarter/internals/delivery/http/graphql/generated"
 	"gostarter/internals/delivery/http/graphql/models"
+	"gostarter/internals/delivery/http/helpers"
 	"gostarter/internals/domain"
 )
 
@@ -17,12 +19,43 @@ func (r *queryResolver) Me(ctx context.Context) (*domain.Account, error) {
 	panic(fmt.Errorf("not implemented: Me - me"))
 }
 
+// APIKeys is the resolver for the apiKeys field.
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*domain.APIKey, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.APIKeys")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.APIKeyService.List(ctx, acc.Id)
+}
+
+// MyPermissions is the resolver for the myPermissions field.
+func (r *queryResolver) MyPermissions(ctx context.Context) ([]string, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.MyPermissions")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.PermissionService.ResolvePermissions(ctx, acc.EffectiveRoles())
+}
+
 // Accounts is the resolver for the accounts field.
-func (r *queryResolver) Accounts(ctx context.Context, pagination domain.Pagination) (*models.PaginatedAccounts, error) {
+func (r *queryResolver) Accounts(ctx context.Context, filter *models.AccountFilterInput, sort []*models.AccountSortInput, pagination domain.Pagination) (*models.PaginatedAccounts, error) {
 	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.Accounts")
 	defer span.End()
 
-	accounts, err := r.ServiceDi.AccountService.ListAccounts(ctx, &pagination)
+	accountFilter, err := accountFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := r.ServiceDi.AccountService.ListAccounts(ctx, accountFilter, accountSort(sort), &pagination)
 	if err != nil {
 		return nil, err
 	}
@@ -37,6 +70,40 @@ func (r *queryResolver) Accounts(ctx context.Context, pagination domain.Paginati
 	}, nil
 }
 
+// AccountConnection is the resolver for the accountConnection field.
+func (r *queryResolver) AccountConnection(ctx context.Context, filter *models.AccountFilterInput, sort []*models.AccountSortInput, first *int, after *string, last *int, before *string) (*domain.AccountConnection, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.AccountConnection")
+	defer span.End()
+
+	pagination, err := cursorPagination(ctx, first, after, last, before)
+	if err != nil {
+		return nil, err
+	}
+
+	accountFilter, err := accountFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.AccountService.ListAccountsByCursor(ctx, accountFilter, accountSort(sort), pagination)
+}
+
+// SearchAccounts is the resolver for the searchAccounts field.
+func (r *queryResolver) SearchAccounts(ctx context.Context, query string, limit *int) ([]*domain.AccountSearchResult, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.SearchAccounts")
+	defer span.End()
+
+	searchLimit := defaultSearchLimit
+	if limit != nil {
+		if *limit < 1 || *limit > maxConnectionSize {
+			return nil, errors.New("limit must be between 1 and 100")
+		}
+		searchLimit = *limit
+	}
+
+	return r.ServiceDi.AccountService.SearchAccounts(ctx, query, searchLimit)
+}
+
 // AccountByEmail is the resolver for the accountByEmail field.
 func (r *queryResolver) AccountByEmail(ctx context.Context, email string) (*domain.Account, error) {
 	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.AccountByEmail")
@@ -45,6 +112,76 @@ func (r *queryResolver) AccountByEmail(ctx context.Context, email string) (*doma
 	return r.ServiceDi.AccountService.GetAccountByEmail(ctx, email)
 }
 
+// AccountByUsername is the resolver for the accountByUsername field.
+func (r *queryResolver) AccountByUsername(ctx context.Context, username string) (*domain.Account, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.AccountByUsername")
+	defer span.End()
+
+	return r.ServiceDi.AccountService.GetAccountByUsername(ctx, username)
+}
+
+// Roles is the resolver for the roles field.
+func (r *queryResolver) Roles(ctx context.Context) ([]*domain.Role, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.Roles")
+	defer span.End()
+
+	return r.ServiceDi.RoleService.ListRoles(ctx)
+}
+
+// AuditEvents is the resolver for the auditEvents field.
+func (r *queryResolver) AuditEvents(ctx context.Context, filter *models.AuditEventFilter, pagination domain.Pagination) (*models.PaginatedAuditEvents, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.AuditEvents")
+	defer span.End()
+
+	auditFilter, err := auditFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := r.ServiceDi.AuditService.ListEvents(ctx, auditFilter, &pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PaginatedAuditEvents{
+		Events: events,
+		PageInfo: &models.PageInfo{
+			Page:  pagination.Page,
+			Size:  pagination.Size,
+			Total: pagination.Total,
+		},
+	}, nil
+}
+
+// Organizations is the resolver for the organizations field.
+func (r *queryResolver) Organizations(ctx context.Context) ([]*domain.Organization, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.Organizations")
+	defer span.End()
+
+	acc, err := helpers.GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.ServiceDi.OrganizationService.ListOrganizations(ctx, acc.Id)
+}
+
+// Organization is the resolver for the organization field.
+func (r *queryResolver) Organization(ctx context.Context, id int) (*domain.Organization, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.Organization")
+	defer span.End()
+
+	return r.ServiceDi.OrganizationService.GetOrganization(ctx, id)
+}
+
+// Invitations is the resolver for the invitations field.
+func (r *queryResolver) Invitations(ctx context.Context, organizationID int) ([]*domain.Invitation, error) {
+	ctx, span := r.Container.Tracer.Start(ctx, "QueryResolver.Invitations")
+	defer span.End()
+
+	return r.ServiceDi.InvitationService.ListInvitations(ctx, organizationID)
+}
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
@@ -0,0 +1,23 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.56
+
+import (
+	"context"
+	"gostarter/internals/delivery/http/graphql/generated"
+	"gostarter/internals/domain"
+)
+
+// CreatedAt is the resolver for the createdAt field.
+func (r *roleResolver) CreatedAt(ctx context.Context, obj *domain.Role) (string, error) {
+	timeString := obj.CreatedAt.Format("2006-01-02 15:04:05")
+
+	return timeString, nil
+}
+
+// Role returns generated.RoleResolver implementation.
+func (r *Resolver) Role() generated.RoleResolver { return &roleResolver{r} }
+
+type roleResolver struct{ *Resolver }
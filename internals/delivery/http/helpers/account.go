@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"errors"
+	"gostarter/internals/domain"
+	"net/http"
+)
+
+// WriteIdentifierError answers an invalid email or username with a 400 and a taken email or username
+// with a 409, and reports whether err was one of them
+func WriteIdentifierError(w http.ResponseWriter, err error) bool {
+	var status int
+	switch {
+	case errors.Is(err, domain.ErrInvalidEmail), errors.Is(err, domain.ErrInvalidUsername):
+		status = http.StatusBadRequest
+	case errors.Is(err, domain.ErrEmailTaken), errors.Is(err, domain.ErrUsernameTaken):
+		status = http.StatusConflict
+	default:
+		return false
+	}
+
+	errorResponse := GeneralResponse{
+		Message: "invalid account details",
+		Errors: []string{
+			err.Error(),
+		},
+	}
+	_ = WriteResponse(w, status, errorResponse)
+	return true
+}
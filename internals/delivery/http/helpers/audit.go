@@ -0,0 +1,20 @@
+package helpers
+
+import (
+	"errors"
+	"time"
+)
+
+// ParseFilterTime parses an RFC 3339 time of a filter, empty values disable the filter
+func ParseFilterTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("invalid " + name + ", expected an RFC 3339 time")
+	}
+
+	return &parsed, nil
+}
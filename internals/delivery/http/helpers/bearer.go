@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// GetBearerToken returns the token of an `Authorization: Bearer` header, or an empty string
+func GetBearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// WriteBearerChallenge rejects a request with an invalid bearer token, RFC 6750
+func WriteBearerChallenge(w http.ResponseWriter, description string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="gostarter", error="invalid_token", error_description="`+description+`"`)
+
+	errorResponse := GeneralResponse{
+		Message: "invalid token",
+		Errors: []string{
+			description,
+		},
+	}
+	_ = WriteResponse(w, http.StatusUnauthorized, errorResponse)
+}
+
+// GetAccessTokenFromContext returns the JWT that authenticated the request, from the header or the cookie
+func GetAccessTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value("accessToken").(string)
+	return token
+}
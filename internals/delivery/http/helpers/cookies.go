@@ -0,0 +1,92 @@
+package helpers
+
+import (
+	"gostarter/infra/config"
+	"net/http"
+)
+
+// SetAuthCookies sets the access and refresh tokens in http only cookies
+func SetAuthCookies(w http.ResponseWriter, accessToken, refreshToken string) {
+	SetAccessCookie(w, accessToken)
+
+	http.SetCookie(w, &http.Cookie{
+		Path:     "/",
+		Name:     config.REFRESH_COOKIE_NAME,
+		Value:    refreshToken,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// SetAccessCookie replaces only the access token, keeping the current refresh token
+func SetAccessCookie(w http.ResponseWriter, accessToken string) {
+	http.SetCookie(w, &http.Cookie{
+		Path:     "/",
+		Name:     config.AUTH_COOKIE_NAME,
+		Value:    accessToken,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// ClearAuthCookies blanks the access and refresh token cookies, and the admin token kept while impersonating
+func ClearAuthCookies(w http.ResponseWriter) {
+	for _, name := range []string{config.AUTH_COOKIE_NAME, config.REFRESH_COOKIE_NAME, config.IMPERSONATOR_COOKIE_NAME} {
+		http.SetCookie(w, &http.Cookie{
+			Path:     "/",
+			Name:     name,
+			Value:    "",
+			MaxAge:   -1,
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+}
+
+// SetImpersonatorCookie keeps the access token of the admin until the impersonation stops
+func SetImpersonatorCookie(w http.ResponseWriter, accessToken string) {
+	http.SetCookie(w, &http.Cookie{
+		Path:     "/",
+		Name:     config.IMPERSONATOR_COOKIE_NAME,
+		Value:    accessToken,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func ClearImpersonatorCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Path:     "/",
+		Name:     config.IMPERSONATOR_COOKIE_NAME,
+		Value:    "",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// SetOIDCFlowCookie keeps the encrypted login flow state until the provider redirects back.
+// It is lax so the browser sends it on the cross site callback.
+func SetOIDCFlowCookie(w http.ResponseWriter, flowState string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Path:     "/auth/oidc",
+		Name:     config.OIDC_COOKIE_NAME,
+		Value:    flowState,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func ClearOIDCFlowCookie(w http.ResponseWriter) {
+	SetOIDCFlowCookie(w, "", -1)
+}
+
+// GetCookieValue returns the value of the cookie, or an empty string if it is not set
+func GetCookieValue(r *http.Request, name string) string {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
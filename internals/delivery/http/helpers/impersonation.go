@@ -0,0 +1,28 @@
+package helpers
+
+import (
+	"context"
+	"gostarter/infra/observability"
+	"gostarter/internals/domain"
+	"strconv"
+)
+
+// WithImpersonation marks the context of an impersonation session, so services refuse sensitive
+// operations and every log record and span of the request is tagged with both identities
+func WithImpersonation(ctx context.Context, account *domain.Account) context.Context {
+	ctx = domain.WithImpersonator(ctx, account.Impersonator.Id)
+	ctx = observability.WithTag(ctx, "impersonator.id", strconv.Itoa(account.Impersonator.Id))
+	return observability.WithTag(ctx, "impersonated.id", strconv.Itoa(account.Id))
+}
+
+// GetImpersonatorFromContext returns the admin acting in an impersonation session, domain.ErrNotImpersonating otherwise
+func GetImpersonatorFromContext(ctx context.Context) (*domain.Account, error) {
+	acc, err := GetAccountFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !acc.IsImpersonated() {
+		return nil, domain.ErrNotImpersonating
+	}
+	return acc.Impersonator, nil
+}
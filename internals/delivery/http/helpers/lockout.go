@@ -0,0 +1,47 @@
+package helpers
+
+import (
+	"errors"
+	"gostarter/internals/domain"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// GetClientIP returns the address of the client, the RealIP middleware already applied forwarding headers
+func GetClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// SetRetryAfter sets the Retry-After header of a lockout error and reports whether err was one
+func SetRetryAfter(w http.ResponseWriter, err error) bool {
+	var lockoutErr *domain.LockoutError
+	if !errors.As(err, &lockoutErr) {
+		return false
+	}
+
+	seconds := int(math.Ceil(lockoutErr.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	return true
+}
+
+// WriteLockout answers a locked login with a 429 and reports whether err was a lockout
+func WriteLockout(w http.ResponseWriter, err error) bool {
+	if !SetRetryAfter(w, err) {
+		return false
+	}
+
+	errorResponse := GeneralResponse{
+		Message: "too many attempts",
+		Errors: []string{
+			err.Error(),
+		},
+	}
+	_ = WriteResponse(w, http.StatusTooManyRequests, errorResponse)
+	return true
+}
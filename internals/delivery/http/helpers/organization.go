@@ -0,0 +1,21 @@
+package helpers
+
+import (
+	"context"
+	"gostarter/internals/domain"
+)
+
+// WithOrganization puts the membership of the selected organization in the context and scopes it to the organization
+func WithOrganization(ctx context.Context, member *domain.OrganizationMember) context.Context {
+	ctx = context.WithValue(ctx, "organization", member)
+	return domain.WithTenant(ctx, member.OrganizationId)
+}
+
+// GetOrganizationFromContext returns the membership of the selected organization, domain.ErrNoTenant without one
+func GetOrganizationFromContext(ctx context.Context) (*domain.OrganizationMember, error) {
+	member, ok := ctx.Value("organization").(*domain.OrganizationMember)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+	return member, nil
+}
@@ -0,0 +1,114 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"gostarter/internals/domain"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WritePaginationHeaders sets the X-Total-Count header and a Link header (RFC 8288) with the
+// first, prev, next and last pages. The links keep the other query parameters of the request.
+func WritePaginationHeaders(w http.ResponseWriter, r *http.Request, pagination *domain.Pagination) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(pagination.Total))
+
+	lastPage := 1
+	if pagination.Size > 0 && pagination.Total > 0 {
+		lastPage = (pagination.Total + pagination.Size - 1) / pagination.Size
+	}
+
+	pageURL := func(page int) string {
+		query := r.URL.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(pagination.Size))
+		return (&url.URL{Path: r.URL.Path, RawQuery: query.Encode()}).String()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if pagination.Page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(min(pagination.Page-1, lastPage))))
+	}
+	if pagination.Page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(pagination.Page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastPage)))
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// GetCursorPaginationParams reads the first, after, last, before and total query parameters,
+// it reports false when the request pages with none of the cursor parameters
+func GetCursorPaginationParams(r *http.Request) (domain.CursorPagination, bool, error) {
+	query := r.URL.Query()
+
+	pagination := domain.CursorPagination{
+		After:  query.Get("after"),
+		Before: query.Get("before"),
+	}
+	if !query.Has("first") && !query.Has("last") && pagination.After == "" && pagination.Before == "" {
+		return pagination, false, nil
+	}
+
+	sizes := map[string]*int{
+		"first": &pagination.First,
+		"last":  &pagination.Last,
+	}
+	for name, size := range sizes {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return pagination, true, errors.New("invalid " + name)
+		}
+		*size = parsed
+	}
+
+	if total := query.Get("total"); total != "" {
+		withTotal, err := strconv.ParseBool(total)
+		if err != nil {
+			return pagination, true, errors.New("invalid total")
+		}
+		pagination.WithTotal = withTotal
+	}
+
+	// The default size of listings by page
+	pagination.SetDefaultSize(10)
+	return pagination, true, nil
+}
+
+// WriteCursorPaginationHeaders sets a Link header (RFC 8288) with the first and last pages and
+// the next and prev pages when there are some, and X-Total-Count when the total was counted
+func WriteCursorPaginationHeaders(w http.ResponseWriter, r *http.Request, pageInfo *domain.ConnectionPageInfo, size int, total *int) {
+	if total != nil {
+		w.Header().Set("X-Total-Count", strconv.Itoa(*total))
+	}
+
+	pageURL := func(direction, cursorName, cursor string) string {
+		query := r.URL.Query()
+		for _, name := range []string{"first", "after", "last", "before", "page", "limit"} {
+			query.Del(name)
+		}
+		query.Set(direction, strconv.Itoa(size))
+		if cursor != "" {
+			query.Set(cursorName, cursor)
+		}
+		return (&url.URL{Path: r.URL.Path, RawQuery: query.Encode()}).String()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL("first", "", ""))}
+	if pageInfo.HasPreviousPage && pageInfo.StartCursor != nil {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL("last", "before", *pageInfo.StartCursor)))
+	}
+	if pageInfo.HasNextPage && pageInfo.EndCursor != nil {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL("first", "after", *pageInfo.EndCursor)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL("last", "", "")))
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
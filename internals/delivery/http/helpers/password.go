@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"errors"
+	"gostarter/internals/domain"
+	"net/http"
+)
+
+// PasswordPolicyResponse reports each rule a password failed
+type PasswordPolicyResponse struct {
+	Message    string                     `json:"message"`
+	Errors     []string                   `json:"errors"`
+	Violations []domain.PasswordViolation `json:"violations"`
+}
+
+// WritePasswordPolicyError answers a rejected password with a 400 and reports whether err was a policy error
+func WritePasswordPolicyError(w http.ResponseWriter, err error) bool {
+	var policyErr *domain.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	messages := make([]string, len(policyErr.Violations))
+	for i, violation := range policyErr.Violations {
+		messages[i] = violation.Message
+	}
+
+	errorResponse := PasswordPolicyResponse{
+		Message:    domain.ErrPasswordPolicy.Error(),
+		Errors:     messages,
+		Violations: policyErr.Violations,
+	}
+	_ = WriteResponse(w, http.StatusBadRequest, errorResponse)
+	return true
+}
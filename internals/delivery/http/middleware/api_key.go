@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"net/http"
+	"strings"
+)
+
+// APIKeyMiddleware authenticates requests carrying a personal access token in
+// `Authorization: Bearer gsk_...`. Invalid keys are rejected like invalid bearer JWTs.
+func APIKeyMiddleware(apiKeyService domain.APIKeyService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			token := helpers.GetBearerToken(r)
+			if !strings.HasPrefix(token, domain.API_KEY_PREFIX) || isAuth(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			account, err := apiKeyService.Authenticate(r.Context(), token)
+			if errors.Is(err, domain.ErrAPIKeyNotFound) || errors.Is(err, domain.ErrAPIKeyExpired) ||
+				errors.Is(err, domain.ErrAccountDisabled) {
+				helpers.WriteBearerChallenge(w, err.Error())
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "account", account)
+			r = r.WithContext(ctx)
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
@@ -6,26 +6,47 @@ import (
 	"gostarter/internals/delivery/http/helpers"
 	"gostarter/internals/domain"
 	"net/http"
+	"strings"
 )
 
-func JWTMiddleware(tokenService domain.TokenService) func(http.Handler) http.Handler {
+// JWTMiddleware authenticates requests with an access token from the auth cookie or an
+// `Authorization: Bearer` header, precedence decides which is read when both are sent.
+// Invalid bearer tokens are rejected with a 401, invalid cookies are treated as anonymous so
+// browsers can still reach the login and refresh routes.
+func JWTMiddleware(tokenService domain.TokenService, precedence string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		hfn := func(w http.ResponseWriter, r *http.Request) {
-			userJWT, err := r.Cookie(config.AUTH_COOKIE_NAME)
-			if err != nil {
+			userJWT, fromHeader := accessToken(r, precedence)
+			if userJWT == "" {
 				next.ServeHTTP(w, r)
 				return
 			}
 
 			// Send account to context
-			account, err := tokenService.ExtractAccount(userJWT.Value)
+			account, err := tokenService.ExtractAccount(userJWT)
 			if err != nil {
+				if fromHeader {
+					helpers.WriteBearerChallenge(w, "the access token is invalid or expired")
+					return
+				}
 				next.ServeHTTP(w, r)
 				return
 			}
 
+			// Logged out tokens are rejected, or treated as anonymous when they come from the cookie
 			ctx := r.Context()
+			revoked, err := tokenService.IsRevoked(ctx, userJWT)
+			if err != nil || revoked {
+				if fromHeader {
+					helpers.WriteBearerChallenge(w, "the access token was revoked")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			ctx = context.WithValue(ctx, "account", account)
+			ctx = context.WithValue(ctx, "accessToken", userJWT)
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
@@ -35,9 +56,28 @@ func JWTMiddleware(tokenService domain.TokenService) func(http.Handler) http.Han
 	}
 }
 
+// accessToken returns the JWT of the request and whether it came from the header.
+// Bearer values that are not JWTs, API keys and OAuth access tokens, are left to their own handlers.
+func accessToken(r *http.Request, precedence string) (string, bool) {
+	header := helpers.GetBearerToken(r)
+	if strings.HasPrefix(header, domain.API_KEY_PREFIX) || strings.Count(header, ".") != 2 {
+		header = ""
+	}
+	cookie := helpers.GetCookieValue(r, config.AUTH_COOKIE_NAME)
+
+	if precedence == config.TOKEN_PRECEDENCE_COOKIE && cookie != "" {
+		return cookie, false
+	}
+	if header != "" {
+		return header, true
+	}
+	return cookie, false
+}
+
 func IsAuthenticated(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !isAuth(r.Context()) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gostarter"`)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
@@ -45,6 +85,57 @@ func IsAuthenticated(next http.Handler) http.Handler {
 	})
 }
 
+// RequirePermission rejects sessions whose effective roles are not granted the permission
+func RequirePermission(permissionService domain.PermissionService, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			acc, err := helpers.GetAccountFromContext(r.Context())
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gostarter"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			allowed, err := permissionService.HasPermission(r.Context(), acc, permission)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
+
+// RequireRole rejects sessions that do not act with the role, API key sessions need it in their scopes
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			acc, err := helpers.GetAccountFromContext(r.Context())
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gostarter"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if !acc.HasRole(role) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
+
 func isAuth(ctx context.Context) bool {
 	acc, err := helpers.GetAccountFromContext(ctx)
 	return acc != nil && err == nil
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"gostarter/internals/delivery/http/helpers"
+	"log/slog"
+	"net/http"
+)
+
+// ImpersonationMiddleware tags the requests of impersonation sessions with the actor and the
+// impersonated account and logs each of them, so every action taken while impersonating can be
+// told apart in logs and traces.
+func ImpersonationMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	logger = logger.With("path", "ImpersonationMiddleware")
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			acc, err := helpers.GetAccountFromContext(r.Context())
+			if err != nil || !acc.IsImpersonated() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The tags of the context name both identities on this record
+			ctx := helpers.WithImpersonation(r.Context(), acc)
+			logger.InfoContext(ctx, "impersonated request", "method", r.Method, "uri", r.URL.RequestURI())
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
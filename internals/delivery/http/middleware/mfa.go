@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"net/http"
+	"strings"
+)
+
+// mfaPendingAllowedPaths are reachable by accounts that must use MFA but have a session without it
+var mfaPendingAllowedPaths = map[string]bool{
+	"/health":                          true,
+	"/profile":                         true,
+	"/logout":                          true,
+	"/logout-all":                      true,
+	"/impersonation/stop":              true,
+	"/verify-email":                    true,
+	"/verify-email/resend":             true,
+	"/mfa/setup":                       true,
+	"/api/v1/auth/profile":             true,
+	"/api/v1/auth/logout":              true,
+	"/api/v1/auth/logout-all":          true,
+	"/api/v1/auth/impersonation/stop":  true,
+	"/api/v1/auth/refresh":             true,
+	"/api/v1/auth/verify-email":        true,
+	"/api/v1/auth/verify-email/resend": true,
+	"/api/v1/auth/mfa/enroll":          true,
+	"/api/v1/auth/mfa/confirm":         true,
+}
+
+// RequireMFA limits sessions of accounts whose role requires MFA to the enrollment
+// routes until the session has passed a second factor.
+func RequireMFA(mfaService domain.MFAService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			acc, err := helpers.GetAccountFromContext(r.Context())
+			if err != nil || acc.MFAVerified || !mfaService.IsRequired(acc) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			path := r.URL.Path
+			if mfaPendingAllowedPaths[path] || strings.HasPrefix(path, "/static/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if strings.HasPrefix(path, "/api/") || path == "/query" {
+				errorResponse := helpers.GeneralResponse{
+					Message: "mfa required",
+					Errors: []string{
+						domain.ErrMFARequired.Error(),
+					},
+				}
+				_ = helpers.WriteResponse(w, http.StatusForbidden, errorResponse)
+				return
+			}
+
+			http.Redirect(w, r, "/mfa/setup", http.StatusSeeOther)
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"errors"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"net/http"
+	"strconv"
+)
+
+// OrganizationMiddleware selects the organization named by the X-Org-ID header. The account
+// must be a member, its membership is put in the context and the request is scoped to the
+// organization as its tenant. Requests without the header act outside any organization.
+func OrganizationMiddleware(organizationService domain.OrganizationService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(domain.ORG_ID_HEADER)
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			organizationId, err := strconv.Atoi(header)
+			if err != nil || organizationId <= 0 {
+				http.Error(w, "invalid "+domain.ORG_ID_HEADER+" header", http.StatusBadRequest)
+				return
+			}
+
+			acc, err := helpers.GetAccountFromContext(r.Context())
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="gostarter"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			member, err := organizationService.GetMembership(r.Context(), organizationId, acc.Id)
+			if errors.Is(err, domain.ErrNotOrganizationMember) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			r = r.WithContext(helpers.WithOrganization(r.Context(), member))
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
+
+// RequireOrgRole rejects requests without a selected organization, or whose membership is below the role
+func RequireOrgRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			member, err := helpers.GetOrganizationFromContext(r.Context())
+			if err != nil {
+				http.Error(w, "missing "+domain.ORG_ID_HEADER+" header", http.StatusBadRequest)
+				return
+			}
+
+			if !member.HasRole(role) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
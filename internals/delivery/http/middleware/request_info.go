@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestInfo attaches the client address, user agent and request id to the context, so the
+// service layer can record them with audit events. It runs after RequestID and RealIP.
+func RequestInfo(next http.Handler) http.Handler {
+	hfn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := domain.WithRequestInfo(r.Context(), domain.RequestInfo{
+			IP:        helpers.GetClientIP(r),
+			UserAgent: r.UserAgent(),
+			RequestId: middleware.GetReqID(r.Context()),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+
+	return http.HandlerFunc(hfn)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"gostarter/infra/config"
+	"gostarter/internals/delivery/http/helpers"
+	"net/http"
+	"strings"
+)
+
+// unverifiedAllowedPaths are reachable by accounts with an unverified email under the profile only policy
+var unverifiedAllowedPaths = map[string]bool{
+	"/health":                          true,
+	"/profile":                         true,
+	"/logout":                          true,
+	"/logout-all":                      true,
+	"/impersonation/stop":              true,
+	"/verify-email":                    true,
+	"/verify-email/resend":             true,
+	"/verify-email/change":             true,
+	"/api/v1/auth/profile":             true,
+	"/api/v1/auth/logout":              true,
+	"/api/v1/auth/logout-all":          true,
+	"/api/v1/auth/impersonation/stop":  true,
+	"/api/v1/auth/refresh":             true,
+	"/api/v1/auth/verify-email":        true,
+	"/api/v1/auth/verify-email/resend": true,
+	// A mistyped email is only fixed by changing it
+	"/api/v1/account/email":         true,
+	"/api/v1/account/email/confirm": true,
+	// Account holders can always download their data
+	"/api/v1/account/export": true,
+}
+
+// unverifiedAllowedRoutes are the method and path of routes sharing their path with routes that stay restricted
+var unverifiedAllowedRoutes = map[string]bool{
+	// Deleting the account requires the password again
+	http.MethodDelete + " /api/v1/account": true,
+}
+
+// RestrictUnverified limits accounts that have not verified their email to the profile
+// and verification routes when the policy is UNVERIFIED_POLICY_PROFILE_ONLY.
+func RestrictUnverified(policy string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if policy != config.UNVERIFIED_POLICY_PROFILE_ONLY {
+			return next
+		}
+
+		hfn := func(w http.ResponseWriter, r *http.Request) {
+			acc, err := helpers.GetAccountFromContext(r.Context())
+			if err != nil || acc.IsEmailVerified() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			path := r.URL.Path
+			if unverifiedAllowedPaths[path] || unverifiedAllowedRoutes[r.Method+" "+path] || strings.HasPrefix(path, "/static/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if strings.HasPrefix(path, "/api/") || path == "/query" {
+				errorResponse := helpers.GeneralResponse{
+					Message: "email not verified",
+					Errors: []string{
+						"verify your email address to access this resource",
+					},
+				}
+				_ = helpers.WriteResponse(w, http.StatusForbidden, errorResponse)
+				return
+			}
+
+			http.Redirect(w, r, "/profile", http.StatusSeeOther)
+		}
+
+		return http.HandlerFunc(hfn)
+	}
+}
@@ -11,11 +11,21 @@ import (
 func accountApiRoutes(r chi.Router, accountHandler domain.AccountHandler) {
 	r.Post("/auth/register", accountHandler.Register)
 	r.Post("/auth/login", accountHandler.Login)
+	r.Post("/auth/refresh", accountHandler.Refresh)
+	r.Post("/auth/verify-email", accountHandler.VerifyEmail)
+	r.Post("/auth/verify-email/resend", accountHandler.ResendVerification)
+	r.Post("/account/email/confirm", accountHandler.ConfirmEmailChange)
 
 	r.Group(func(r chi.Router) {
 		r.Use(custommiddleware.IsAuthenticated)
 		r.Post("/auth/logout", accountHandler.Logout)
+		r.Post("/auth/logout-all", accountHandler.LogoutAll)
 		r.Get("/auth/profile", accountHandler.Profile)
+		r.Get("/account/export", accountHandler.Export)
+		r.Patch("/account", accountHandler.UpdateAccount)
+		r.Delete("/account", accountHandler.DeleteAccount)
+		r.Post("/account/password", accountHandler.ChangePassword)
+		r.Post("/account/email", accountHandler.ChangeEmail)
 	})
 }
 
@@ -24,7 +34,14 @@ func accountWebRoutes(r chi.Router, handler *web.AccountWebHandler) {
 	r.Post("/register", handler.PostRegisterMember)
 	r.With(custommiddleware.RedirectIfLoggedIn("/profile")).Get("/login", handler.GetLogin)
 	r.Post("/login", handler.PostLogin)
+	r.Post("/login/mfa", handler.PostLoginMFA)
+	r.Get("/auth/oidc/{provider}", handler.GetOIDCLogin)
+	r.Get("/auth/oidc/{provider}/callback", handler.GetOIDCCallback)
 	r.With(custommiddleware.IsAuthenticated).Get("/profile", handler.GetProfile)
+	r.Get("/verify-email", handler.GetVerifyEmail)
+	r.Get("/verify-email/change", handler.GetConfirmEmailChange)
+	r.Post("/verify-email/resend", handler.PostResendVerification)
 	r.Post("/logout", handler.PostLogout)
+	r.With(custommiddleware.IsAuthenticated).Post("/logout-all", handler.PostLogoutAll)
 
 }
@@ -0,0 +1,62 @@
+package routing
+
+import (
+	custommiddleware "gostarter/internals/delivery/http/middleware"
+	"gostarter/internals/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func adminApiRoutes(
+	r chi.Router,
+	permissionService domain.PermissionService,
+	lockoutHandler domain.LockoutHandler,
+	roleHandler domain.RoleHandler,
+	auditHandler domain.AuditHandler,
+	adminAccountHandler domain.AdminAccountHandler,
+) {
+	r.Group(func(r chi.Router) {
+		r.Use(custommiddleware.IsAuthenticated)
+		r.With(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_ACCOUNTS_UNLOCK)).
+			Post("/admin/accounts/{id}/unlock", lockoutHandler.Unlock)
+
+		r.Group(func(r chi.Router) {
+			r.Use(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_ROLES_MANAGE))
+			r.Post("/admin/roles", roleHandler.Create)
+			r.Get("/admin/roles", roleHandler.List)
+			r.Post("/admin/roles/{role}/permissions", roleHandler.GrantPermission)
+			r.Delete("/admin/roles/{role}/permissions/{permission}", roleHandler.RevokePermission)
+			r.Post("/admin/accounts/{id}/roles", roleHandler.AssignRole)
+			r.Delete("/admin/accounts/{id}/roles/{role}", roleHandler.UnassignRole)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_AUDIT_READ))
+			r.Get("/admin/audit-events", auditHandler.List)
+			r.Get("/admin/audit-events/export", auditHandler.Export)
+		})
+
+		// Account administration is reserved to admins, on top of the permission of each route
+		r.Group(func(r chi.Router) {
+			r.Use(custommiddleware.RequireRole(domain.ROLE_ADMIN))
+
+			r.Group(func(r chi.Router) {
+				r.Use(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_ACCOUNTS_READ))
+				r.Get("/admin/accounts", adminAccountHandler.List)
+				r.Get("/admin/accounts/search", adminAccountHandler.Search)
+				r.Get("/admin/accounts/{id}", adminAccountHandler.Get)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_ACCOUNTS_WRITE))
+				r.Patch("/admin/accounts/{id}", adminAccountHandler.Update)
+				r.Delete("/admin/accounts/{id}", adminAccountHandler.Delete)
+				r.Post("/admin/accounts/{id}/disable", adminAccountHandler.Disable)
+				r.Post("/admin/accounts/{id}/enable", adminAccountHandler.Enable)
+			})
+
+			r.With(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_ROLES_MANAGE)).
+				Put("/admin/accounts/{id}/roles", adminAccountHandler.SetRoles)
+		})
+	})
+}
@@ -0,0 +1,17 @@
+package routing
+
+import (
+	custommiddleware "gostarter/internals/delivery/http/middleware"
+	"gostarter/internals/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func apiKeyApiRoutes(r chi.Router, handler domain.APIKeyHandler) {
+	r.Group(func(r chi.Router) {
+		r.Use(custommiddleware.IsAuthenticated)
+		r.Post("/auth/api-keys", handler.Create)
+		r.Get("/auth/api-keys", handler.List)
+		r.Delete("/auth/api-keys/{id}", handler.Revoke)
+	})
+}
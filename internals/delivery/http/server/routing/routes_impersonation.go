@@ -0,0 +1,27 @@
+package routing
+
+import (
+	custommiddleware "gostarter/internals/delivery/http/middleware"
+	"gostarter/internals/delivery/http/web"
+	"gostarter/internals/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func impersonationApiRoutes(r chi.Router, permissionService domain.PermissionService, handler domain.ImpersonationHandler) {
+	r.Group(func(r chi.Router) {
+		r.Use(custommiddleware.IsAuthenticated)
+		r.With(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_ACCOUNTS_IMPERSONATE)).
+			Post("/admin/accounts/{id}/impersonate", handler.Start)
+		r.Post("/auth/impersonation/stop", handler.Stop)
+	})
+}
+
+func impersonationWebRoutes(r chi.Router, permissionService domain.PermissionService, handler *web.ImpersonationWebHandler) {
+	r.Group(func(r chi.Router) {
+		r.Use(custommiddleware.IsAuthenticated)
+		r.With(custommiddleware.RequirePermission(permissionService, domain.PERMISSION_ACCOUNTS_IMPERSONATE)).
+			Post("/admin/accounts/{id}/impersonate", handler.PostImpersonate)
+		r.Post("/impersonation/stop", handler.PostStopImpersonation)
+	})
+}
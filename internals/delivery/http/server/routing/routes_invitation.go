@@ -0,0 +1,30 @@
+package routing
+
+import (
+	custommiddleware "gostarter/internals/delivery/http/middleware"
+	"gostarter/internals/delivery/http/web"
+	"gostarter/internals/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func invitationApiRoutes(r chi.Router, handler domain.InvitationHandler) {
+	r.Post("/invitations/register", handler.Register)
+
+	r.Group(func(r chi.Router) {
+		r.Use(custommiddleware.IsAuthenticated)
+		r.Post("/invitations/accept", handler.Accept)
+
+		r.Group(func(r chi.Router) {
+			r.Use(custommiddleware.RequireOrgRole(domain.ORG_ROLE_ADMIN))
+			r.Post("/organization/invitations", handler.Create)
+			r.Get("/organization/invitations", handler.List)
+			r.Delete("/organization/invitations/{id}", handler.Revoke)
+		})
+	})
+}
+
+func invitationWebRoutes(r chi.Router, handler *web.InvitationWebHandler) {
+	r.Get("/invitations/accept", handler.GetAcceptInvitation)
+	r.Post("/invitations/accept", handler.PostAcceptInvitation)
+}
@@ -0,0 +1,31 @@
+package routing
+
+import (
+	custommiddleware "gostarter/internals/delivery/http/middleware"
+	"gostarter/internals/delivery/http/web"
+	"gostarter/internals/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func mfaApiRoutes(r chi.Router, handler domain.MFAHandler) {
+	r.Post("/auth/mfa/verify", handler.Verify)
+
+	r.Group(func(r chi.Router) {
+		r.Use(custommiddleware.IsAuthenticated)
+		r.Post("/auth/mfa/enroll", handler.Enroll)
+		r.Post("/auth/mfa/confirm", handler.Confirm)
+		r.Post("/auth/mfa/disable", handler.Disable)
+		r.Post("/auth/mfa/recovery-codes", handler.RegenerateRecoveryCodes)
+	})
+}
+
+func mfaWebRoutes(r chi.Router, handler *web.MFAWebHandler) {
+	r.Group(func(r chi.Router) {
+		r.Use(custommiddleware.IsAuthenticated)
+		r.Get("/mfa/setup", handler.GetMFASetup)
+		r.Post("/mfa/setup", handler.PostMFASetup)
+		r.Post("/mfa/disable", handler.PostMFADisable)
+		r.Post("/mfa/recovery-codes", handler.PostMFARecoveryCodes)
+	})
+}
@@ -0,0 +1,24 @@
+package routing
+
+import (
+	custommiddleware "gostarter/internals/delivery/http/middleware"
+	"gostarter/internals/delivery/http/web"
+	"gostarter/internals/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func oauthRoutes(r chi.Router, handler domain.OAuthHandler) {
+	r.Get("/.well-known/openid-configuration", handler.Discovery)
+	r.Get("/.well-known/jwks.json", handler.JWKS)
+	r.Post("/oauth/token", handler.Token)
+	r.Get("/oauth/userinfo", handler.UserInfo)
+	r.Post("/oauth/userinfo", handler.UserInfo)
+	r.Post("/oauth/introspect", handler.Introspect)
+	r.Post("/oauth/revoke", handler.Revoke)
+}
+
+func oauthWebRoutes(r chi.Router, handler *web.OAuthWebHandler) {
+	r.Get("/oauth/authorize", handler.GetAuthorize)
+	r.With(custommiddleware.IsAuthenticated).Post("/oauth/authorize", handler.PostAuthorize)
+}
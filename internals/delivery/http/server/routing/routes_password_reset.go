@@ -0,0 +1,21 @@
+package routing
+
+import (
+	custommiddleware "gostarter/internals/delivery/http/middleware"
+	"gostarter/internals/delivery/http/web"
+	"gostarter/internals/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func passwordResetApiRoutes(r chi.Router, handler domain.PasswordResetHandler) {
+	r.Post("/auth/password-reset/request", handler.RequestPasswordReset)
+	r.Post("/auth/password-reset/confirm", handler.ConfirmPasswordReset)
+}
+
+func passwordResetWebRoutes(r chi.Router, handler *web.PasswordResetWebHandler) {
+	r.With(custommiddleware.RedirectIfLoggedIn("/profile")).Get("/forgot-password", handler.GetForgotPassword)
+	r.Post("/forgot-password", handler.PostForgotPassword)
+	r.Get("/reset-password", handler.GetResetPassword)
+	r.Post("/reset-password", handler.PostResetPassword)
+}
@@ -6,7 +6,6 @@ import (
 	custommiddleware "gostarter/internals/delivery/http/middleware"
 	"gostarter/internals/di"
 	"net/http"
-	"strings"
 
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/go-chi/chi/v5"
@@ -28,19 +27,26 @@ func SetupRoutes(
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RealIP)
+	r.Use(custommiddleware.RequestInfo)
 	r.Use(middleware.Throttle(12000))
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Org-ID"},
+		ExposedHeaders:   []string{"WWW-Authenticate", "Link", "X-Total-Count"},
 		AllowCredentials: true,
 	}))
 
 	r.Use(custommiddleware.NewLatencyMiddleware(container.Meter))
 	r.Use(custommiddleware.NewCounterMiddleware(container.Meter))
 
-	r.Use(custommiddleware.JWTMiddleware(serviceDi.TokenService))
+	r.Use(custommiddleware.JWTMiddleware(serviceDi.TokenService, cfg.JWT.TokenPrecedence))
+	r.Use(custommiddleware.ImpersonationMiddleware(container.Logger))
+	r.Use(custommiddleware.APIKeyMiddleware(serviceDi.APIKeyService))
+	r.Use(custommiddleware.RestrictUnverified(cfg.Auth.UnverifiedPolicy))
+	r.Use(custommiddleware.RequireMFA(serviceDi.MFAService))
+	r.Use(custommiddleware.OrganizationMiddleware(serviceDi.OrganizationService))
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -53,14 +59,35 @@ func SetupRoutes(
 
 	// Web Routes
 	accountWebRoutes(r, handlerDi.AccountWebHandler)
+	passwordResetWebRoutes(r, handlerDi.PasswordResetWebHandler)
+	mfaWebRoutes(r, handlerDi.MFAWebHandler)
+	oauthWebRoutes(r, handlerDi.OAuthWebHandler)
+	invitationWebRoutes(r, handlerDi.InvitationWebHandler)
+	impersonationWebRoutes(r, serviceDi.PermissionService, handlerDi.ImpersonationWebHandler)
+
+	// OAuth2 and OpenID Connect provider routes
+	oauthRoutes(r, handlerDi.OAuthHandler)
 
 	// API Routes
 	r.Route("/api/v1", func(r chi.Router) {
 		// Routes
 		accountApiRoutes(r, handlerDi.AccountHandler)
+		passwordResetApiRoutes(r, handlerDi.PasswordResetHandler)
+		mfaApiRoutes(r, handlerDi.MFAHandler)
+		apiKeyApiRoutes(r, handlerDi.APIKeyHandler)
+		adminApiRoutes(
+			r,
+			serviceDi.PermissionService,
+			handlerDi.LockoutHandler,
+			handlerDi.RoleHandler,
+			handlerDi.AuditHandler,
+			handlerDi.AdminAccountHandler,
+		)
+		invitationApiRoutes(r, handlerDi.InvitationHandler)
+		impersonationApiRoutes(r, serviceDi.PermissionService, handlerDi.ImpersonationHandler)
 	})
 
-	baseUrl := "http://" + strings.TrimPrefix(cfg.Server.BaseURL, "http://")
+	baseUrl := cfg.Server.GetBaseURL()
 
 	// Swagger API docs
 	r.Get("/docs", func(w http.ResponseWriter, r *http.Request) {
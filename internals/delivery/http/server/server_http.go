@@ -5,6 +5,7 @@ import (
 	"gostarter/infra"
 	"gostarter/internals/delivery/http/graphql"
 	"gostarter/internals/delivery/http/server/routing"
+	"gostarter/internals/delivery/worker"
 	"gostarter/internals/di"
 
 	"context"
@@ -13,13 +14,32 @@ import (
 
 type HttpServer struct {
 	server *http.Server
+
+	revocationPruner   *worker.RevocationPruner
+	keyringReloader    *worker.KeyringReloader
+	loginAttemptPruner *worker.LoginAttemptPruner
+	auditPruner        *worker.AuditPruner
+	accountPurger      *worker.AccountPurger
+	stopWorkers        context.CancelFunc
 }
 
 func (s *HttpServer) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopWorkers = cancel
+
+	go s.revocationPruner.Start(ctx)
+	go s.keyringReloader.Start(ctx)
+	go s.loginAttemptPruner.Start(ctx)
+	go s.auditPruner.Start(ctx)
+	go s.accountPurger.Start(ctx)
+
 	return s.server.ListenAndServe()
 }
 
 func (s *HttpServer) Stop(ctx context.Context) error {
+	if s.stopWorkers != nil {
+		s.stopWorkers()
+	}
 	return s.server.Shutdown(ctx)
 }
 
@@ -42,5 +62,10 @@ func NewHttpServer(container *infra.Container) *HttpServer {
 			Addr:    ":" + container.Cfg.Server.Port,
 			Handler: r,
 		},
+		revocationPruner:   worker.NewRevocationPruner(container, serviceDi.TokenService),
+		keyringReloader:    worker.NewKeyringReloader(container, serviceDi.TokenService),
+		loginAttemptPruner: worker.NewLoginAttemptPruner(container, serviceDi.LockoutService),
+		auditPruner:        worker.NewAuditPruner(container, serviceDi.AuditService),
+		accountPurger:      worker.NewAccountPurger(container, serviceDi.AccountService),
 	}
 }
@@ -1,29 +1,49 @@
 package web
 
 import (
-	"context"
+	"errors"
+	"gostarter/infra"
 	"gostarter/infra/config"
 	"gostarter/internals/delivery/http/helpers"
 	"gostarter/internals/domain"
 	"gostarter/pkg/rendering"
+	"log/slog"
 	"net/http"
+	"strings"
 )
 
 type AccountWebHandler struct {
-	accountService domain.AccountService
-	tokenService   domain.TokenService
-	renderer       *rendering.HtmlRenderer
+	logger *slog.Logger
+
+	unverifiedPolicy string
+
+	accountService      domain.AccountService
+	tokenService        domain.TokenService
+	verificationService domain.VerificationService
+	mfaService          domain.MFAService
+	oidcService         domain.OIDCService
+	renderer            *rendering.HtmlRenderer
 }
 
 func NewAccountWebHandler(
+	container *infra.Container,
 	tokenService domain.TokenService,
 	accountService domain.AccountService,
+	verificationService domain.VerificationService,
+	mfaService domain.MFAService,
+	oidcService domain.OIDCService,
 ) *AccountWebHandler {
 	renderer := rendering.NewHtmlRenderer(config.TEMPLATE_DIR)
+	logger := container.Logger.With("path", "AccountWebHandler")
 	return &AccountWebHandler{
-		accountService: accountService,
-		tokenService:   tokenService,
-		renderer:       renderer,
+		logger:              logger,
+		unverifiedPolicy:    container.Cfg.Auth.UnverifiedPolicy,
+		accountService:      accountService,
+		tokenService:        tokenService,
+		verificationService: verificationService,
+		mfaService:          mfaService,
+		oidcService:         oidcService,
+		renderer:            renderer,
 	}
 }
 
@@ -59,24 +79,60 @@ func (h *AccountWebHandler) PostRegisterMember(w http.ResponseWriter, r *http.Re
 
 	// Get the form data
 	email := r.Form.Get("email")
+	username := r.Form.Get("username")
 	password := r.Form.Get("password")
 
 	acc := &domain.Account{
-		Username: email,
+		Username: username,
 		Email:    email,
 		Password: password,
 		Roles:    []string{domain.ROLE_USER},
 	}
 
 	// Register the member
-	err = h.accountService.Register(context.Background(), acc)
+	err = h.accountService.Register(r.Context(), acc)
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		w.WriteHeader(http.StatusBadRequest)
+		data := map[string]interface{}{
+			"Title":      "Register",
+			"Violations": policyErr.Violations,
+		}
+		err = h.renderer.RenderWithLayout(
+			w, "layout/main.html", "register.html", data,
+		)
+		if err != nil {
+			h.logger.Error("failed to render register", "error", err)
+		}
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidUsername) || errors.Is(err, domain.ErrEmailTaken) || errors.Is(err, domain.ErrUsernameTaken) {
+		w.WriteHeader(http.StatusBadRequest)
+		data := map[string]interface{}{
+			"Title": "Register",
+			"Error": err.Error(),
+		}
+		err = h.renderer.RenderWithLayout(
+			w, "layout/main.html", "register.html", data,
+		)
+		if err != nil {
+			h.logger.Error("failed to render register", "error", err)
+		}
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	// Unverified accounts cannot login until the email is confirmed
+	if !acc.IsEmailVerified() && h.unverifiedPolicy == config.UNVERIFIED_POLICY_BLOCK_LOGIN {
+		h.renderVerifyEmail(w, "We sent a verification link to "+acc.Email+". Verify your email to login.", false)
+		return
+	}
+
 	// Generate JWT
-	token, err := h.tokenService.GenerateJWT(acc.Id, acc.Username, acc.Roles)
+	token, err := h.tokenService.GenerateJWT(acc)
 	if err != nil {
 		errorResponse := helpers.GeneralResponse{
 			Message: "failed to generate token",
@@ -88,21 +144,28 @@ func (h *AccountWebHandler) PostRegisterMember(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// Set token in http only cookie
-	http.SetCookie(w, &http.Cookie{
-		Path:     "/",
-		Name:     config.AUTH_COOKIE_NAME,
-		Value:    token,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	refreshToken, err := h.tokenService.GenerateRefreshToken(r.Context(), acc.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Set tokens in http only cookies
+	helpers.SetAuthCookies(w, token, refreshToken)
 
 	http.Redirect(w, r, "/profile", http.StatusSeeOther)
 }
 
 func (h *AccountWebHandler) GetLogin(w http.ResponseWriter, r *http.Request) {
+	h.renderLogin(w, r, "")
+}
+
+func (h *AccountWebHandler) renderLogin(w http.ResponseWriter, r *http.Request, errorMessage string) {
 	data := map[string]interface{}{
-		"Title": "Login",
+		"Title":     "Login",
+		"Providers": h.oidcService.Providers(),
+		"Next":      r.FormValue("next"),
+		"Error":     errorMessage,
 	}
 	err := h.renderer.RenderWithLayout(
 		w, "layout/main.html", "login.html", data,
@@ -121,34 +184,80 @@ func (h *AccountWebHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the form data
-	email := r.Form.Get("email")
+	// Get the form data, the identifier is an email or username
+	identifier := r.Form.Get("identifier")
+	if identifier == "" {
+		identifier = r.Form.Get("email")
+	}
 	password := r.Form.Get("password")
 
 	// Authenticate
-	acc, err := h.accountService.Authenticate(context.Background(), email, password)
+	acc, err := h.accountService.Authenticate(r.Context(), identifier, password, helpers.GetClientIP(r))
+	if helpers.SetRetryAfter(w, err) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		h.renderLogin(w, r, "Too many failed attempts. Try again later.")
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidCredentials) {
+		w.WriteHeader(http.StatusUnauthorized)
+		h.renderLogin(w, r, "Invalid email, username or password.")
+		return
+	}
+	if errors.Is(err, domain.ErrAccountDisabled) {
+		w.WriteHeader(http.StatusForbidden)
+		h.renderLogin(w, r, "This account is disabled.")
+		return
+	}
+	if errors.Is(err, domain.ErrEmailNotVerified) {
+		h.renderVerifyEmail(w, "Your email address is not verified yet. Check your inbox or request a new link.", true)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if h.beginSession(w, r, acc) {
+		http.Redirect(w, r, localRedirect(r.Form.Get("next")), http.StatusSeeOther)
+	}
+}
+
+// beginSession sets the session cookies of an authenticated account, or renders the second
+// factor step when MFA is enabled. It returns false when a response was already written.
+func (h *AccountWebHandler) beginSession(w http.ResponseWriter, r *http.Request, acc *domain.Account) bool {
+	// Accounts with MFA continue to the second factor step
+	mfaEnabled, err := h.mfaService.IsEnabled(r.Context(), acc.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if mfaEnabled {
+		mfaToken, err := h.tokenService.GenerateMFAPendingToken(acc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return false
+		}
+		h.renderMFAVerify(w, mfaToken, r.FormValue("next"), "")
+		return false
+	}
+
 	// Generate JWT
-	token, err := h.tokenService.GenerateJWT(acc.Id, acc.Username, acc.Roles)
+	token, err := h.tokenService.GenerateJWT(acc)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return false
 	}
 
-	// Set token in http only cookie
-	http.SetCookie(w, &http.Cookie{
-		Path:     "/",
-		Name:     config.AUTH_COOKIE_NAME,
-		Value:    token,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	refreshToken, err := h.tokenService.GenerateRefreshToken(r.Context(), acc.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
 
-	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+	// Set tokens in http only cookies
+	helpers.SetAuthCookies(w, token, refreshToken)
+
+	return true
 }
 
 func (h *AccountWebHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
@@ -172,14 +281,57 @@ func (h *AccountWebHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AccountWebHandler) PostLogout(w http.ResponseWriter, r *http.Request) {
-	// Set token in http only cookie
-	http.SetCookie(w, &http.Cookie{
-		Path:     "/",
-		Name:     config.AUTH_COOKIE_NAME,
-		Value:    "",
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	// Revoke tokens of the current session
+	err := h.tokenService.RevokeSession(
+		r.Context(),
+		helpers.GetCookieValue(r, config.AUTH_COOKIE_NAME),
+		helpers.GetCookieValue(r, config.REFRESH_COOKIE_NAME),
+	)
+
+	// Clear token cookies, the browser is logged out even when revoking failed
+	helpers.ClearAuthCookies(w)
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+func (h *AccountWebHandler) PostLogoutAll(w http.ResponseWriter, r *http.Request) {
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Revoke all sessions of the account
+	err = h.tokenService.RevokeAllSessions(r.Context(), acc.Id)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	// Clear token cookies
+	helpers.ClearAuthCookies(w)
 
 	http.Redirect(w, r, "/login", http.StatusSeeOther)
 }
+
+// writeServiceError answers a failed service call, operations refused to impersonation sessions with a 403
+func writeServiceError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, domain.ErrImpersonationForbidden) {
+		status = http.StatusForbidden
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// localRedirect returns next when it is a path on this site, so login cannot be used as an open redirect
+func localRedirect(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/profile"
+	}
+	return next
+}
@@ -0,0 +1,87 @@
+package web
+
+import (
+	"errors"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"net/http"
+)
+
+func (h *AccountWebHandler) GetVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	acc, err := h.verificationService.VerifyEmail(r.Context(), token)
+	if err != nil {
+		h.renderVerifyEmail(w, "This verification link is invalid or has expired.", true)
+		return
+	}
+
+	// Refresh the access token of a logged in account so the new status applies right away
+	current, err := helpers.GetAccountFromContext(r.Context())
+	if err == nil && current.Id == acc.Id {
+		acc.MFAVerified = current.MFAVerified
+		token, err := h.tokenService.GenerateJWT(acc)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		helpers.SetAccessCookie(w, token)
+	}
+
+	h.renderVerifyEmail(w, "Your email address has been verified.", false)
+}
+
+// GetConfirmEmailChange applies the email change of the link mailed to the new address
+func (h *AccountWebHandler) GetConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	_, err := h.accountService.ConfirmEmailChange(r.Context(), token)
+	if errors.Is(err, domain.ErrEmailTaken) {
+		h.renderVerifyEmail(w, "This email address has been registered by another account in the meantime.", false)
+		return
+	}
+	if err != nil {
+		h.renderVerifyEmail(w, "This email change link is invalid or has expired.", false)
+		return
+	}
+
+	// Access tokens carrying the old address are revoked, the next request refreshes the session
+	h.renderVerifyEmail(w, "Your email address has been changed.", false)
+}
+
+func (h *AccountWebHandler) PostResendVerification(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	email := r.Form.Get("email")
+
+	acc, err := h.accountService.GetAccountByEmail(r.Context(), email)
+	if err == nil && !acc.IsEmailVerified() {
+		err = h.verificationService.SendVerificationEmail(r.Context(), acc)
+		if err != nil {
+			h.logger.Error("failed to resend verification email", "error", err)
+		}
+	}
+
+	// Same message whether or not the account exists
+	h.renderVerifyEmail(w, "If the account exists and is unverified, a new verification link has been sent.", false)
+}
+
+func (h *AccountWebHandler) renderVerifyEmail(w http.ResponseWriter, message string, showResend bool) {
+	data := map[string]interface{}{
+		"Title":      "Verify Email",
+		"Message":    message,
+		"ShowResend": showResend,
+	}
+	err := h.renderer.RenderWithLayout(
+		w, "layout/main.html", "verify_email.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ImpersonationWebHandler struct {
+	logger *slog.Logger
+
+	impersonationService domain.ImpersonationService
+}
+
+func NewImpersonationWebHandler(
+	container *infra.Container,
+	impersonationService domain.ImpersonationService,
+) *ImpersonationWebHandler {
+	logger := container.Logger.With("path", "ImpersonationWebHandler")
+	return &ImpersonationWebHandler{
+		logger:               logger,
+		impersonationService: impersonationService,
+	}
+}
+
+// PostImpersonate swaps the session cookie for an impersonation token, the token of the admin is
+// kept aside so stopping returns to it
+func (h *ImpersonationWebHandler) PostImpersonate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid account id", http.StatusBadRequest)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	token, err := h.impersonationService.Impersonate(r.Context(), acc, id)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, domain.ErrImpersonateSelf) ||
+		errors.Is(err, domain.ErrImpersonationNested) ||
+		errors.Is(err, domain.ErrImpersonationEscalation) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	helpers.SetImpersonatorCookie(w, helpers.GetCookieValue(r, config.AUTH_COOKIE_NAME))
+	helpers.SetAccessCookie(w, token)
+
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
+
+// PostStopImpersonation revokes the impersonation token and restores the session of the admin
+func (h *ImpersonationWebHandler) PostStopImpersonation(w http.ResponseWriter, r *http.Request) {
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = h.impersonationService.StopImpersonation(r.Context(), acc, helpers.GetCookieValue(r, config.AUTH_COOKIE_NAME))
+	if errors.Is(err, domain.ErrNotImpersonating) {
+		http.Redirect(w, r, "/profile", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	helpers.ClearImpersonatorCookie(w)
+
+	// An expired admin token leaves the browser logged out, the admin logs in again
+	adminToken := helpers.GetCookieValue(r, config.IMPERSONATOR_COOKIE_NAME)
+	if adminToken == "" {
+		helpers.ClearAuthCookies(w)
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	helpers.SetAccessCookie(w, adminToken)
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
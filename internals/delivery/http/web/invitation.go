@@ -0,0 +1,135 @@
+package web
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"gostarter/pkg/rendering"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+type InvitationWebHandler struct {
+	logger *slog.Logger
+
+	invitationService domain.InvitationService
+	renderer          *rendering.HtmlRenderer
+}
+
+func NewInvitationWebHandler(
+	container *infra.Container,
+	invitationService domain.InvitationService,
+) *InvitationWebHandler {
+	renderer := rendering.NewHtmlRenderer(config.TEMPLATE_DIR)
+	logger := container.Logger.With("path", "InvitationWebHandler")
+	return &InvitationWebHandler{
+		logger:            logger,
+		invitationService: invitationService,
+		renderer:          renderer,
+	}
+}
+
+// invitationErrorMessage explains why an invite link cannot be used
+func invitationErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvitationExpired):
+		return "This invitation has expired. Ask for a new one."
+	case errors.Is(err, domain.ErrInvitationUsed):
+		return "This invitation has already been accepted."
+	case errors.Is(err, domain.ErrInvitationEmailMismatch):
+		return "This invitation was sent to another email address. Login with the invited account to accept it."
+	case errors.Is(err, domain.ErrInvitationAccountExists):
+		return "An account with the invited email exists. Login to accept the invitation."
+	case errors.Is(err, domain.ErrAlreadyOrganizationMember):
+		return "You are already a member of this organization."
+	default:
+		return "This invitation link is invalid."
+	}
+}
+
+func (h *InvitationWebHandler) renderInvitation(w http.ResponseWriter, r *http.Request, token string, data map[string]interface{}) {
+	data["Title"] = "Invitation"
+	data["Token"] = token
+	data["LoginURL"] = "/login?next=" + url.QueryEscape("/invitations/accept?token="+url.QueryEscape(token))
+
+	// The link is looked up again so the page shows why it cannot be used any more
+	if data["Success"] == nil {
+		invitation, organization, err := h.invitationService.GetInvitation(r.Context(), token)
+		if err != nil {
+			data["Error"] = invitationErrorMessage(err)
+		} else {
+			data["Invitation"] = invitation
+			data["Organization"] = organization
+		}
+	}
+
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err == nil {
+		data["Account"] = acc
+	}
+
+	err = h.renderer.RenderWithLayout(
+		w, "layout/main.html", "invitation.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *InvitationWebHandler) GetAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	h.renderInvitation(w, r, r.URL.Query().Get("token"), map[string]interface{}{})
+}
+
+func (h *InvitationWebHandler) PostAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token := r.Form.Get("token")
+	data := map[string]interface{}{}
+
+	// A logged in account joins, anyone else registers the invited email
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err == nil {
+		_, err = h.invitationService.Accept(r.Context(), token, acc)
+		if err != nil {
+			data["Error"] = invitationErrorMessage(err)
+			h.renderInvitation(w, r, token, data)
+			return
+		}
+
+		http.Redirect(w, r, "/profile", http.StatusSeeOther)
+		return
+	}
+
+	password := r.Form.Get("password")
+	if password != r.Form.Get("confirm_password") {
+		data["Error"] = "Passwords do not match."
+		h.renderInvitation(w, r, token, data)
+		return
+	}
+
+	acc = &domain.Account{
+		Password: password,
+		Roles:    []string{domain.ROLE_USER},
+	}
+
+	var policyErr *domain.PasswordPolicyError
+	_, err = h.invitationService.AcceptWithRegistration(r.Context(), token, acc)
+	if errors.As(err, &policyErr) {
+		data["Violations"] = policyErr.Violations
+	} else if err != nil {
+		data["Error"] = invitationErrorMessage(err)
+	} else {
+		data["Success"] = true
+	}
+
+	h.renderInvitation(w, r, token, data)
+}
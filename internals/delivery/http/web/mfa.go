@@ -0,0 +1,288 @@
+package web
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"gostarter/pkg/rendering"
+	"html/template"
+	"log/slog"
+	"net/http"
+)
+
+func (h *AccountWebHandler) renderMFAVerify(w http.ResponseWriter, mfaToken, next, errorMessage string) {
+	data := map[string]interface{}{
+		"Title":    "Two-Factor Authentication",
+		"MFAToken": mfaToken,
+		"Next":     next,
+		"Error":    errorMessage,
+	}
+	err := h.renderer.RenderWithLayout(
+		w, "layout/main.html", "mfa_verify.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostLoginMFA completes a login that is waiting for the second factor
+func (h *AccountWebHandler) PostLoginMFA(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	mfaToken := r.Form.Get("mfa_token")
+	code := r.Form.Get("code")
+
+	accountId, err := h.tokenService.ValidateMFAPendingToken(r.Context(), mfaToken)
+	if err != nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	err = h.mfaService.VerifyLogin(r.Context(), accountId, code, helpers.GetClientIP(r))
+	if helpers.SetRetryAfter(w, err) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		h.renderMFAVerify(w, mfaToken, r.Form.Get("next"), "Too many failed attempts. Try again later.")
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidMFACode) {
+		h.renderMFAVerify(w, mfaToken, r.Form.Get("next"), "The code is invalid. Try again or use a recovery code.")
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The pending token is single use
+	err = h.tokenService.RevokeJWT(r.Context(), mfaToken)
+	if err != nil {
+		h.logger.Error("failed to revoke mfa token", "error", err)
+	}
+
+	acc, err := h.accountService.GetAccountByID(r.Context(), accountId)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	acc.MFAVerified = true
+
+	// Generate JWT
+	token, err := h.tokenService.GenerateJWT(acc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	refreshToken, err := h.tokenService.GenerateRefreshToken(r.Context(), acc.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Set tokens in http only cookies
+	helpers.SetAuthCookies(w, token, refreshToken)
+
+	http.Redirect(w, r, localRedirect(r.Form.Get("next")), http.StatusSeeOther)
+}
+
+type MFAWebHandler struct {
+	logger *slog.Logger
+
+	tokenService domain.TokenService
+	mfaService   domain.MFAService
+	renderer     *rendering.HtmlRenderer
+}
+
+func NewMFAWebHandler(
+	container *infra.Container,
+	tokenService domain.TokenService,
+	mfaService domain.MFAService,
+) *MFAWebHandler {
+	renderer := rendering.NewHtmlRenderer(config.TEMPLATE_DIR)
+	logger := container.Logger.With("path", "MFAWebHandler")
+	return &MFAWebHandler{
+		logger:       logger,
+		tokenService: tokenService,
+		mfaService:   mfaService,
+		renderer:     renderer,
+	}
+}
+
+func (h *MFAWebHandler) renderSetup(w http.ResponseWriter, data map[string]interface{}) {
+	data["Title"] = "Two-Factor Authentication"
+	err := h.renderer.RenderWithLayout(
+		w, "layout/main.html", "mfa_setup.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetMFASetup shows the MFA status, starting a new enrollment when MFA is not enabled yet
+func (h *MFAWebHandler) GetMFASetup(w http.ResponseWriter, r *http.Request) {
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	enabled, err := h.mfaService.IsEnabled(r.Context(), acc.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Enabled":  enabled,
+		"Required": h.mfaService.IsRequired(acc),
+	}
+
+	if !enabled {
+		enrollment, err := h.mfaService.Enroll(r.Context(), acc)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		data["Secret"] = enrollment.Secret
+		// The generated data uri is trusted, html/template would otherwise replace it
+		data["QRCode"] = template.URL(enrollment.QRCode)
+	}
+
+	h.renderSetup(w, data)
+}
+
+// PostMFASetup confirms the enrollment and shows the recovery codes once
+func (h *MFAWebHandler) PostMFASetup(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	codes, err := h.mfaService.Confirm(r.Context(), acc.Id, r.Form.Get("code"))
+	if errors.Is(err, domain.ErrInvalidMFACode) {
+		// The secret is not shown again, the user restarts the enrollment
+		h.renderSetup(w, map[string]interface{}{
+			"Error":   "The code is invalid. Start over and scan the new QR code.",
+			"Retry":   true,
+			"Enabled": false,
+		})
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	// Replace the current session with one that passed the second factor
+	err = h.tokenService.RevokeSession(
+		r.Context(),
+		helpers.GetCookieValue(r, config.AUTH_COOKIE_NAME),
+		helpers.GetCookieValue(r, config.REFRESH_COOKIE_NAME),
+	)
+	if err != nil {
+		h.logger.Error("failed to revoke session", "error", err)
+	}
+
+	acc.MFAVerified = true
+	token, err := h.tokenService.GenerateJWT(acc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	refreshToken, err := h.tokenService.GenerateRefreshToken(r.Context(), acc.Id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	helpers.SetAuthCookies(w, token, refreshToken)
+
+	h.renderSetup(w, map[string]interface{}{
+		"Enabled":       true,
+		"RecoveryCodes": codes,
+	})
+}
+
+func (h *MFAWebHandler) PostMFADisable(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	err = h.mfaService.Disable(r.Context(), acc, r.Form.Get("code"))
+	if errors.Is(err, domain.ErrInvalidMFACode) || errors.Is(err, domain.ErrMFARequired) {
+		h.renderSetup(w, map[string]interface{}{
+			"Enabled":  true,
+			"Required": h.mfaService.IsRequired(acc),
+			"Error":    err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	http.Redirect(w, r, "/profile", http.StatusSeeOther)
+}
+
+func (h *MFAWebHandler) PostMFARecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(r.Context(), acc.Id, r.Form.Get("code"))
+	if errors.Is(err, domain.ErrInvalidMFACode) {
+		h.renderSetup(w, map[string]interface{}{
+			"Enabled":  true,
+			"Required": h.mfaService.IsRequired(acc),
+			"Error":    err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+
+	h.renderSetup(w, map[string]interface{}{
+		"Enabled":       true,
+		"Required":      h.mfaService.IsRequired(acc),
+		"RecoveryCodes": codes,
+	})
+}
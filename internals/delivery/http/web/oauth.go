@@ -0,0 +1,213 @@
+package web
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"gostarter/pkg/rendering"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// scopeDescriptions are shown on the consent page
+var scopeDescriptions = map[string]string{
+	domain.OAUTH_SCOPE_OPENID:         "Sign you in with your gostarter account",
+	domain.OAUTH_SCOPE_EMAIL:          "Read your email address",
+	domain.OAUTH_SCOPE_PROFILE:        "Read your username",
+	domain.OAUTH_SCOPE_OFFLINE_ACCESS: "Stay signed in when you are not using the app",
+}
+
+type OAuthWebHandler struct {
+	logger *slog.Logger
+
+	oauthService domain.OAuthService
+	renderer     *rendering.HtmlRenderer
+}
+
+func NewOAuthWebHandler(
+	container *infra.Container,
+	oauthService domain.OAuthService,
+) *OAuthWebHandler {
+	renderer := rendering.NewHtmlRenderer(config.TEMPLATE_DIR)
+	logger := container.Logger.With("path", "OAuthWebHandler")
+	return &OAuthWebHandler{
+		logger:       logger,
+		oauthService: oauthService,
+		renderer:     renderer,
+	}
+}
+
+func parseAuthorizationRequest(values url.Values) *domain.OAuthAuthorizationRequest {
+	return &domain.OAuthAuthorizationRequest{
+		ResponseType:        values.Get("response_type"),
+		ClientId:            values.Get("client_id"),
+		RedirectURI:         values.Get("redirect_uri"),
+		Scope:               values.Get("scope"),
+		State:               values.Get("state"),
+		Nonce:               values.Get("nonce"),
+		CodeChallenge:       values.Get("code_challenge"),
+		CodeChallengeMethod: values.Get("code_challenge_method"),
+	}
+}
+
+// redirectToClient sends the browser back to the validated redirect uri of the client
+func redirectToClient(w http.ResponseWriter, r *http.Request, req *domain.OAuthAuthorizationRequest, params url.Values) {
+	redirectURI, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	query := redirectURI.Query()
+	for key := range params {
+		query.Set(key, params.Get(key))
+	}
+	if req.State != "" {
+		query.Set("state", req.State)
+	}
+	redirectURI.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func redirectOAuthError(w http.ResponseWriter, r *http.Request, req *domain.OAuthAuthorizationRequest, err error) {
+	oauthErr := domain.NewOAuthError("server_error", "")
+	errors.As(err, &oauthErr)
+
+	params := url.Values{}
+	params.Set("error", oauthErr.Code)
+	if oauthErr.Description != "" {
+		params.Set("error_description", oauthErr.Description)
+	}
+
+	redirectToClient(w, r, req, params)
+}
+
+func (h *OAuthWebHandler) renderError(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+
+	data := map[string]interface{}{
+		"Title": "Authorization Error",
+		"Error": err.Error(),
+	}
+	err = h.renderer.RenderWithLayout(
+		w, "layout/main.html", "oauth_error.html", data,
+	)
+	if err != nil {
+		h.logger.Error("failed to render oauth error", "error", err)
+	}
+}
+
+// GetAuthorize is the authorization endpoint. It asks the signed in account for consent
+// unless the scopes were already granted to the client.
+func (h *OAuthWebHandler) GetAuthorize(w http.ResponseWriter, r *http.Request) {
+	req := parseAuthorizationRequest(r.URL.Query())
+
+	client, err := h.oauthService.ValidateAuthorizationRequest(r.Context(), req)
+	if err != nil && client == nil {
+		h.renderError(w, err)
+		return
+	}
+	if err != nil {
+		redirectOAuthError(w, r, req, err)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil && r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		// The session cookies are strict and not sent when a client starts the request,
+		// reloading from a page of this site tells a signed in account apart from anonymous.
+		err = h.renderer.Render(w, "oidc_callback.html", map[string]interface{}{
+			"Redirect": r.URL.RequestURI(),
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if err != nil {
+		http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+		return
+	}
+
+	consented, err := h.oauthService.HasConsent(r.Context(), acc.Id, client.ClientId, req.Scope)
+	if err != nil {
+		redirectOAuthError(w, r, req, err)
+		return
+	}
+	if consented {
+		h.authorize(w, r, acc, req)
+		return
+	}
+
+	var scopes []string
+	for _, scope := range strings.Fields(req.Scope) {
+		scopes = append(scopes, scopeDescriptions[scope])
+	}
+
+	data := map[string]interface{}{
+		"Title":   "Authorize " + client.Name,
+		"Client":  client,
+		"Account": acc,
+		"Scopes":  scopes,
+		"Request": req,
+	}
+	err = h.renderer.RenderWithLayout(
+		w, "layout/main.html", "consent.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// PostAuthorize records the decision made on the consent page
+func (h *OAuthWebHandler) PostAuthorize(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := parseAuthorizationRequest(r.PostForm)
+
+	client, err := h.oauthService.ValidateAuthorizationRequest(r.Context(), req)
+	if err != nil && client == nil {
+		h.renderError(w, err)
+		return
+	}
+	if err != nil {
+		redirectOAuthError(w, r, req, err)
+		return
+	}
+
+	acc, err := helpers.GetAccountFromContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if r.PostForm.Get("decision") != "approve" {
+		redirectOAuthError(w, r, req, domain.NewOAuthError(domain.OAUTH_ERR_ACCESS_DENIED, "the user denied the request"))
+		return
+	}
+
+	h.authorize(w, r, acc, req)
+}
+
+func (h *OAuthWebHandler) authorize(w http.ResponseWriter, r *http.Request, acc *domain.Account, req *domain.OAuthAuthorizationRequest) {
+	code, err := h.oauthService.Authorize(r.Context(), acc, req)
+	if err != nil {
+		redirectOAuthError(w, r, req, err)
+		return
+	}
+
+	params := url.Values{}
+	params.Set("code", code)
+	redirectToClient(w, r, req, params)
+}
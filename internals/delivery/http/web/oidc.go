@@ -0,0 +1,86 @@
+package web
+
+import (
+	"errors"
+	"gostarter/infra/config"
+	"gostarter/internals/delivery/http/helpers"
+	"gostarter/internals/domain"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// oidcFlowCookieMaxAge bounds how long the user has to complete the login at the provider
+const oidcFlowCookieMaxAge = 10 * 60
+
+// GetOIDCLogin redirects to the authorization endpoint of the provider
+func (h *AccountWebHandler) GetOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	authURL, flowState, err := h.oidcService.BeginLogin(r.Context(), provider)
+	if errors.Is(err, domain.ErrOIDCProviderNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to begin oidc login", "provider", provider, "error", err)
+		h.renderLogin(w, r, "The login provider is not available right now. Try again later.")
+		return
+	}
+
+	helpers.SetOIDCFlowCookie(w, flowState, oidcFlowCookieMaxAge)
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// GetOIDCCallback completes the login when the provider redirects back with a code
+func (h *AccountWebHandler) GetOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+	query := r.URL.Query()
+
+	flowState := helpers.GetCookieValue(r, config.OIDC_COOKIE_NAME)
+	helpers.ClearOIDCFlowCookie(w)
+
+	if providerError := query.Get("error"); providerError != "" {
+		h.logger.Warn("oidc provider returned an error", "provider", provider, "error", providerError)
+		h.renderLogin(w, r, "Login with the provider was cancelled or failed.")
+		return
+	}
+
+	acc, err := h.oidcService.CompleteLogin(r.Context(), provider, query.Get("state"), query.Get("code"), flowState)
+	if errors.Is(err, domain.ErrOIDCEmailNotVerified) {
+		h.renderLogin(w, r, "The provider did not confirm your email address.")
+		return
+	}
+	if errors.Is(err, domain.ErrAccountDisabled) {
+		h.renderLogin(w, r, "This account is disabled.")
+		return
+	}
+	if errors.Is(err, domain.ErrOIDCAccountUnverified) {
+		h.renderLogin(w, r, "An account with this email exists. Log in with your password and verify your email first.")
+		return
+	}
+	if errors.Is(err, domain.ErrOIDCRegistrationClosed) {
+		h.renderLogin(w, r, "No account is registered for this login.")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to complete oidc login", "provider", provider, "error", err)
+		h.renderLogin(w, r, "Login with the provider failed. Try again.")
+		return
+	}
+
+	if !h.beginSession(w, r, acc) {
+		return
+	}
+
+	// The session cookies are strict, a redirect chain started by the provider would not send
+	// them, so the browser is sent on from a page of this site instead.
+	err = h.renderer.Render(w, "oidc_callback.html", map[string]interface{}{
+		"Redirect": "/profile",
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
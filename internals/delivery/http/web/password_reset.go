@@ -0,0 +1,123 @@
+package web
+
+import (
+	"errors"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/domain"
+	"gostarter/pkg/rendering"
+	"log/slog"
+	"net/http"
+)
+
+type PasswordResetWebHandler struct {
+	logger *slog.Logger
+
+	passwordResetService domain.PasswordResetService
+	renderer             *rendering.HtmlRenderer
+}
+
+func NewPasswordResetWebHandler(
+	container *infra.Container,
+	passwordResetService domain.PasswordResetService,
+) *PasswordResetWebHandler {
+	renderer := rendering.NewHtmlRenderer(config.TEMPLATE_DIR)
+	logger := container.Logger.With("path", "PasswordResetWebHandler")
+	return &PasswordResetWebHandler{
+		logger:               logger,
+		passwordResetService: passwordResetService,
+		renderer:             renderer,
+	}
+}
+
+func (h *PasswordResetWebHandler) GetForgotPassword(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"Title": "Forgot Password",
+	}
+	err := h.renderer.RenderWithLayout(
+		w, "layout/main.html", "forgot_password.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *PasswordResetWebHandler) PostForgotPassword(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	email := r.Form.Get("email")
+
+	err = h.passwordResetService.RequestPasswordReset(r.Context(), email)
+	if err != nil {
+		h.logger.Error("failed to request password reset", "error", err)
+	}
+
+	// Same message whether or not the account exists
+	data := map[string]interface{}{
+		"Title":   "Forgot Password",
+		"Message": "If the account exists, a password reset link has been sent.",
+	}
+	err = h.renderer.RenderWithLayout(
+		w, "layout/main.html", "forgot_password.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *PasswordResetWebHandler) GetResetPassword(w http.ResponseWriter, r *http.Request) {
+	data := map[string]interface{}{
+		"Title": "Reset Password",
+		"Token": r.URL.Query().Get("token"),
+	}
+	err := h.renderer.RenderWithLayout(
+		w, "layout/main.html", "reset_password.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *PasswordResetWebHandler) PostResetPassword(w http.ResponseWriter, r *http.Request) {
+	// Parse the form
+	err := r.ParseForm()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token := r.Form.Get("token")
+	password := r.Form.Get("password")
+
+	data := map[string]interface{}{
+		"Title": "Reset Password",
+		"Token": token,
+	}
+
+	var policyErr *domain.PasswordPolicyError
+	if password != r.Form.Get("confirm_password") {
+		data["Error"] = "Passwords do not match."
+	} else if err := h.passwordResetService.ResetPassword(r.Context(), token, password); errors.As(err, &policyErr) {
+		data["Violations"] = policyErr.Violations
+	} else if err != nil {
+		data["Error"] = "This reset link is invalid or has expired."
+	} else {
+		data["Success"] = true
+	}
+
+	err = h.renderer.RenderWithLayout(
+		w, "layout/main.html", "reset_password.html", data,
+	)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+)
+
+// AccountPurger periodically erases the accounts deleted longer ago than the grace period
+type AccountPurger struct {
+	logger   *slog.Logger
+	interval time.Duration
+
+	accountService domain.AccountService
+}
+
+func NewAccountPurger(container *infra.Container, accountService domain.AccountService) *AccountPurger {
+	interval := time.Minute * time.Duration(container.Cfg.Auth.AccountPurgeIntervalMinutes)
+	if interval <= 0 {
+		interval = defaultPruneInterval
+	}
+
+	logger := container.Logger.With("path", "AccountPurger")
+	return &AccountPurger{
+		logger:         logger,
+		interval:       interval,
+		accountService: accountService,
+	}
+}
+
+// Start blocks and purges on every tick until the context is cancelled
+func (p *AccountPurger) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := p.accountService.PurgeDeletedAccounts(ctx)
+			if err != nil {
+				p.logger.Error("failed to purge deleted accounts", "error", err)
+				continue
+			}
+			p.logger.Info("purged deleted accounts", "count", purged)
+		}
+	}
+}
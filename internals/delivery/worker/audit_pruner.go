@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+)
+
+// AuditPruner periodically removes audit events older than the configured retention
+type AuditPruner struct {
+	logger   *slog.Logger
+	interval time.Duration
+
+	auditService domain.AuditService
+}
+
+func NewAuditPruner(container *infra.Container, auditService domain.AuditService) *AuditPruner {
+	interval := time.Minute * time.Duration(container.Cfg.Audit.PruneIntervalMinutes)
+	if interval <= 0 {
+		interval = defaultPruneInterval
+	}
+
+	logger := container.Logger.With("path", "AuditPruner")
+	return &AuditPruner{
+		logger:       logger,
+		interval:     interval,
+		auditService: auditService,
+	}
+}
+
+// Start blocks and prunes on every tick until the context is cancelled
+func (p *AuditPruner) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := p.auditService.PruneEvents(ctx)
+			if err != nil {
+				p.logger.Error("failed to prune audit events", "error", err)
+				continue
+			}
+			p.logger.Info("pruned audit events", "count", pruned)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+)
+
+// KeyringReloader periodically reads the signing keyring, so keys rotated with
+// `gostarter keys rotate` are used without restarting the server
+type KeyringReloader struct {
+	logger   *slog.Logger
+	interval time.Duration
+
+	tokenService domain.TokenService
+}
+
+func NewKeyringReloader(container *infra.Container, tokenService domain.TokenService) *KeyringReloader {
+	interval := time.Second * time.Duration(container.Cfg.JWT.KeyringReloadSeconds)
+	if interval <= 0 {
+		interval = time.Second * config.DEFAULT_KEYRING_RELOAD_SECONDS
+	}
+
+	logger := container.Logger.With("path", "KeyringReloader")
+	return &KeyringReloader{
+		logger:       logger,
+		interval:     interval,
+		tokenService: tokenService,
+	}
+}
+
+// Start blocks and reloads on every tick until the context is cancelled
+func (k *KeyringReloader) Start(ctx context.Context) {
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := k.tokenService.ReloadKeys(ctx)
+			if err != nil {
+				k.logger.Error("failed to reload signing keys", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+)
+
+// LoginAttemptPruner periodically removes failed login counters that are no longer remembered
+type LoginAttemptPruner struct {
+	logger   *slog.Logger
+	interval time.Duration
+
+	lockoutService domain.LockoutService
+}
+
+func NewLoginAttemptPruner(container *infra.Container, lockoutService domain.LockoutService) *LoginAttemptPruner {
+	interval := time.Minute * time.Duration(container.Cfg.Auth.LockoutWindowMinutes)
+	if interval <= 0 {
+		interval = defaultPruneInterval
+	}
+
+	logger := container.Logger.With("path", "LoginAttemptPruner")
+	return &LoginAttemptPruner{
+		logger:         logger,
+		interval:       interval,
+		lockoutService: lockoutService,
+	}
+}
+
+// Start blocks and prunes on every tick until the context is cancelled
+func (p *LoginAttemptPruner) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := p.lockoutService.PruneLoginAttempts(ctx)
+			if err != nil {
+				p.logger.Error("failed to prune login attempts", "error", err)
+				continue
+			}
+			p.logger.Info("pruned login attempts", "count", pruned)
+		}
+	}
+}
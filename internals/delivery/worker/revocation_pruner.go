@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+)
+
+const defaultPruneInterval = time.Hour
+
+// RevocationPruner periodically removes expired entries from the token denylist
+type RevocationPruner struct {
+	logger   *slog.Logger
+	interval time.Duration
+
+	tokenService domain.TokenService
+}
+
+func NewRevocationPruner(container *infra.Container, tokenService domain.TokenService) *RevocationPruner {
+	interval := time.Minute * time.Duration(container.Cfg.JWT.RevocationPruneMinutes)
+	if interval <= 0 {
+		interval = defaultPruneInterval
+	}
+
+	logger := container.Logger.With("path", "RevocationPruner")
+	return &RevocationPruner{
+		logger:       logger,
+		interval:     interval,
+		tokenService: tokenService,
+	}
+}
+
+// Start blocks and prunes on every tick until the context is cancelled
+func (p *RevocationPruner) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pruned, err := p.tokenService.PruneRevokedTokens(ctx)
+			if err != nil {
+				p.logger.Error("failed to prune revoked tokens", "error", err)
+				continue
+			}
+			p.logger.Info("pruned revoked tokens", "count", pruned)
+		}
+	}
+}
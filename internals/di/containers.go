@@ -2,43 +2,217 @@ package di
 
 import (
 	"gostarter/infra"
+	"gostarter/infra/config"
 	"gostarter/internals/delivery/http/api"
 	"gostarter/internals/delivery/http/web"
 	"gostarter/internals/domain"
 	"gostarter/internals/service"
+	"gostarter/internals/storage/memory"
 	"gostarter/internals/storage/pgstorage"
 )
 
 type RepoContainer struct {
-	AccountRepo domain.AccountRepository
+	AccountRepo         domain.AccountRepository
+	RefreshTokenRepo    domain.RefreshTokenRepository
+	TokenRevocationRepo domain.TokenRevocationRepository
+	AccountTokenRepo    domain.AccountTokenRepository
+	MFARepo             domain.MFARepository
+	IdentityRepo        domain.IdentityRepository
+	OAuthClientRepo     domain.OAuthClientRepository
+	OAuthGrantRepo      domain.OAuthGrantRepository
+	APIKeyRepo          domain.APIKeyRepository
+	LockoutRepo         domain.LockoutRepository
+	PasswordHistoryRepo domain.PasswordHistoryRepository
+	PermissionRepo      domain.PermissionRepository
+	RoleRepo            domain.RoleRepository
+	OrganizationRepo    domain.OrganizationRepository
+	InvitationRepo      domain.InvitationRepository
+	AuditRepo           domain.AuditRepository
 }
 
 func NewRepoContainer(container *infra.Container) *RepoContainer {
 	return &RepoContainer{
-		AccountRepo: pgstorage.NewAccountRepository(container),
+		AccountRepo:         pgstorage.NewAccountRepository(container),
+		RefreshTokenRepo:    pgstorage.NewRefreshTokenRepository(container),
+		TokenRevocationRepo: newTokenRevocationRepository(container),
+		AccountTokenRepo:    pgstorage.NewAccountTokenRepository(container),
+		MFARepo:             pgstorage.NewMFARepository(container),
+		IdentityRepo:        pgstorage.NewIdentityRepository(container),
+		OAuthClientRepo:     pgstorage.NewOAuthClientRepository(container),
+		OAuthGrantRepo:      pgstorage.NewOAuthGrantRepository(container),
+		APIKeyRepo:          pgstorage.NewAPIKeyRepository(container),
+		LockoutRepo:         pgstorage.NewLockoutRepository(container),
+		PasswordHistoryRepo: pgstorage.NewPasswordHistoryRepository(container),
+		PermissionRepo:      pgstorage.NewPermissionRepository(container),
+		RoleRepo:            pgstorage.NewRoleRepository(container),
+		OrganizationRepo:    pgstorage.NewOrganizationRepository(container),
+		InvitationRepo:      pgstorage.NewInvitationRepository(container),
+		AuditRepo:           pgstorage.NewAuditRepository(container),
 	}
 }
 
+func newTokenRevocationRepository(container *infra.Container) domain.TokenRevocationRepository {
+	if container.Cfg.JWT.RevocationStore == config.STORE_MEMORY {
+		return memory.NewTokenRevocationRepository(container)
+	}
+	return pgstorage.NewTokenRevocationRepository(container)
+}
+
 type ServiceContainer struct {
-	TokenService   domain.TokenService
-	AccountService domain.AccountService
+	TokenService         domain.TokenService
+	VerificationService  domain.VerificationService
+	AccountService       domain.AccountService
+	PasswordResetService domain.PasswordResetService
+	MFAService           domain.MFAService
+	OIDCService          domain.OIDCService
+	OAuthService         domain.OAuthService
+	APIKeyService        domain.APIKeyService
+	LockoutService       domain.LockoutService
+	PermissionService    domain.PermissionService
+	RoleService          domain.RoleService
+	OrganizationService  domain.OrganizationService
+	InvitationService    domain.InvitationService
+	ImpersonationService domain.ImpersonationService
+	AuditService         domain.AuditService
+	AccountExportService domain.AccountExportService
 }
 
 func NewServiceContainer(container *infra.Container, repoContainer *RepoContainer) *ServiceContainer {
+	auditService := service.NewAuditService(container, repoContainer.AuditRepo)
+	tokenService := service.NewTokenService(container, repoContainer.RefreshTokenRepo, repoContainer.TokenRevocationRepo)
+	verificationService := service.NewVerificationService(container, repoContainer.AccountRepo, repoContainer.AccountTokenRepo)
+	lockoutService := service.NewLockoutService(container, repoContainer.AccountRepo, repoContainer.LockoutRepo, auditService)
+	passwordPolicyService := service.NewPasswordPolicyService(container, repoContainer.PasswordHistoryRepo)
+	accountService := service.NewAccountService(
+		container,
+		repoContainer.AccountRepo,
+		tokenService,
+		verificationService,
+		lockoutService,
+		passwordPolicyService,
+		auditService,
+	)
+	permissionService := service.NewPermissionService(container, repoContainer.PermissionRepo)
+
 	return &ServiceContainer{
-		TokenService:   service.NewTokenService(container.Cfg.JWT),
-		AccountService: service.NewAccountService(container, repoContainer.AccountRepo),
+		TokenService:         tokenService,
+		VerificationService:  verificationService,
+		AccountService:       accountService,
+		PasswordResetService: service.NewPasswordResetService(container, accountService, tokenService, repoContainer.AccountTokenRepo),
+		MFAService:           service.NewMFAService(container, repoContainer.MFARepo, lockoutService, auditService),
+		OIDCService:          service.NewOIDCService(container, accountService, repoContainer.IdentityRepo, auditService),
+		OAuthService: service.NewOAuthService(
+			container,
+			accountService,
+			tokenService,
+			repoContainer.OAuthClientRepo,
+			repoContainer.OAuthGrantRepo,
+		),
+		APIKeyService:     service.NewAPIKeyService(container, accountService, repoContainer.APIKeyRepo, auditService),
+		LockoutService:    lockoutService,
+		PermissionService: permissionService,
+		RoleService: service.NewRoleService(
+			container,
+			accountService,
+			tokenService,
+			permissionService,
+			repoContainer.RoleRepo,
+			auditService,
+		),
+		OrganizationService: service.NewOrganizationService(
+			container,
+			accountService,
+			repoContainer.OrganizationRepo,
+			auditService,
+		),
+		InvitationService: service.NewInvitationService(
+			container,
+			accountService,
+			repoContainer.OrganizationRepo,
+			repoContainer.InvitationRepo,
+			auditService,
+		),
+		ImpersonationService: service.NewImpersonationService(
+			container,
+			accountService,
+			tokenService,
+			permissionService,
+			auditService,
+		),
+		AuditService: auditService,
+		AccountExportService: service.NewAccountExportService(
+			container,
+			accountService,
+			repoContainer.RefreshTokenRepo,
+			auditService,
+		),
 	}
 }
 
 type HandlerContainer struct {
-	AccountHandler    domain.AccountHandler
-	AccountWebHandler *web.AccountWebHandler
+	AccountHandler          domain.AccountHandler
+	AccountWebHandler       *web.AccountWebHandler
+	PasswordResetHandler    domain.PasswordResetHandler
+	PasswordResetWebHandler *web.PasswordResetWebHandler
+	MFAHandler              domain.MFAHandler
+	MFAWebHandler           *web.MFAWebHandler
+	OAuthHandler            domain.OAuthHandler
+	OAuthWebHandler         *web.OAuthWebHandler
+	APIKeyHandler           domain.APIKeyHandler
+	LockoutHandler          domain.LockoutHandler
+	RoleHandler             domain.RoleHandler
+	InvitationHandler       domain.InvitationHandler
+	InvitationWebHandler    *web.InvitationWebHandler
+	ImpersonationHandler    domain.ImpersonationHandler
+	ImpersonationWebHandler *web.ImpersonationWebHandler
+	AuditHandler            domain.AuditHandler
+	AdminAccountHandler     domain.AdminAccountHandler
 }
 
 func NewHandlerContainer(container *infra.Container, serviceContainer *ServiceContainer) *HandlerContainer {
 	return &HandlerContainer{
-		AccountHandler:    api.NewAccountHandler(container, serviceContainer.AccountService, serviceContainer.TokenService),
-		AccountWebHandler: web.NewAccountWebHandler(serviceContainer.TokenService, serviceContainer.AccountService),
+		AccountHandler: api.NewAccountHandler(
+			container,
+			serviceContainer.AccountService,
+			serviceContainer.TokenService,
+			serviceContainer.VerificationService,
+			serviceContainer.MFAService,
+			serviceContainer.AccountExportService,
+		),
+		AccountWebHandler: web.NewAccountWebHandler(
+			container,
+			serviceContainer.TokenService,
+			serviceContainer.AccountService,
+			serviceContainer.VerificationService,
+			serviceContainer.MFAService,
+			serviceContainer.OIDCService,
+		),
+		PasswordResetHandler:    api.NewPasswordResetHandler(container, serviceContainer.PasswordResetService),
+		PasswordResetWebHandler: web.NewPasswordResetWebHandler(container, serviceContainer.PasswordResetService),
+		MFAHandler: api.NewMFAHandler(
+			container,
+			serviceContainer.AccountService,
+			serviceContainer.TokenService,
+			serviceContainer.MFAService,
+		),
+		MFAWebHandler:        web.NewMFAWebHandler(container, serviceContainer.TokenService, serviceContainer.MFAService),
+		OAuthHandler:         api.NewOAuthHandler(container, serviceContainer.OAuthService),
+		OAuthWebHandler:      web.NewOAuthWebHandler(container, serviceContainer.OAuthService),
+		APIKeyHandler:        api.NewAPIKeyHandler(container, serviceContainer.APIKeyService),
+		LockoutHandler:       api.NewLockoutHandler(container, serviceContainer.LockoutService),
+		RoleHandler:          api.NewRoleHandler(container, serviceContainer.RoleService),
+		InvitationHandler:    api.NewInvitationHandler(container, serviceContainer.InvitationService),
+		InvitationWebHandler: web.NewInvitationWebHandler(container, serviceContainer.InvitationService),
+		ImpersonationHandler: api.NewImpersonationHandler(container, serviceContainer.ImpersonationService),
+		ImpersonationWebHandler: web.NewImpersonationWebHandler(
+			container,
+			serviceContainer.ImpersonationService,
+		),
+		AuditHandler: api.NewAuditHandler(container, serviceContainer.AuditService),
+		AdminAccountHandler: api.NewAdminAccountHandler(
+			container,
+			serviceContainer.AccountService,
+			serviceContainer.RoleService,
+		),
 	}
 }
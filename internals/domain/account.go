@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 const (
@@ -12,24 +16,167 @@ const (
 	ROLE_ADMIN = "admin"
 )
 
+const (
+	USERNAME_MIN_LENGTH = 3
+	USERNAME_MAX_LENGTH = 32
+)
+
 type Account struct {
 	Id int `json:"id"`
 
+	// Username is optional, accounts without one log in with their email
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
 
 	Roles []string `json:"roles"`
 
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	// MFAVerified is set on sessions that passed the second factor, it is carried in the token and not persisted
+	MFAVerified bool `json:"-"`
+
+	// Scopes limit the roles of a session authenticated with an API key, nil for other sessions
+	Scopes []string `json:"-"`
+
+	// Impersonator is the admin acting as the account in an impersonation session, nil for other sessions
+	Impersonator *Account `json:"-"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// DeletedAt marks an account awaiting its purge, deleted accounts are not found by lookups
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// DisabledAt marks an account an admin disabled, it cannot start sessions until it is enabled
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
+
+func (a *Account) IsEmailVerified() bool {
+	return a.EmailVerifiedAt != nil
+}
+
+func (a *Account) IsDisabled() bool {
+	return a.DisabledAt != nil
+}
+
+// ValidateUsername returns ErrInvalidUsername unless the username is made of letters, digits,
+// dots, dashes and underscores and starts with a letter or digit. Without an @ it cannot be
+// mistaken for an email at login.
+func ValidateUsername(username string) error {
+	length := utf8.RuneCountInString(username)
+	if length < USERNAME_MIN_LENGTH || length > USERNAME_MAX_LENGTH {
+		return ErrInvalidUsername
+	}
+
+	for i, r := range username {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			continue
+		}
+		if i > 0 && strings.ContainsRune("._-", r) {
+			continue
+		}
+		return ErrInvalidUsername
+	}
+
+	return nil
+}
+
+// IsImpersonated reports whether the session is an admin acting as the account
+func (a *Account) IsImpersonated() bool {
+	return a.Impersonator != nil
+}
+
+// HasRole reports whether the session acts with the role, API key sessions only with the roles in the key scopes
+func (a *Account) HasRole(role string) bool {
+	if a.Scopes != nil && !slices.Contains(a.Scopes, role) {
+		return false
+	}
+	return slices.Contains(a.Roles, role)
+}
+
+// EffectiveRoles returns the roles the session acts with
+func (a *Account) EffectiveRoles() []string {
+	var roles []string
+	for _, role := range a.Roles {
+		if a.HasRole(role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// AccountEdge is an account of a cursor page with the cursor pointing at it
+type AccountEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Account `json:"node"`
+}
+
+type AccountConnection struct {
+	Edges    []*AccountEdge      `json:"edges"`
+	PageInfo *ConnectionPageInfo `json:"page_info"`
+	// TotalCount is only counted when the pagination asks for it
+	TotalCount *int `json:"total_count,omitempty"`
+}
+
+// NewAccountConnection builds a page from the accounts read for it in listing order, with one
+// account more than the page size when there are more to read in the paging direction
+func NewAccountConnection(accounts []*Account, pagination *CursorPagination, cursor func(*Account) string) *AccountConnection {
+	more := len(accounts) > pagination.Limit()
+	if more && pagination.Backward() {
+		accounts = accounts[1:]
+	} else if more {
+		accounts = accounts[:len(accounts)-1]
+	}
+
+	connection := &AccountConnection{
+		Edges: make([]*AccountEdge, 0, len(accounts)),
+		PageInfo: &ConnectionPageInfo{
+			HasNextPage:     more && !pagination.Backward() || pagination.Before != "",
+			HasPreviousPage: more && pagination.Backward() || pagination.After != "",
+		},
+	}
+	for _, account := range accounts {
+		connection.Edges = append(connection.Edges, &AccountEdge{
+			Cursor: cursor(account),
+			Node:   account,
+		})
+	}
+
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+
+	return connection
 }
 
 type AccountHandler interface {
 	Register(w http.ResponseWriter, r *http.Request)
 	Login(w http.ResponseWriter, r *http.Request)
 	Logout(w http.ResponseWriter, r *http.Request)
+	LogoutAll(w http.ResponseWriter, r *http.Request)
+	Refresh(w http.ResponseWriter, r *http.Request)
 	Profile(w http.ResponseWriter, r *http.Request)
+	Export(w http.ResponseWriter, r *http.Request)
+
+	UpdateAccount(w http.ResponseWriter, r *http.Request)
+	ChangePassword(w http.ResponseWriter, r *http.Request)
+	ChangeEmail(w http.ResponseWriter, r *http.Request)
+	ConfirmEmailChange(w http.ResponseWriter, r *http.Request)
+	DeleteAccount(w http.ResponseWriter, r *http.Request)
+
+	VerifyEmail(w http.ResponseWriter, r *http.Request)
+	ResendVerification(w http.ResponseWriter, r *http.Request)
+}
+
+type AdminAccountHandler interface {
+	List(w http.ResponseWriter, r *http.Request)
+	Get(w http.ResponseWriter, r *http.Request)
+	Update(w http.ResponseWriter, r *http.Request)
+	Disable(w http.ResponseWriter, r *http.Request)
+	Enable(w http.ResponseWriter, r *http.Request)
+	SetRoles(w http.ResponseWriter, r *http.Request)
+	Delete(w http.ResponseWriter, r *http.Request)
+	Search(w http.ResponseWriter, r *http.Request)
 }
 
 var (
@@ -37,15 +184,59 @@ var (
 )
 
 type AccountService interface {
+	// Register creates an account with a password that passed the password policy
 	Register(ctx context.Context, account *Account) error
-	Authenticate(ctx context.Context, email, password string) (*Account, error)
+	// RegisterExternal creates an account for an identity provider login, its generated password skips the policy
+	RegisterExternal(ctx context.Context, account *Account) error
+	// Authenticate checks the password of a login by email or username from the ip, returning
+	// ErrInvalidCredentials for unknown accounts and wrong passwords alike and a LockoutError
+	// after repeated failures
+	Authenticate(ctx context.Context, identifier, password, ip string) (*Account, error)
 
 	GetAccountByID(ctx context.Context, id int) (*Account, error)
 	GetAccountByEmail(ctx context.Context, email string) (*Account, error)
+	GetAccountByUsername(ctx context.Context, username string) (*Account, error)
+	// UpdateAccount validates a changed username and email, taken emails and usernames return
+	// ErrEmailTaken and ErrUsernameTaken
 	UpdateAccount(ctx context.Context, account *Account) error
+	// DisableAccount blocks new logins of the account and ends its sessions, it returns
+	// ErrAccountDisabled if it is already disabled
+	DisableAccount(ctx context.Context, id int) error
+	// EnableAccount lifts the block, it returns ErrAccountNotDisabled if the account is not disabled
+	EnableAccount(ctx context.Context, id int) error
+	// DeleteAccount marks the account deleted and ends its sessions, it is purged once the grace
+	// period has passed and can be restored until then
 	DeleteAccount(ctx context.Context, id int) error
+	// RestoreAccount undoes the deletion of an account that was not purged yet
+	RestoreAccount(ctx context.Context, id int) error
+	// PurgeAccount erases the account and its data right away, deleted or not
+	PurgeAccount(ctx context.Context, id int) error
+	// PurgeDeletedAccounts erases the accounts deleted longer ago than the grace period
+	PurgeDeletedAccounts(ctx context.Context) (int64, error)
+	SetPassword(ctx context.Context, account *Account, password string) error
+	// VerifyPassword re-authenticates the account before a sensitive change, returning
+	// ErrInvalidCredentials for a wrong password. Failures count towards the lockout like logins.
+	VerifyPassword(ctx context.Context, accountId int, password string) error
+	// ChangePassword sets a new password after checking the current one and ends every session
+	ChangePassword(ctx context.Context, accountId int, currentPassword, newPassword string) error
+	// RequestEmailChange checks the password and mails a confirmation link to the new address,
+	// the email changes once it is confirmed
+	RequestEmailChange(ctx context.Context, accountId int, password, newEmail string) error
+	// ConfirmEmailChange applies a confirmed email change and revokes the access tokens carrying the old address
+	ConfirmEmailChange(ctx context.Context, token string) (*Account, error)
+	// ValidatePassword returns a PasswordPolicyError listing every rule the password fails for the account
+	ValidatePassword(ctx context.Context, account *Account, password string) error
 
-	ListAccounts(context.Context, *Pagination) ([]*Account, error)
+	// ListAccounts returns a page of the accounts matching the filter in the sort order,
+	// ErrInvalidAccountStatus and ErrInvalidAccountSort are returned for an unknown status or sort
+	ListAccounts(ctx context.Context, filter *AccountFilter, sort []AccountSort, pagination *Pagination) ([]*Account, error)
+	// ListAccountsByCursor returns a keyset page of the accounts, ErrInvalidCursorPagination and
+	// ErrInvalidCursor are returned for pagination the repository cannot serve
+	ListAccountsByCursor(ctx context.Context, filter *AccountFilter, sort []AccountSort, pagination *CursorPagination) (*AccountConnection, error)
+	// SearchAccounts returns at most limit accounts that are not deleted, best matches first.
+	// Words of the query match words of the username or email by prefix or, when misspelled,
+	// by similarity. ErrInvalidSearchQuery is returned for a query without words.
+	SearchAccounts(ctx context.Context, query string, limit int) ([]*AccountSearchResult, error)
 }
 
 var (
@@ -54,16 +245,43 @@ var (
 )
 
 type AccountRepository interface {
+	// CreateAccount returns ErrEmailTaken or ErrUsernameTaken when another account, deleted
+	// or not, has the email or username. Both are compared in their normalized form.
 	CreateAccount(ctx context.Context, account *Account) error
 	GetAccountByID(ctx context.Context, id int) (*Account, error)
+	// GetAccountByEmail and GetAccountByUsername match regardless of case and Unicode normalization
 	GetAccountByEmail(ctx context.Context, email string) (*Account, error)
+	GetAccountByUsername(ctx context.Context, username string) (*Account, error)
+	// UpdateAccount does not write Roles, memberships are managed through the RoleRepository
 	UpdateAccount(ctx context.Context, account *Account) error
+	// SetAccountDisabled disables the account at the time, or enables it for a nil time
+	SetAccountDisabled(ctx context.Context, id int, disabledAt *time.Time) error
+	// SoftDeleteAccount hides the account from lookups, it returns ErrAccountNotFound if it is already deleted
+	SoftDeleteAccount(ctx context.Context, id int, deletedAt time.Time) error
+	RestoreAccount(ctx context.Context, id int) error
+	// DeleteAccount removes the account along with its roles, sessions and other dependent rows
 	DeleteAccount(ctx context.Context, id int) error
+	// PurgeDeletedAccounts removes the accounts deleted before the time and returns their ids
+	PurgeDeletedAccounts(ctx context.Context, before time.Time) ([]int, error)
 
-	ListAccounts(context.Context, *Pagination) ([]*Account, error)
+	// ListAccounts and ListAccountsByCursor order the accounts by the sort and then by id,
+	// ListAccountsByCursor returns ErrInvalidCursor for a cursor it did not make for the same sort
+	ListAccounts(ctx context.Context, filter *AccountFilter, sort []AccountSort, pagination *Pagination) ([]*Account, error)
+	ListAccountsByCursor(ctx context.Context, filter *AccountFilter, sort []AccountSort, pagination *CursorPagination) (*AccountConnection, error)
+	// SearchAccounts ranks the accounts that are not deleted against the words of the query,
+	// the highlights are left to the service
+	SearchAccounts(ctx context.Context, query string, limit int) ([]*AccountSearchResult, error)
 }
 
 // Errors
 var (
-	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountNotFound    = errors.New("account not found")
+	ErrAccountNotDeleted  = errors.New("account is not deleted")
+	ErrAccountDisabled    = errors.New("account is disabled")
+	ErrAccountNotDisabled = errors.New("account is not disabled")
+	ErrOwnAccount         = errors.New("admins cannot disable or delete their own account")
+	ErrEmailTaken         = errors.New("email is already registered")
+	ErrUsernameTaken      = errors.New("username is already taken")
+	ErrInvalidEmail       = errors.New("invalid email address")
+	ErrInvalidUsername    = errors.New("username must be 3 to 32 letters, digits, dots, dashes or underscores starting with a letter or digit")
 )
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// AccountExport is the personal data kept about an account, secrets such as the password
+// and token hashes are left out
+type AccountExport struct {
+	ExportedAt  time.Time          `json:"exported_at"`
+	Account     *ExportedAccount   `json:"account"`
+	Roles       []string           `json:"roles"`
+	Sessions    []*ExportedSession `json:"sessions"`
+	AuditEvents []*AuditEvent      `json:"audit_events"`
+}
+
+type ExportedAccount struct {
+	Id              int        `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ExportedSession is a refresh token of the account, a login on a device
+type ExportedSession struct {
+	Id        int        `json:"id"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type AccountExportService interface {
+	// Export collects the data of the account, it is refused to impersonation sessions
+	Export(ctx context.Context, accountId int) (*AccountExport, error)
+	// WriteArchive writes the export as a ZIP archive with a JSON file per section
+	WriteArchive(export *AccountExport, w io.Writer) error
+}
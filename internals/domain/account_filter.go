@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Account statuses of listings, accounts that are not deleted are listed when none is given
+const (
+	ACCOUNT_STATUS_ACTIVE     = "active"
+	ACCOUNT_STATUS_DISABLED   = "disabled"
+	ACCOUNT_STATUS_UNVERIFIED = "unverified"
+	ACCOUNT_STATUS_DELETED    = "deleted"
+)
+
+var accountStatuses = []string{
+	ACCOUNT_STATUS_ACTIVE,
+	ACCOUNT_STATUS_DISABLED,
+	ACCOUNT_STATUS_UNVERIFIED,
+	ACCOUNT_STATUS_DELETED,
+}
+
+// Fields account listings can be sorted by. Emails and usernames sort by their normalized
+// form, byte by byte.
+const (
+	ACCOUNT_SORT_ID         = "id"
+	ACCOUNT_SORT_EMAIL      = "email"
+	ACCOUNT_SORT_USERNAME   = "username"
+	ACCOUNT_SORT_CREATED_AT = "created_at"
+	ACCOUNT_SORT_UPDATED_AT = "updated_at"
+)
+
+var accountSortFields = []string{
+	ACCOUNT_SORT_ID,
+	ACCOUNT_SORT_EMAIL,
+	ACCOUNT_SORT_USERNAME,
+	ACCOUNT_SORT_CREATED_AT,
+	ACCOUNT_SORT_UPDATED_AT,
+}
+
+// AccountFilter narrows account listings, zero values match everything
+type AccountFilter struct {
+	// Status is one of the ACCOUNT_STATUS values. Deleted accounts are only listed with ACCOUNT_STATUS_DELETED.
+	Status string
+	Role   string
+
+	// From times are inclusive and To times exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+
+	// Prefixes and the query are matched regardless of case and Unicode normalization,
+	// the query against the email and the username
+	EmailPrefix    string
+	UsernamePrefix string
+	Query          string
+}
+
+// Validate returns ErrInvalidAccountStatus for an unknown status
+func (f *AccountFilter) Validate() error {
+	if f.Status != "" && !slices.Contains(accountStatuses, f.Status) {
+		return ErrInvalidAccountStatus
+	}
+	return nil
+}
+
+// AccountSort orders a listing by a field, ties are broken by the next sort and lastly the id
+type AccountSort struct {
+	Field      string
+	Descending bool
+}
+
+// ParseAccountSort parses comma separated fields, a field prefixed by - sorts descending,
+// as in `-created_at,email`. Empty input sorts by id.
+func ParseAccountSort(value string) ([]AccountSort, error) {
+	var sort []AccountSort
+	if strings.TrimSpace(value) == "" {
+		return sort, nil
+	}
+
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		name, descending := strings.CutPrefix(field, "-")
+		sort = append(sort, AccountSort{Field: name, Descending: descending})
+	}
+
+	return sort, ValidateAccountSort(sort)
+}
+
+// ValidateAccountSort returns ErrInvalidAccountSort for unknown and repeated fields
+func ValidateAccountSort(sort []AccountSort) error {
+	seen := map[string]bool{}
+	for _, s := range sort {
+		if !slices.Contains(accountSortFields, s.Field) || seen[s.Field] {
+			return ErrInvalidAccountSort
+		}
+		seen[s.Field] = true
+	}
+	return nil
+}
+
+// AccountSortKey formats the sort like ParseAccountSort reads it, cursors carry it so they
+// are not used with another order
+func AccountSortKey(sort []AccountSort) string {
+	fields := make([]string, 0, len(sort))
+	for _, s := range sort {
+		if s.Descending {
+			fields = append(fields, "-"+s.Field)
+		} else {
+			fields = append(fields, s.Field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+var (
+	ErrInvalidAccountStatus = errors.New("status must be one of active, disabled, unverified or deleted")
+	ErrInvalidAccountSort   = errors.New("sort fields must be id, email, username, created_at or updated_at, each at most once")
+)
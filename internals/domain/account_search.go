@@ -0,0 +1,22 @@
+package domain
+
+import "errors"
+
+// ACCOUNT_SEARCH_SIMILARITY is the trigram word similarity, between 0 and 1, from which a
+// misspelled search word matches a word of the username or email
+const ACCOUNT_SEARCH_SIMILARITY = 0.4
+
+// AccountSearchResult is an account found by SearchAccounts
+type AccountSearchResult struct {
+	Account *Account
+	// Rank orders the results of a search, better matches rank higher. Ranks of different
+	// searches cannot be compared.
+	Rank float64
+	// UsernameHighlight and EmailHighlight are HTML escaped with the matches in <mark> tags
+	UsernameHighlight string
+	EmailHighlight    string
+}
+
+var (
+	ErrInvalidSearchQuery = errors.New("search query must contain a letter or digit")
+)
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Purposes of single use account tokens
+const (
+	TOKEN_PURPOSE_EMAIL_VERIFICATION = "email_verification"
+	TOKEN_PURPOSE_PASSWORD_RESET     = "password_reset"
+	TOKEN_PURPOSE_EMAIL_CHANGE       = "email_change"
+)
+
+// AccountToken is a single use token sent to the account owner, only its hash is stored
+type AccountToken struct {
+	Id        int
+	AccountId int
+	Purpose   string
+	TokenHash string
+	// Payload is the value the token confirms, the new address of an email change
+	Payload string
+
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type AccountTokenRepository interface {
+	CreateAccountToken(ctx context.Context, token *AccountToken) error
+	GetAccountTokenByHash(ctx context.Context, purpose, tokenHash string) (*AccountToken, error)
+	// MarkAccountTokenUsed returns ErrAccountTokenUsed if the token was already consumed
+	MarkAccountTokenUsed(ctx context.Context, id int) error
+	InvalidateAccountTokens(ctx context.Context, accountId int, purpose string) error
+}
+
+var (
+	ErrAccountTokenNotFound = errors.New("token not found")
+	ErrAccountTokenExpired  = errors.New("token expired")
+	ErrAccountTokenUsed     = errors.New("token already used")
+)
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// API_KEY_PREFIX marks personal access tokens, so they are recognised in headers and secret scanners
+const API_KEY_PREFIX = "gsk_"
+
+// APIKey is a long lived personal access token of an account, only its hash is stored.
+// Scopes are role names, a request made with the key acts with the roles in both the
+// account and the scopes.
+type APIKey struct {
+	Id        int      `json:"id"`
+	AccountId int      `json:"-"`
+	Name      string   `json:"name"`
+	Prefix    string   `json:"prefix"`
+	TokenHash string   `json:"-"`
+	Scopes    []string `json:"scopes"`
+
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+type APIKeyHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+}
+
+type APIKeyService interface {
+	// Create issues a key limited to scopes the account can use, the token is only returned here
+	Create(ctx context.Context, account *Account, name string, scopes []string, expiresAt *time.Time) (*APIKey, string, error)
+	List(ctx context.Context, accountId int) ([]*APIKey, error)
+	Revoke(ctx context.Context, accountId, id int) error
+
+	// Authenticate returns the account of the key with its scopes, and records the use
+	Authenticate(ctx context.Context, token string) (*Account, error)
+}
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+	GetAPIKeyByHash(ctx context.Context, tokenHash string) (*APIKey, error)
+	ListAPIKeys(ctx context.Context, accountId int) ([]*APIKey, error)
+	DeleteAPIKey(ctx context.Context, accountId, id int) error
+	TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error
+}
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrAPIKeyExpired      = errors.New("api key expired")
+	ErrAPIKeyInvalidScope = errors.New("api key scope is not a role of the account")
+	ErrAPIKeyNameRequired = errors.New("api key name is required")
+)
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Audit actions are dotted names, a filter on a prefix such as `account.login` also matches
+// the actions below it such as `account.login.failed`.
+const (
+	AUDIT_ACCOUNT_REGISTER     = "account.register"
+	AUDIT_ACCOUNT_LOGIN        = "account.login"
+	AUDIT_ACCOUNT_LOGIN_FAILED = "account.login.failed"
+	AUDIT_ACCOUNT_DELETE       = "account.delete"
+	AUDIT_ACCOUNT_RESTORE      = "account.restore"
+	AUDIT_ACCOUNT_PURGE        = "account.purge"
+	AUDIT_ACCOUNT_EXPORT       = "account.export"
+	AUDIT_ACCOUNT_UPDATE       = "account.update"
+	AUDIT_ACCOUNT_PASSWORD     = "account.password.change"
+	AUDIT_ACCOUNT_EMAIL_CHANGE = "account.email.change"
+	AUDIT_ACCOUNT_DISABLE      = "account.disable"
+	AUDIT_ACCOUNT_ENABLE       = "account.enable"
+	AUDIT_ACCOUNT_UNLOCK       = "account.unlock"
+
+	AUDIT_MFA_ENABLE       = "account.mfa.enable"
+	AUDIT_MFA_DISABLE      = "account.mfa.disable"
+	AUDIT_MFA_LOGIN_FAILED = "account.mfa.failed"
+
+	AUDIT_API_KEY_CREATE = "account.api_key.create"
+	AUDIT_API_KEY_REVOKE = "account.api_key.revoke"
+
+	AUDIT_IMPERSONATION_START = "account.impersonation.start"
+	AUDIT_IMPERSONATION_STOP  = "account.impersonation.stop"
+
+	AUDIT_ROLE_CREATE            = "role.create"
+	AUDIT_ROLE_PERMISSION_GRANT  = "role.permission.grant"
+	AUDIT_ROLE_PERMISSION_REVOKE = "role.permission.revoke"
+	AUDIT_ROLE_ASSIGN            = "role.assign"
+	AUDIT_ROLE_UNASSIGN          = "role.unassign"
+
+	AUDIT_ORGANIZATION_CREATE        = "organization.create"
+	AUDIT_ORGANIZATION_MEMBER_ADD    = "organization.member.add"
+	AUDIT_ORGANIZATION_MEMBER_UPDATE = "organization.member.update"
+	AUDIT_ORGANIZATION_MEMBER_REMOVE = "organization.member.remove"
+	AUDIT_INVITATION_CREATE          = "organization.invitation.create"
+	AUDIT_INVITATION_REVOKE          = "organization.invitation.revoke"
+	AUDIT_INVITATION_ACCEPT          = "organization.invitation.accept"
+)
+
+// AuditEvent is an append only record of a security relevant change. An ActorId of 0 marks
+// anonymous requests and the command line, in impersonation sessions the actor is the admin
+// and ImpersonatedId the account acted as.
+type AuditEvent struct {
+	Id             int64                  `json:"id"`
+	Action         string                 `json:"action"`
+	ActorId        int                    `json:"actorId"`
+	SubjectId      int                    `json:"subjectId"`
+	ImpersonatedId int                    `json:"impersonatedId"`
+	OrganizationId int                    `json:"organizationId"`
+	IP             string                 `json:"ip"`
+	UserAgent      string                 `json:"userAgent"`
+	RequestId      string                 `json:"requestId"`
+	Metadata       map[string]interface{} `json:"metadata"`
+	CreatedAt      time.Time              `json:"createdAt"`
+}
+
+// AuditFilter narrows audit queries, zero values match everything
+type AuditFilter struct {
+	Action         string
+	ActorId        int
+	SubjectId      int
+	OrganizationId int
+	From           *time.Time
+	To             *time.Time
+
+	// AccountId matches the events the account took part in, as actor, subject or impersonated account
+	AccountId int
+}
+
+type AuditHandler interface {
+	List(w http.ResponseWriter, r *http.Request)
+	Export(w http.ResponseWriter, r *http.Request)
+}
+
+type AuditService interface {
+	// Record appends the event with the request details of the context. It never fails the
+	// audited operation, store errors are logged instead.
+	Record(ctx context.Context, event *AuditEvent)
+	ListEvents(ctx context.Context, filter *AuditFilter, pagination *Pagination) ([]*AuditEvent, error)
+	// ExportEvents calls fn with every matching event, oldest first, without loading them all
+	ExportEvents(ctx context.Context, filter *AuditFilter, fn func(*AuditEvent) error) error
+	// PruneEvents removes the events older than the configured retention
+	PruneEvents(ctx context.Context) (int64, error)
+}
+
+type AuditRepository interface {
+	CreateEvent(ctx context.Context, event *AuditEvent) error
+	ListEvents(ctx context.Context, filter *AuditFilter, pagination *Pagination) ([]*AuditEvent, error)
+	StreamEvents(ctx context.Context, filter *AuditFilter, fn func(*AuditEvent) error) error
+	DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RequestInfo describes the client of a request, recorded with audit events
+type RequestInfo struct {
+	IP        string
+	UserAgent string
+	RequestId string
+}
+
+// WithRequestInfo attaches the client of the request to the context
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, "requestInfo", info)
+}
+
+// RequestInfoFromContext returns the client of the request, empty outside of requests
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value("requestInfo").(RequestInfo)
+	return info
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Identity links an account to a subject of an external OIDC provider
+type Identity struct {
+	Id        int
+	AccountId int
+	Provider  string
+	Subject   string
+	Email     string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type IdentityRepository interface {
+	CreateIdentity(ctx context.Context, identity *Identity) error
+	GetIdentity(ctx context.Context, provider, subject string) (*Identity, error)
+}
+
+// OIDCProvider describes a configured provider for the login page
+type OIDCProvider struct {
+	Name        string
+	DisplayName string
+}
+
+type OIDCService interface {
+	Providers() []OIDCProvider
+	// BeginLogin returns the authorization url of the provider and an opaque flow state,
+	// holding the state, nonce and PKCE verifier, that must be passed back to CompleteLogin.
+	BeginLogin(ctx context.Context, provider string) (authURL string, flowState string, err error)
+	// CompleteLogin exchanges the code, validates the ID token and returns the linked account,
+	// creating the account when the provider allows it.
+	CompleteLogin(ctx context.Context, provider, state, code, flowState string) (*Account, error)
+}
+
+var (
+	ErrIdentityNotFound       = errors.New("identity not found")
+	ErrOIDCProviderNotFound   = errors.New("oidc provider not found")
+	ErrOIDCInvalidState       = errors.New("invalid oidc state")
+	ErrOIDCInvalidNonce       = errors.New("invalid oidc nonce")
+	ErrOIDCEmailNotVerified   = errors.New("email not verified by the oidc provider")
+	ErrOIDCRegistrationClosed = errors.New("no account found for this identity")
+	ErrOIDCAccountUnverified  = errors.New("the account with this email is not verified")
+)
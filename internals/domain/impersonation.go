@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Impersonation sessions act as the subject account while the admin who started them stays
+// known as the actor. Their access tokens carry the actor in the act claim and ExtractAccount
+// returns it as the Impersonator of the account.
+
+type ImpersonationHandler interface {
+	Start(w http.ResponseWriter, r *http.Request)
+	Stop(w http.ResponseWriter, r *http.Request)
+}
+
+type ImpersonationService interface {
+	// Impersonate issues an access token acting as the account. It cannot be refreshed, the
+	// account may not be granted a permission the actor lacks and sessions cannot be nested.
+	Impersonate(ctx context.Context, actor *Account, accountId int) (string, error)
+	// StopImpersonation revokes the access token of an impersonation session
+	StopImpersonation(ctx context.Context, session *Account, token string) error
+}
+
+// WithImpersonator marks the context as acting for the actor in an impersonation session
+func WithImpersonator(ctx context.Context, actorId int) context.Context {
+	return context.WithValue(ctx, "impersonator", actorId)
+}
+
+// ImpersonatorFromContext returns the actor of an impersonation session
+func ImpersonatorFromContext(ctx context.Context) (int, bool) {
+	actorId, ok := ctx.Value("impersonator").(int)
+	return actorId, ok
+}
+
+// ForbidImpersonation guards sensitive operations, it returns ErrImpersonationForbidden in impersonation sessions
+func ForbidImpersonation(ctx context.Context) error {
+	if _, ok := ImpersonatorFromContext(ctx); ok {
+		return ErrImpersonationForbidden
+	}
+	return nil
+}
+
+var (
+	ErrImpersonationForbidden  = errors.New("not allowed while impersonating an account")
+	ErrImpersonateSelf         = errors.New("cannot impersonate your own account")
+	ErrImpersonationNested     = errors.New("already impersonating an account")
+	ErrImpersonationEscalation = errors.New("cannot impersonate an account granted permissions you lack")
+	ErrNotImpersonating        = errors.New("session is not impersonating an account")
+)
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Invitation asks the owner of an email address to join an organization with a role. Its
+// single use token is mailed to the address, only the hash is stored.
+type Invitation struct {
+	Id             int    `json:"id"`
+	OrganizationId int    `json:"organization_id"`
+	Email          string `json:"email"`
+	Role           string `json:"role"`
+	TokenHash      string `json:"-"`
+	InvitedBy      int    `json:"invited_by"`
+
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type InvitationHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+	Accept(w http.ResponseWriter, r *http.Request)
+	Register(w http.ResponseWriter, r *http.Request)
+}
+
+type InvitationService interface {
+	// Invite mails an invite link to the email, replacing a pending invitation for it
+	Invite(ctx context.Context, actor *OrganizationMember, email, role string) (*Invitation, error)
+	ListInvitations(ctx context.Context, organizationId int) ([]*Invitation, error)
+	RevokeInvitation(ctx context.Context, actor *OrganizationMember, id int) error
+
+	// GetInvitation returns the pending invitation of a token with its organization, without using the token
+	GetInvitation(ctx context.Context, token string) (*Invitation, *Organization, error)
+	// Accept attaches an existing account to the organization, the account email must be the invited one
+	Accept(ctx context.Context, token string, account *Account) (*OrganizationMember, error)
+	// AcceptWithRegistration creates the account of the invited email through AccountService.Register
+	// and attaches it, the email counts as verified since the link was delivered to it
+	AcceptWithRegistration(ctx context.Context, token string, account *Account) (*OrganizationMember, error)
+}
+
+// InvitationRepository list, create and delete are scoped to the tenant of the context
+type InvitationRepository interface {
+	CreateInvitation(ctx context.Context, invitation *Invitation) error
+	GetInvitationByHash(ctx context.Context, tokenHash string) (*Invitation, error)
+	ListInvitations(ctx context.Context) ([]*Invitation, error)
+	DeleteInvitation(ctx context.Context, id int) error
+	// MarkInvitationAccepted returns ErrInvitationUsed if the invitation was already accepted
+	MarkInvitationAccepted(ctx context.Context, id, accountId int) error
+}
+
+var (
+	ErrInvitationNotFound      = errors.New("invitation not found")
+	ErrInvitationExpired       = errors.New("invitation expired")
+	ErrInvitationUsed          = errors.New("invitation already accepted")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to another email address")
+	ErrInvitationAccountExists = errors.New("an account with the invited email exists, login to accept")
+	ErrInvitationEmailRequired = errors.New("invitation email is required")
+)
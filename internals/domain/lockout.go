@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Failed attempts are counted separately for the login identifier, the client address and the second factor of an account
+const (
+	LOCKOUT_SCOPE_ACCOUNT = "account"
+	LOCKOUT_SCOPE_IP      = "ip"
+	LOCKOUT_SCOPE_MFA     = "mfa"
+)
+
+// LockoutKey names a counter of failed attempts
+type LockoutKey struct {
+	Scope string
+	Key   string
+}
+
+// AccountLockoutKey counts failures for a login identifier, whether or not an account uses it
+func AccountLockoutKey(identifier string) LockoutKey {
+	return LockoutKey{Scope: LOCKOUT_SCOPE_ACCOUNT, Key: strings.ToLower(strings.TrimSpace(identifier))}
+}
+
+func IPLockoutKey(ip string) LockoutKey {
+	return LockoutKey{Scope: LOCKOUT_SCOPE_IP, Key: ip}
+}
+
+func MFALockoutKey(accountId int) LockoutKey {
+	return LockoutKey{Scope: LOCKOUT_SCOPE_MFA, Key: strconv.Itoa(accountId)}
+}
+
+// LoginAttempt tracks the recent failures of a key and the lock they caused
+type LoginAttempt struct {
+	Scope string
+	Key   string
+
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
+
+// LockoutError is returned while a key is locked, RetryAfter is the time left on the lock
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+type LockoutHandler interface {
+	Unlock(w http.ResponseWriter, r *http.Request)
+}
+
+type LockoutService interface {
+	// Check returns a LockoutError while any of the keys is locked
+	Check(ctx context.Context, keys ...LockoutKey) error
+	// RecordFailure counts a failed attempt, locking keys that passed their threshold with an exponential backoff
+	RecordFailure(ctx context.Context, keys ...LockoutKey) error
+	// Reset clears the failures of keys after a successful attempt
+	Reset(ctx context.Context, keys ...LockoutKey) error
+	// Unlock clears the login and second factor locks of an account
+	Unlock(ctx context.Context, accountId int) error
+
+	PruneLoginAttempts(ctx context.Context) (int64, error)
+}
+
+type LockoutRepository interface {
+	GetLoginAttempt(ctx context.Context, key LockoutKey) (*LoginAttempt, error)
+	// RecordLoginFailure increments the failures of the key, counting from one again when the last failure is before windowStart
+	RecordLoginFailure(ctx context.Context, key LockoutKey, now, windowStart time.Time) (*LoginAttempt, error)
+	LockLoginAttempt(ctx context.Context, key LockoutKey, until time.Time) error
+	DeleteLoginAttempt(ctx context.Context, key LockoutKey) error
+	// DeleteStaleLoginAttempts removes keys without failures since before that are not locked
+	DeleteStaleLoginAttempts(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	// ErrInvalidCredentials is returned for an unknown identifier and a wrong password alike
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrTooManyAttempts      = errors.New("too many failed attempts, try again later")
+	ErrLoginAttemptNotFound = errors.New("login attempt not found")
+)
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// AccountMFA holds the TOTP enrollment of an account, the secret is stored encrypted
+type AccountMFA struct {
+	AccountId int
+	Secret    string
+
+	// LastUsedStep is the TOTP time step of the last accepted code, codes up to it are refused
+	LastUsedStep int64
+
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsEnabled reports whether the enrollment was confirmed with a valid code
+func (m *AccountMFA) IsEnabled() bool {
+	return m.ConfirmedAt != nil
+}
+
+// MFAEnrollment is returned when enrolling, it is shown once so the secret can be added to an authenticator app
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+	QRCode string `json:"qr_code"`
+}
+
+type MFAHandler interface {
+	Verify(w http.ResponseWriter, r *http.Request)
+
+	Enroll(w http.ResponseWriter, r *http.Request)
+	Confirm(w http.ResponseWriter, r *http.Request)
+	Disable(w http.ResponseWriter, r *http.Request)
+	RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request)
+}
+
+type MFAService interface {
+	// Enroll creates a new unconfirmed secret, replacing any pending enrollment
+	Enroll(ctx context.Context, account *Account) (*MFAEnrollment, error)
+	// Confirm enables MFA once a valid code is presented and returns the recovery codes
+	Confirm(ctx context.Context, accountId int, code string) ([]string, error)
+	Disable(ctx context.Context, account *Account, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, accountId int, code string) ([]string, error)
+
+	IsEnabled(ctx context.Context, accountId int) (bool, error)
+	// IsRequired reports whether a role of the account is forced to use MFA
+	IsRequired(account *Account) bool
+	// Verify accepts a TOTP code or an unused recovery code
+	Verify(ctx context.Context, accountId int, code string) error
+	// VerifyLogin verifies the second factor of a login from the ip, locking the account and ip after repeated failures
+	VerifyLogin(ctx context.Context, accountId int, code, ip string) error
+}
+
+type MFARepository interface {
+	SaveMFA(ctx context.Context, mfa *AccountMFA) error
+	GetMFA(ctx context.Context, accountId int) (*AccountMFA, error)
+	ConfirmMFA(ctx context.Context, accountId int, confirmedAt time.Time) error
+	// DeleteMFA removes the enrollment along with its recovery codes
+	DeleteMFA(ctx context.Context, accountId int) error
+	// UseTOTPStep records the time step of an accepted code, it returns ErrInvalidMFACode when a
+	// code of that step or a later one was accepted already
+	UseTOTPStep(ctx context.Context, accountId int, step int64) error
+
+	ReplaceRecoveryCodes(ctx context.Context, accountId int, codeHashes []string) error
+	// UseRecoveryCode returns ErrInvalidMFACode if no unused code matches
+	UseRecoveryCode(ctx context.Context, accountId int, codeHash string) error
+}
+
+var (
+	ErrMFANotEnrolled    = errors.New("mfa not enrolled")
+	ErrMFANotEnabled     = errors.New("mfa not enabled")
+	ErrMFAAlreadyEnabled = errors.New("mfa already enabled")
+	ErrMFARequired       = errors.New("mfa is required for this account")
+	ErrInvalidMFACode    = errors.New("invalid mfa code")
+)
@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// OAuth scopes understood by the provider
+const (
+	OAUTH_SCOPE_OPENID         = "openid"
+	OAUTH_SCOPE_EMAIL          = "email"
+	OAUTH_SCOPE_PROFILE        = "profile"
+	OAUTH_SCOPE_OFFLINE_ACCESS = "offline_access"
+)
+
+// Kinds of tokens issued to OAuth clients
+const (
+	OAUTH_TOKEN_ACCESS  = "access_token"
+	OAUTH_TOKEN_REFRESH = "refresh_token"
+)
+
+// JSONWebKey is the public part of a signing key, RFC 7517
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// OAuthClient is an application registered to sign in with gostarter accounts.
+// Public clients have no secret and rely on PKCE alone.
+type OAuthClient struct {
+	ClientId     string
+	SecretHash   string
+	Name         string
+	RedirectURIs []string
+	Scopes       []string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (c *OAuthClient) IsPublic() bool {
+	return c.SecretHash == ""
+}
+
+// OAuthAuthorizationCode is the single use code handed to the client after consent, only its hash is stored
+type OAuthAuthorizationCode struct {
+	Id                  int
+	CodeHash            string
+	ClientId            string
+	AccountId           int
+	RedirectURI         string
+	Scope               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// OAuthToken is an opaque access or refresh token issued to a client, only its hash is stored
+type OAuthToken struct {
+	Id        int
+	TokenHash string
+	Kind      string
+	ClientId  string
+	AccountId int
+	Scope     string
+
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (t *OAuthToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// OAuthAuthorizationRequest holds the parameters of a request to the authorization endpoint
+type OAuthAuthorizationRequest struct {
+	ResponseType        string
+	ClientId            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// OAuthTokenRequest holds the parameters of a request to the token endpoint
+type OAuthTokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+
+	ClientId     string
+	ClientSecret string
+}
+
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IdToken      string `json:"id_token,omitempty"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthIntrospection is the introspection response, RFC 7662
+type OAuthIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientId  string `json:"client_id,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+}
+
+// OAuthError is an error reported to clients with the codes of RFC 6749
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// OAuth error codes
+const (
+	OAUTH_ERR_INVALID_REQUEST           = "invalid_request"
+	OAUTH_ERR_INVALID_CLIENT            = "invalid_client"
+	OAUTH_ERR_INVALID_GRANT             = "invalid_grant"
+	OAUTH_ERR_INVALID_SCOPE             = "invalid_scope"
+	OAUTH_ERR_INVALID_TOKEN             = "invalid_token"
+	OAUTH_ERR_ACCESS_DENIED             = "access_denied"
+	OAUTH_ERR_UNSUPPORTED_GRANT_TYPE    = "unsupported_grant_type"
+	OAUTH_ERR_UNSUPPORTED_RESPONSE_TYPE = "unsupported_response_type"
+)
+
+type OAuthHandler interface {
+	Discovery(w http.ResponseWriter, r *http.Request)
+	JWKS(w http.ResponseWriter, r *http.Request)
+	Token(w http.ResponseWriter, r *http.Request)
+	UserInfo(w http.ResponseWriter, r *http.Request)
+	Introspect(w http.ResponseWriter, r *http.Request)
+	Revoke(w http.ResponseWriter, r *http.Request)
+}
+
+type OAuthService interface {
+	// RegisterClient stores the client and returns its secret, which is empty for public clients
+	RegisterClient(ctx context.Context, client *OAuthClient, public bool) (string, error)
+	ListClients(ctx context.Context) ([]*OAuthClient, error)
+	DeleteClient(ctx context.Context, clientId string) error
+
+	// ValidateAuthorizationRequest checks the client and redirect uri first, errors about the
+	// redirect uri must be shown to the user instead of being sent to the client.
+	ValidateAuthorizationRequest(ctx context.Context, req *OAuthAuthorizationRequest) (*OAuthClient, error)
+	HasConsent(ctx context.Context, accountId int, clientId, scope string) (bool, error)
+	// Authorize records the consent of the account and returns an authorization code
+	Authorize(ctx context.Context, account *Account, req *OAuthAuthorizationRequest) (string, error)
+
+	Exchange(ctx context.Context, req *OAuthTokenRequest) (*OAuthTokenResponse, error)
+	UserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error)
+	Introspect(ctx context.Context, clientId, clientSecret, token string) (*OAuthIntrospection, error)
+	Revoke(ctx context.Context, clientId, clientSecret, token string) error
+
+	Discovery() map[string]interface{}
+	JWKS() []JSONWebKey
+}
+
+type OAuthClientRepository interface {
+	CreateClient(ctx context.Context, client *OAuthClient) error
+	GetClient(ctx context.Context, clientId string) (*OAuthClient, error)
+	ListClients(ctx context.Context) ([]*OAuthClient, error)
+	DeleteClient(ctx context.Context, clientId string) error
+}
+
+type OAuthGrantRepository interface {
+	CreateAuthorizationCode(ctx context.Context, code *OAuthAuthorizationCode) error
+	GetAuthorizationCodeByHash(ctx context.Context, codeHash string) (*OAuthAuthorizationCode, error)
+	// MarkAuthorizationCodeUsed returns ErrOAuthCodeUsed if the code was already exchanged
+	MarkAuthorizationCodeUsed(ctx context.Context, id int) error
+
+	CreateToken(ctx context.Context, token *OAuthToken) error
+	GetTokenByHash(ctx context.Context, tokenHash string) (*OAuthToken, error)
+	// RevokeToken returns ErrOAuthTokenRevoked if the token was already revoked
+	RevokeToken(ctx context.Context, id int) error
+	// RevokeTokensByGrant revokes every token of the account issued to the client
+	RevokeTokensByGrant(ctx context.Context, clientId string, accountId int) error
+
+	GetConsentScope(ctx context.Context, accountId int, clientId string) (string, error)
+	SaveConsent(ctx context.Context, accountId int, clientId, scope string) error
+}
+
+var (
+	ErrOAuthClientNotFound = NewOAuthError(OAUTH_ERR_INVALID_CLIENT, "client not found")
+	ErrOAuthCodeNotFound   = NewOAuthError(OAUTH_ERR_INVALID_GRANT, "authorization code not found")
+	ErrOAuthCodeUsed       = NewOAuthError(OAUTH_ERR_INVALID_GRANT, "authorization code already used")
+	ErrOAuthTokenNotFound  = NewOAuthError(OAUTH_ERR_INVALID_TOKEN, "token not found")
+	ErrOAuthTokenRevoked   = NewOAuthError(OAUTH_ERR_INVALID_GRANT, "token revoked")
+)
@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Organization roles are held per membership, each role includes the ones ranked below it
+const (
+	ORG_ROLE_OWNER  = "owner"
+	ORG_ROLE_ADMIN  = "admin"
+	ORG_ROLE_MEMBER = "member"
+)
+
+// ORG_ID_HEADER selects the organization a request acts in
+const ORG_ID_HEADER = "X-Org-ID"
+
+var orgRoleRank = map[string]int{
+	ORG_ROLE_MEMBER: 1,
+	ORG_ROLE_ADMIN:  2,
+	ORG_ROLE_OWNER:  3,
+}
+
+func ValidOrgRole(role string) bool {
+	_, ok := orgRoleRank[role]
+	return ok
+}
+
+// OrgRoleAtLeast reports whether the role ranks at or above the required one
+func OrgRoleAtLeast(role, required string) bool {
+	return ValidOrgRole(role) && orgRoleRank[role] >= orgRoleRank[required]
+}
+
+type Organization struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OrganizationMember struct {
+	OrganizationId int    `json:"organization_id"`
+	AccountId      int    `json:"account_id"`
+	Role           string `json:"role"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// HasRole reports whether the membership grants at least the organization role
+func (m *OrganizationMember) HasRole(role string) bool {
+	return OrgRoleAtLeast(m.Role, role)
+}
+
+// WithTenant scopes the context to an organization, tenant data is only read and written within it
+func WithTenant(ctx context.Context, organizationId int) context.Context {
+	return context.WithValue(ctx, "tenant", organizationId)
+}
+
+// TenantFromContext returns the organization the context is scoped to
+func TenantFromContext(ctx context.Context) (int, bool) {
+	organizationId, ok := ctx.Value("tenant").(int)
+	return organizationId, ok && organizationId > 0
+}
+
+type OrganizationService interface {
+	// CreateOrganization creates an organization owned by the account
+	CreateOrganization(ctx context.Context, account *Account, name string) (*Organization, error)
+	GetOrganization(ctx context.Context, id int) (*Organization, error)
+	// ListOrganizations returns the organizations the account is a member of
+	ListOrganizations(ctx context.Context, accountId int) ([]*Organization, error)
+
+	// GetMembership returns ErrNotOrganizationMember when the account is not a member
+	GetMembership(ctx context.Context, organizationId, accountId int) (*OrganizationMember, error)
+	ListMembers(ctx context.Context, organizationId int) ([]*OrganizationMember, error)
+
+	// Member changes are made by the actor membership, only owners hand out or take away the owner role
+	AddMember(ctx context.Context, actor *OrganizationMember, accountId int, role string) (*OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, actor *OrganizationMember, accountId int, role string) (*OrganizationMember, error)
+	RemoveMember(ctx context.Context, actor *OrganizationMember, accountId int) error
+}
+
+// OrganizationRepository member methods are scoped to the tenant of the context and return
+// ErrNoTenant without one
+type OrganizationRepository interface {
+	// CreateOrganization creates the organization with the account as its owner
+	CreateOrganization(ctx context.Context, organization *Organization, ownerId int) error
+	GetOrganizationByID(ctx context.Context, id int) (*Organization, error)
+	ListOrganizationsByAccount(ctx context.Context, accountId int) ([]*Organization, error)
+
+	GetMember(ctx context.Context, accountId int) (*OrganizationMember, error)
+	ListMembers(ctx context.Context) ([]*OrganizationMember, error)
+	AddMember(ctx context.Context, member *OrganizationMember) error
+	// UpdateMemberRole and RemoveMember return ErrLastOwner instead of leaving the organization without an owner
+	UpdateMemberRole(ctx context.Context, accountId int, role string) error
+	RemoveMember(ctx context.Context, accountId int) error
+}
+
+var (
+	ErrOrganizationNotFound      = errors.New("organization not found")
+	ErrOrganizationExists        = errors.New("organization already exists")
+	ErrOrganizationNameRequired  = errors.New("organization name is required")
+	ErrNotOrganizationMember     = errors.New("not a member of the organization")
+	ErrAlreadyOrganizationMember = errors.New("account is already a member of the organization")
+	ErrInvalidOrgRole            = errors.New("organization role must be owner, admin or member")
+	ErrLastOwner                 = errors.New("organization needs at least one owner")
+	ErrNoTenant                  = errors.New("no organization selected")
+)
@@ -1,5 +1,7 @@
 package domain
 
+import "errors"
+
 type Pagination struct {
 	Page  int `json:"page"`
 	Size  int `json:"size"`
@@ -13,3 +15,68 @@ func (p *Pagination) GetOffset() int {
 func (p *Pagination) SetTotal(total int) {
 	p.Total = total
 }
+
+// CursorPagination selects a page of a keyset ordered listing, First items After a cursor
+// going forward or Last items Before a cursor going backward. Cursors are opaque to callers,
+// they are returned with every item of a page.
+type CursorPagination struct {
+	First  int    `json:"first"`
+	After  string `json:"after"`
+	Last   int    `json:"last"`
+	Before string `json:"before"`
+
+	// WithTotal also counts every item of the listing, an extra query callers can skip
+	WithTotal bool `json:"with_total"`
+}
+
+// SetDefaultSize sets the page size when neither First nor Last is, reading backward from a
+// Before cursor and forward otherwise
+func (p *CursorPagination) SetDefaultSize(size int) {
+	if p.First != 0 || p.Last != 0 {
+		return
+	}
+	if p.Before != "" {
+		p.Last = size
+	} else {
+		p.First = size
+	}
+}
+
+// Backward reports whether the page is read backward from Before
+func (p *CursorPagination) Backward() bool {
+	return p.Last > 0
+}
+
+// Limit returns the page size
+func (p *CursorPagination) Limit() int {
+	if p.Backward() {
+		return p.Last
+	}
+	return p.First
+}
+
+// Validate returns ErrInvalidCursorPagination unless exactly one of First and Last is set,
+// with After only going forward and Before only going backward
+func (p *CursorPagination) Validate() error {
+	if p.First < 0 || p.Last < 0 || (p.First > 0) == (p.Last > 0) {
+		return ErrInvalidCursorPagination
+	}
+	if (p.First > 0 && p.Before != "") || (p.Last > 0 && p.After != "") {
+		return ErrInvalidCursorPagination
+	}
+	return nil
+}
+
+// ConnectionPageInfo describes a cursor page. Paging forward, HasPreviousPage is only known to
+// be true after a cursor, and paging backward the same holds for HasNextPage.
+type ConnectionPageInfo struct {
+	HasNextPage     bool    `json:"has_next_page"`
+	HasPreviousPage bool    `json:"has_previous_page"`
+	StartCursor     *string `json:"start_cursor"`
+	EndCursor       *string `json:"end_cursor"`
+}
+
+var (
+	ErrInvalidCursor           = errors.New("invalid cursor")
+	ErrInvalidCursorPagination = errors.New("either first with an optional after or last with an optional before is required")
+)
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Rules of the password policy, reported with each violation
+const (
+	PASSWORD_RULE_MIN_LENGTH = "min_length"
+	PASSWORD_RULE_MAX_LENGTH = "max_length"
+	PASSWORD_RULE_UPPERCASE  = "uppercase"
+	PASSWORD_RULE_LOWERCASE  = "lowercase"
+	PASSWORD_RULE_DIGIT      = "digit"
+	PASSWORD_RULE_SYMBOL     = "symbol"
+	PASSWORD_RULE_IDENTIFIER = "identifier"
+	PASSWORD_RULE_REUSE      = "reuse"
+	PASSWORD_RULE_BREACHED   = "breached"
+)
+
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError lists every rule a password failed
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return ErrPasswordPolicy.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordPolicy
+}
+
+type PasswordPolicyService interface {
+	// Validate checks a password chosen for the account, an account without an id is being registered
+	Validate(ctx context.Context, account *Account, password string) error
+	// Remember records the hash of a password set for the account, for the reuse rule
+	Remember(ctx context.Context, accountId int, passwordHash string) error
+}
+
+type PasswordHistoryRepository interface {
+	AddPasswordHistory(ctx context.Context, accountId int, passwordHash string) error
+	// ListPasswordHistory returns the latest hashes first
+	ListPasswordHistory(ctx context.Context, accountId int, limit int) ([]string, error)
+	// PrunePasswordHistory keeps the latest hashes of the account
+	PrunePasswordHistory(ctx context.Context, accountId int, keep int) error
+}
+
+var (
+	ErrPasswordPolicy = errors.New("password does not meet the policy")
+)
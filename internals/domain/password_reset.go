@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+)
+
+type PasswordResetHandler interface {
+	RequestPasswordReset(w http.ResponseWriter, r *http.Request)
+	ConfirmPasswordReset(w http.ResponseWriter, r *http.Request)
+}
+
+type PasswordResetService interface {
+	// RequestPasswordReset mails a reset link if the account exists, it never reports whether it does.
+	// The mail is sent in the background, delivery failures are only logged.
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+}
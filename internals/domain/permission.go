@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Permissions are `resource:action` names, granted to roles. A `*` action grants every
+// action on the resource and PERMISSION_ALL grants everything.
+const (
+	PERMISSION_ALL             = "*"
+	PERMISSION_ACCOUNTS_READ   = "accounts:read"
+	PERMISSION_ACCOUNTS_WRITE  = "accounts:write"
+	PERMISSION_ACCOUNTS_UNLOCK = "accounts:unlock"
+	PERMISSION_ROLES_MANAGE    = "roles:manage"
+
+	PERMISSION_ACCOUNTS_IMPERSONATE = "accounts:impersonate"
+	PERMISSION_AUDIT_READ           = "audit:read"
+)
+
+type Permission struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PermissionMatches reports whether a granted permission, possibly a wildcard, covers the required one
+func PermissionMatches(granted, required string) bool {
+	if granted == PERMISSION_ALL || granted == required {
+		return true
+	}
+
+	resource, found := strings.CutSuffix(granted, ":*")
+	return found && strings.HasPrefix(required, resource+":")
+}
+
+type PermissionService interface {
+	// ResolvePermissions returns the permissions granted to the roles, served from a cache
+	ResolvePermissions(ctx context.Context, roles []string) ([]string, error)
+	// HasPermission reports whether the session is granted the permission through its effective roles
+	HasPermission(ctx context.Context, account *Account, permission string) (bool, error)
+	// InvalidateCache drops resolved permissions after roles or grants change
+	InvalidateCache()
+
+	ListPermissions(ctx context.Context) ([]*Permission, error)
+}
+
+type PermissionRepository interface {
+	ListPermissions(ctx context.Context) ([]*Permission, error)
+	// GetRolePermissions returns the permission names granted to each of the roles
+	GetRolePermissions(ctx context.Context, roles []string) (map[string][]string, error)
+}
+
+var (
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrPermissionNotFound = errors.New("permission not found")
+	ErrRoleNotFound       = errors.New("role not found")
+)
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// Role names are lowercase identifiers, they travel space separated in API key scopes
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_.-]{0,63}$`)
+
+// ValidRoleName reports whether the name can be used for a new role
+func ValidRoleName(name string) bool {
+	return roleNamePattern.MatchString(name)
+}
+
+type Role struct {
+	Id          int      `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RoleHandler interface {
+	Create(w http.ResponseWriter, r *http.Request)
+	List(w http.ResponseWriter, r *http.Request)
+	GrantPermission(w http.ResponseWriter, r *http.Request)
+	RevokePermission(w http.ResponseWriter, r *http.Request)
+	AssignRole(w http.ResponseWriter, r *http.Request)
+	UnassignRole(w http.ResponseWriter, r *http.Request)
+}
+
+// RoleService manages roles and memberships. The actorId of every change is written to the
+// audit trail, 0 for changes made from the command line. Changes take effect for existing
+// sessions, membership changes revoke the access tokens of the account so its next refresh
+// carries the new roles.
+type RoleService interface {
+	CreateRole(ctx context.Context, actorId int, name string, permissions []string) (*Role, error)
+	ListRoles(ctx context.Context) ([]*Role, error)
+
+	GrantPermission(ctx context.Context, actorId int, role, permission string) error
+	RevokePermission(ctx context.Context, actorId int, role, permission string) error
+
+	AssignRole(ctx context.Context, actorId, accountId int, role string) error
+	UnassignRole(ctx context.Context, actorId, accountId int, role string) error
+	// SetRoles assigns and removes roles so the account has exactly the given ones, every role
+	// is checked to exist before any membership changes
+	SetRoles(ctx context.Context, actorId, accountId int, roles []string) error
+}
+
+type RoleRepository interface {
+	// CreateRole creates the role with its permissions, returning ErrRoleExists for a taken name
+	CreateRole(ctx context.Context, role *Role) error
+	ListRoles(ctx context.Context) ([]*Role, error)
+	GetRoleByName(ctx context.Context, name string) (*Role, error)
+
+	GrantPermission(ctx context.Context, role, permission string) error
+	RevokePermission(ctx context.Context, role, permission string) error
+
+	AssignRole(ctx context.Context, accountId int, role string) error
+	UnassignRole(ctx context.Context, accountId int, role string) error
+}
+
+var (
+	ErrInvalidRoleName          = errors.New("role names are lowercase letters, digits, '.', '_' and '-'")
+	ErrRoleExists               = errors.New("role already exists")
+	ErrRoleAlreadyAssigned      = errors.New("account already has the role")
+	ErrRoleNotAssigned          = errors.New("account does not have the role")
+	ErrPermissionAlreadyGranted = errors.New("role already has the permission")
+	ErrPermissionNotGranted     = errors.New("role does not have the permission")
+)
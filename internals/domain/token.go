@@ -1,7 +1,91 @@
 package domain
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Token types carried in the typ claim
+const (
+	TOKEN_TYPE_ACCESS      = "access"
+	TOKEN_TYPE_MFA_PENDING = "mfa_pending"
+)
+
 type TokenService interface {
-	GenerateJWT(id int, username string, roles []string) (string, error)
+	GenerateJWT(account *Account) (string, error)
+	// GenerateImpersonationJWT issues an access token for the subject carrying the actor in its act claim
+	GenerateImpersonationJWT(actor, subject *Account) (string, error)
 	VerifyJWT(token string) (bool, error)
 	ExtractAccount(token string) (*Account, error)
+
+	// GenerateMFAPendingToken issues a short lived token proving the password step of a login
+	GenerateMFAPendingToken(account *Account) (string, error)
+	// ValidateMFAPendingToken returns the account id of a pending token that was not used yet
+	ValidateMFAPendingToken(ctx context.Context, token string) (int, error)
+
+	// SignClaims signs arbitrary claims, such as ID tokens, with a kid header matching JWKS
+	SignClaims(claims map[string]interface{}) (string, error)
+	// JWKS returns the public keys of every key in the keyring, verification only keys included
+	JWKS() []JSONWebKey
+	// ReloadKeys picks up a rotated keyring without a restart
+	ReloadKeys(ctx context.Context) error
+
+	GenerateRefreshToken(ctx context.Context, accountId int) (string, error)
+	RotateRefreshToken(ctx context.Context, token string) (int, string, error)
+	RevokeRefreshToken(ctx context.Context, token string) error
+
+	RevokeJWT(ctx context.Context, token string) error
+	RevokeSession(ctx context.Context, accessToken, refreshToken string) error
+	RevokeAllSessions(ctx context.Context, accountId int) error
+	// RevokeAccessTokens invalidates the issued access tokens of the account but keeps its refresh
+	// tokens, so sessions pick up account changes such as new roles on their next refresh
+	RevokeAccessTokens(ctx context.Context, accountId int) error
+	IsRevoked(ctx context.Context, token string) (bool, error)
+	PruneRevokedTokens(ctx context.Context) (int64, error)
+}
+
+type RefreshToken struct {
+	Id        int
+	AccountId int
+	FamilyId  string
+	TokenHash string
+
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// RevokeRefreshToken marks a single token as used, it returns ErrRefreshTokenReused
+	// if the token was already revoked by a concurrent rotation.
+	RevokeRefreshToken(ctx context.Context, id int) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyId string) error
+	RevokeRefreshTokensByAccount(ctx context.Context, accountId int) error
+	// ListRefreshTokensByAccount returns the tokens of the account, newest first
+	ListRefreshTokensByAccount(ctx context.Context, accountId int) ([]*RefreshToken, error)
+}
+
+// RevokedToken is a denylist entry for a single access token, kept until the token expires
+type RevokedToken struct {
+	Jti       string
+	AccountId int
+	ExpiresAt time.Time
+}
+
+type TokenRevocationRepository interface {
+	RevokeToken(ctx context.Context, token *RevokedToken) error
+	// RevokeAccountTokens invalidates every token of the account issued before revokedBefore.
+	RevokeAccountTokens(ctx context.Context, accountId int, revokedBefore, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string, accountId int, issuedAt time.Time) (bool, error)
+	PruneExpired(ctx context.Context, now time.Time) (int64, error)
 }
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenExpired  = errors.New("refresh token expired")
+	ErrRefreshTokenReused   = errors.New("refresh token reused")
+	ErrUnknownSigningKey    = errors.New("token signed with an unknown key")
+)
@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+type VerificationService interface {
+	SendVerificationEmail(ctx context.Context, account *Account) error
+	VerifyEmail(ctx context.Context, token string) (*Account, error)
+	// SendEmailChange mails a confirmation link to the new address and a notice to the current one
+	SendEmailChange(ctx context.Context, account *Account, newEmail string) error
+	// ConfirmEmailChange moves the account to the address the token was sent to, it is verified by the link
+	ConfirmEmailChange(ctx context.Context, token string) (*Account, error)
+}
+
+var (
+	ErrEmailNotVerified     = errors.New("email not verified")
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+)
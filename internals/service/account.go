@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/pkg/utils"
 	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
 
 	"github.com/adharshmk96/goutils/auth"
 	"go.opentelemetry.io/otel/trace"
@@ -11,26 +17,96 @@ import (
 	"gostarter/internals/domain"
 )
 
+const defaultDeletionGrace = 30 * 24 * time.Hour
+
 type accountService struct {
 	logger *slog.Logger
 	tracer trace.Tracer
 
-	accountRepo domain.AccountRepository
+	unverifiedPolicy string
+	deletionGrace    time.Duration
+	// dummyHash is verified for unknown accounts, so they take as long to reject as a wrong password
+	dummyHash string
+
+	accountRepo           domain.AccountRepository
+	tokenService          domain.TokenService
+	verificationService   domain.VerificationService
+	lockoutService        domain.LockoutService
+	passwordPolicyService domain.PasswordPolicyService
+	auditService          domain.AuditService
 }
 
-func NewAccountService(container *infra.Container, accountRepo domain.AccountRepository) domain.AccountService {
+func NewAccountService(
+	container *infra.Container,
+	accountRepo domain.AccountRepository,
+	tokenService domain.TokenService,
+	verificationService domain.VerificationService,
+	lockoutService domain.LockoutService,
+	passwordPolicyService domain.PasswordPolicyService,
+	auditService domain.AuditService,
+) domain.AccountService {
 	logger := container.Logger.With("path", "accountService")
+
+	deletionGrace := time.Hour * 24 * time.Duration(container.Cfg.Auth.AccountDeletionGraceDays)
+	if deletionGrace <= 0 {
+		deletionGrace = defaultDeletionGrace
+	}
+
+	dummyHash, err := newDummyHash()
+	if err != nil {
+		logger.Error("failed to hash dummy password", "error", err)
+	}
+
 	return &accountService{
-		logger:      logger,
-		tracer:      container.Tracer,
-		accountRepo: accountRepo,
+		logger:                logger,
+		tracer:                container.Tracer,
+		unverifiedPolicy:      container.Cfg.Auth.UnverifiedPolicy,
+		deletionGrace:         deletionGrace,
+		dummyHash:             dummyHash,
+		accountRepo:           accountRepo,
+		tokenService:          tokenService,
+		verificationService:   verificationService,
+		lockoutService:        lockoutService,
+		passwordPolicyService: passwordPolicyService,
+		auditService:          auditService,
+	}
+}
+
+func newDummyHash() (string, error) {
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
 	}
+	return auth.HashPassword(password, auth.DefaultParams)
 }
 
 func (a *accountService) Register(ctx context.Context, account *domain.Account) error {
 	ctx, span := a.tracer.Start(ctx, "AccountService.Register")
 	defer span.End()
 
+	err := a.passwordPolicyService.Validate(ctx, account, account.Password)
+	if err != nil {
+		return err
+	}
+
+	return a.register(ctx, account)
+}
+
+func (a *accountService) RegisterExternal(ctx context.Context, account *domain.Account) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.RegisterExternal")
+	defer span.End()
+
+	return a.register(ctx, account)
+}
+
+func (a *accountService) register(ctx context.Context, account *domain.Account) error {
+	if account.Username != "" {
+		err := domain.ValidateUsername(account.Username)
+		if err != nil {
+			return err
+		}
+	}
+
 	passwdHash, err := auth.HashPassword(account.Password, auth.DefaultParams)
 	if err != nil {
 		return err
@@ -38,31 +114,118 @@ func (a *accountService) Register(ctx context.Context, account *domain.Account)
 
 	account.Password = passwdHash
 
-	return a.accountRepo.CreateAccount(ctx, account)
+	err = a.accountRepo.CreateAccount(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_REGISTER,
+		ActorId:   account.Id,
+		SubjectId: account.Id,
+	})
+
+	err = a.passwordPolicyService.Remember(ctx, account.Id, passwdHash)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to remember password", "error", err, "accountId", account.Id)
+	}
+
+	if account.IsEmailVerified() {
+		return nil
+	}
+
+	// The account is created, a failed mail can be resent later
+	err = a.verificationService.SendVerificationEmail(ctx, account)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to send verification email", "error", err, "accountId", account.Id)
+	}
+
+	return nil
 }
 
-func (a *accountService) Authenticate(ctx context.Context, email, password string) (*domain.Account, error) {
+func (a *accountService) Authenticate(ctx context.Context, identifier, password, ip string) (*domain.Account, error) {
 	ctx, span := a.tracer.Start(ctx, "AccountService.Authenticate")
 	defer span.End()
 
-	account, err := a.accountRepo.GetAccountByEmail(ctx, email)
+	// Usernames cannot hold an @, so the identifier is either an email or a username
+	var account *domain.Account
+	var err error
+	if strings.Contains(identifier, "@") {
+		account, err = a.accountRepo.GetAccountByEmail(ctx, identifier)
+	} else {
+		account, err = a.accountRepo.GetAccountByUsername(ctx, identifier)
+	}
+	if err != nil && !errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, err
+	}
+
+	// Failures are counted against the email of known accounts, whichever identifier was used
+	accountKey := domain.AccountLockoutKey(identifier)
+	if account != nil {
+		accountKey = domain.AccountLockoutKey(account.Email)
+	}
+
+	keys := []domain.LockoutKey{accountKey, domain.IPLockoutKey(ip)}
+	err = a.lockoutService.Check(ctx, keys...)
 	if err != nil {
 		return nil, err
 	}
 
-	if account == nil {
-		return nil, domain.ErrAccountNotFound
+	passwordHash := a.dummyHash
+	if account != nil {
+		passwordHash = account.Password
 	}
 
-	match, err := auth.VerifyPasswordHash(password, account.Password)
+	match, err := auth.VerifyPasswordHash(password, passwordHash)
 	if err != nil {
 		return nil, err
 	}
 
-	if !match {
-		return nil, domain.ErrAccountNotFound
+	// Unknown accounts and wrong passwords are counted and reported the same way
+	if account == nil || !match {
+		err = a.lockoutService.RecordFailure(ctx, keys...)
+		if err != nil {
+			a.logger.ErrorContext(ctx, "failed to record login failure", "error", err)
+		}
+
+		// Known accounts are recorded by id, so their identifiers are not kept after a purge
+		event := &domain.AuditEvent{
+			Action:   domain.AUDIT_ACCOUNT_LOGIN_FAILED,
+			Metadata: map[string]interface{}{"identifier": identifier},
+		}
+		if account != nil {
+			event.SubjectId = account.Id
+			event.Metadata = nil
+		}
+		a.auditService.Record(ctx, event)
+		return nil, domain.ErrInvalidCredentials
 	}
 
+	// The address keeps its failures, a valid login of one account does not clear guesses at others
+	err = a.lockoutService.Reset(ctx, accountKey)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to reset login failures", "error", err)
+	}
+
+	// Only the right password learns that the account is disabled
+	if account.IsDisabled() {
+		a.auditService.Record(ctx, &domain.AuditEvent{
+			Action:    domain.AUDIT_ACCOUNT_LOGIN_FAILED,
+			SubjectId: account.Id,
+			Metadata:  map[string]interface{}{"reason": "disabled"},
+		})
+		return nil, domain.ErrAccountDisabled
+	}
+
+	if !account.IsEmailVerified() && a.unverifiedPolicy == config.UNVERIFIED_POLICY_BLOCK_LOGIN {
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_LOGIN,
+		ActorId:   account.Id,
+		SubjectId: account.Id,
+	})
 	return account, nil
 }
 
@@ -80,23 +243,437 @@ func (a *accountService) GetAccountByEmail(ctx context.Context, email string) (*
 	return a.accountRepo.GetAccountByEmail(ctx, email)
 }
 
+func (a *accountService) GetAccountByUsername(ctx context.Context, username string) (*domain.Account, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.GetAccountByUsername")
+	defer span.End()
+
+	return a.accountRepo.GetAccountByUsername(ctx, username)
+}
+
 func (a *accountService) UpdateAccount(ctx context.Context, account *domain.Account) error {
 	ctx, span := a.tracer.Start(ctx, "AccountService.UpdateAccount")
 	defer span.End()
 
-	return a.accountRepo.UpdateAccount(ctx, account)
+	current, err := a.accountRepo.GetAccountByID(ctx, account.Id)
+	if err != nil {
+		return err
+	}
+
+	// Usernames set before the rules are kept until they are changed
+	if account.Username != "" && account.Username != current.Username {
+		err = domain.ValidateUsername(account.Username)
+		if err != nil {
+			return err
+		}
+	}
+
+	emailChanged := account.Email != current.Email
+	if emailChanged {
+		account.Email, err = parseEmail(account.Email)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = a.accountRepo.UpdateAccount(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// Refreshed access tokens carry the new address
+	if emailChanged {
+		err = a.tokenService.RevokeAccessTokens(ctx, account.Id)
+		if err != nil {
+			a.logger.ErrorContext(ctx, "failed to revoke access tokens after email change", "error", err, "accountId", account.Id)
+		}
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_UPDATE,
+		SubjectId: account.Id,
+	})
+	return nil
+}
+
+func (a *accountService) DisableAccount(ctx context.Context, id int) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.DisableAccount")
+	defer span.End()
+
+	account, err := a.accountRepo.GetAccountByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if account.IsDisabled() {
+		return domain.ErrAccountDisabled
+	}
+
+	now := time.Now()
+	err = a.accountRepo.SetAccountDisabled(ctx, id, &now)
+	if err != nil {
+		return err
+	}
+
+	err = a.tokenService.RevokeAllSessions(ctx, id)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to revoke sessions of disabled account", "error", err, "accountId", id)
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_DISABLE,
+		SubjectId: id,
+	})
+	return nil
+}
+
+func (a *accountService) EnableAccount(ctx context.Context, id int) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.EnableAccount")
+	defer span.End()
+
+	account, err := a.accountRepo.GetAccountByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !account.IsDisabled() {
+		return domain.ErrAccountNotDisabled
+	}
+
+	err = a.accountRepo.SetAccountDisabled(ctx, id, nil)
+	if err != nil {
+		return err
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_ENABLE,
+		SubjectId: id,
+	})
+	return nil
 }
 
 func (a *accountService) DeleteAccount(ctx context.Context, id int) error {
 	ctx, span := a.tracer.Start(ctx, "AccountService.DeleteAccount")
 	defer span.End()
 
-	return a.accountRepo.DeleteAccount(ctx, id)
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = a.accountRepo.SoftDeleteAccount(ctx, id, now)
+	if err != nil {
+		return err
+	}
+
+	// Access tokens are not checked against the account, they have to be revoked
+	err = a.tokenService.RevokeAllSessions(ctx, id)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to revoke sessions of deleted account", "error", err, "accountId", id)
+	}
+
+	// The actor is the account of the request, or the command line
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_DELETE,
+		SubjectId: id,
+		Metadata:  map[string]interface{}{"purgeAfter": now.Add(a.deletionGrace)},
+	})
+	return nil
+}
+
+func (a *accountService) RestoreAccount(ctx context.Context, id int) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.RestoreAccount")
+	defer span.End()
+
+	err := a.accountRepo.RestoreAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_RESTORE,
+		SubjectId: id,
+	})
+	return nil
 }
 
-func (a *accountService) ListAccounts(ctx context.Context, pagination *domain.Pagination) ([]*domain.Account, error) {
+func (a *accountService) PurgeAccount(ctx context.Context, id int) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.PurgeAccount")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = a.accountRepo.DeleteAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_PURGE,
+		SubjectId: id,
+	})
+	return nil
+}
+
+func (a *accountService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.PurgeDeletedAccounts")
+	defer span.End()
+
+	ids, err := a.accountRepo.PurgeDeletedAccounts(ctx, time.Now().Add(-a.deletionGrace))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		a.auditService.Record(ctx, &domain.AuditEvent{
+			Action:    domain.AUDIT_ACCOUNT_PURGE,
+			SubjectId: id,
+		})
+	}
+
+	return int64(len(ids)), nil
+}
+
+// SetPassword checks the password policy, then hashes and stores a new password for the account
+func (a *accountService) SetPassword(ctx context.Context, account *domain.Account, password string) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.SetPassword")
+	defer span.End()
+
+	err := a.ValidatePassword(ctx, account, password)
+	if err != nil {
+		return err
+	}
+
+	passwdHash, err := auth.HashPassword(password, auth.DefaultParams)
+	if err != nil {
+		return err
+	}
+
+	account.Password = passwdHash
+
+	err = a.accountRepo.UpdateAccount(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	err = a.passwordPolicyService.Remember(ctx, account.Id, passwdHash)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to remember password", "error", err, "accountId", account.Id)
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_PASSWORD,
+		SubjectId: account.Id,
+	})
+	return nil
+}
+
+func (a *accountService) ValidatePassword(ctx context.Context, account *domain.Account, password string) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.ValidatePassword")
+	defer span.End()
+
+	return a.passwordPolicyService.Validate(ctx, account, password)
+}
+
+func (a *accountService) ListAccounts(ctx context.Context, filter *domain.AccountFilter, sort []domain.AccountSort, pagination *domain.Pagination) ([]*domain.Account, error) {
 	ctx, span := a.tracer.Start(ctx, "AccountService.ListAccounts")
 	defer span.End()
 
-	return a.accountRepo.ListAccounts(ctx, pagination)
+	err := validateAccountListing(filter, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.accountRepo.ListAccounts(ctx, filter, sort, pagination)
+}
+
+func (a *accountService) ListAccountsByCursor(ctx context.Context, filter *domain.AccountFilter, sort []domain.AccountSort, pagination *domain.CursorPagination) (*domain.AccountConnection, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.ListAccountsByCursor")
+	defer span.End()
+
+	err := validateAccountListing(filter, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pagination.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.accountRepo.ListAccountsByCursor(ctx, filter, sort, pagination)
+}
+
+func (a *accountService) SearchAccounts(ctx context.Context, query string, limit int) ([]*domain.AccountSearchResult, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.SearchAccounts")
+	defer span.End()
+
+	terms := utils.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, domain.ErrInvalidSearchQuery
+	}
+
+	results, err := a.accountRepo.SearchAccounts(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		result.UsernameHighlight = utils.Highlight(result.Account.Username, terms)
+		result.EmailHighlight = utils.Highlight(result.Account.Email, terms)
+	}
+
+	return results, nil
+}
+
+func validateAccountListing(filter *domain.AccountFilter, sort []domain.AccountSort) error {
+	err := filter.Validate()
+	if err != nil {
+		return err
+	}
+
+	return domain.ValidateAccountSort(sort)
+}
+
+func (a *accountService) VerifyPassword(ctx context.Context, accountId int, password string) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.VerifyPassword")
+	defer span.End()
+
+	account, err := a.accountRepo.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	return a.verifyPassword(ctx, account, password)
+}
+
+// verifyPassword checks the password of a loaded account, the client address of the request
+// is locked along with the account after repeated failures
+func (a *accountService) verifyPassword(ctx context.Context, account *domain.Account, password string) error {
+	keys := []domain.LockoutKey{domain.AccountLockoutKey(account.Email)}
+	if ip := domain.RequestInfoFromContext(ctx).IP; ip != "" {
+		keys = append(keys, domain.IPLockoutKey(ip))
+	}
+
+	err := a.lockoutService.Check(ctx, keys...)
+	if err != nil {
+		return err
+	}
+
+	match, err := auth.VerifyPasswordHash(password, account.Password)
+	if err != nil {
+		return err
+	}
+
+	if !match {
+		err = a.lockoutService.RecordFailure(ctx, keys...)
+		if err != nil {
+			a.logger.ErrorContext(ctx, "failed to record password failure", "error", err)
+		}
+
+		a.auditService.Record(ctx, &domain.AuditEvent{
+			Action:    domain.AUDIT_ACCOUNT_LOGIN_FAILED,
+			SubjectId: account.Id,
+			Metadata:  map[string]interface{}{"reauthentication": true},
+		})
+		return domain.ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+func (a *accountService) ChangePassword(ctx context.Context, accountId int, currentPassword, newPassword string) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.ChangePassword")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return err
+	}
+
+	account, err := a.accountRepo.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	err = a.verifyPassword(ctx, account, currentPassword)
+	if err != nil {
+		return err
+	}
+
+	err = a.SetPassword(ctx, account, newPassword)
+	if err != nil {
+		return err
+	}
+
+	// Sessions started with the old password end, like after a reset
+	return a.tokenService.RevokeAllSessions(ctx, accountId)
+}
+
+func (a *accountService) RequestEmailChange(ctx context.Context, accountId int, password, newEmail string) error {
+	ctx, span := a.tracer.Start(ctx, "AccountService.RequestEmailChange")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return err
+	}
+
+	newEmail, err = parseEmail(newEmail)
+	if err != nil {
+		return err
+	}
+
+	account, err := a.accountRepo.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	err = a.verifyPassword(ctx, account, password)
+	if err != nil {
+		return err
+	}
+
+	// The unique index has the final say when the change is confirmed
+	_, err = a.accountRepo.GetAccountByEmail(ctx, newEmail)
+	if err == nil {
+		return domain.ErrEmailTaken
+	}
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		return err
+	}
+
+	return a.verificationService.SendEmailChange(ctx, account, newEmail)
+}
+
+func (a *accountService) ConfirmEmailChange(ctx context.Context, token string) (*domain.Account, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountService.ConfirmEmailChange")
+	defer span.End()
+
+	account, err := a.verificationService.ConfirmEmailChange(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refreshed access tokens carry the new address
+	err = a.tokenService.RevokeAccessTokens(ctx, account.Id)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to revoke access tokens after email change", "error", err, "accountId", account.Id)
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_EMAIL_CHANGE,
+		SubjectId: account.Id,
+	})
+	return account, nil
+}
+
+// parseEmail returns the trimmed address, only a bare address is accepted and not a display name form
+func parseEmail(email string) (string, error) {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != strings.TrimSpace(email) {
+		return "", domain.ErrInvalidEmail
+	}
+	return address.Address, nil
 }
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"io"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type accountExportService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accountService   domain.AccountService
+	refreshTokenRepo domain.RefreshTokenRepository
+	auditService     domain.AuditService
+}
+
+func NewAccountExportService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	auditService domain.AuditService,
+) domain.AccountExportService {
+	logger := container.Logger.With("path", "accountExportService")
+	return &accountExportService{
+		logger:           logger,
+		tracer:           container.Tracer,
+		accountService:   accountService,
+		refreshTokenRepo: refreshTokenRepo,
+		auditService:     auditService,
+	}
+}
+
+func (a *accountExportService) Export(ctx context.Context, accountId int) (*domain.AccountExport, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountExportService.Export")
+	defer span.End()
+
+	// The data of the account is not for the admin acting as it
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := a.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := a.refreshTokenRepo.ListRefreshTokensByAccount(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*domain.ExportedSession, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, &domain.ExportedSession{
+			Id:        token.Id,
+			ExpiresAt: token.ExpiresAt,
+			RevokedAt: token.RevokedAt,
+			CreatedAt: token.CreatedAt,
+		})
+	}
+
+	events := []*domain.AuditEvent{}
+	err = a.auditService.ExportEvents(ctx, &domain.AuditFilter{AccountId: accountId}, func(event *domain.AuditEvent) error {
+		// Events the account was only the subject of, or impersonated in, were taken by someone
+		// else, whose identity and connection are not part of this account's data
+		if event.ActorId != accountId {
+			event.ActorId = 0
+			event.IP = ""
+			event.UserAgent = ""
+		}
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := account.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_EXPORT,
+		SubjectId: accountId,
+	})
+
+	return &domain.AccountExport{
+		ExportedAt: time.Now(),
+		Account: &domain.ExportedAccount{
+			Id:              account.Id,
+			Username:        account.Username,
+			Email:           account.Email,
+			EmailVerifiedAt: account.EmailVerifiedAt,
+			CreatedAt:       account.CreatedAt,
+			UpdatedAt:       account.UpdatedAt,
+		},
+		Roles:       roles,
+		Sessions:    sessions,
+		AuditEvents: events,
+	}, nil
+}
+
+func (a *accountExportService) WriteArchive(export *domain.AccountExport, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"account.json", export.Account},
+		{"roles.json", export.Roles},
+		{"sessions.json", export.Sessions},
+		{"audit_events.json", export.AuditEvents},
+	}
+	for _, file := range files {
+		fw, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(fw)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(file.data)
+		if err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
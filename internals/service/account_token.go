@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"time"
+)
+
+const accountTokenBytes = 32
+
+// accountTokens issues and consumes signed single use tokens that are mailed to account owners
+type accountTokens struct {
+	secret string
+	repo   domain.AccountTokenRepository
+}
+
+func newAccountTokens(secret string, repo domain.AccountTokenRepository) *accountTokens {
+	return &accountTokens{
+		secret: secret,
+		repo:   repo,
+	}
+}
+
+// issue invalidates earlier tokens of the same purpose and returns a new signed token
+func (t *accountTokens) issue(ctx context.Context, accountId int, purpose string, ttl time.Duration) (string, error) {
+	return t.issueWithPayload(ctx, accountId, purpose, "", ttl)
+}
+
+// issueWithPayload issues a token confirming the payload, it is returned with the consumed token
+func (t *accountTokens) issueWithPayload(ctx context.Context, accountId int, purpose, payload string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken(accountTokenBytes)
+	if err != nil {
+		return "", err
+	}
+
+	err = t.repo.InvalidateAccountTokens(ctx, accountId, purpose)
+	if err != nil {
+		return "", err
+	}
+
+	err = t.repo.CreateAccountToken(ctx, &domain.AccountToken{
+		AccountId: accountId,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		Payload:   payload,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return utils.SignToken(t.secret, token), nil
+}
+
+// consume validates the token and marks it used so it cannot be presented again
+func (t *accountTokens) consume(ctx context.Context, purpose, signed string) (*domain.AccountToken, error) {
+	stored, err := t.lookup(ctx, purpose, signed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.markUsed(ctx, stored)
+	if err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// lookup validates the token without using it, for flows that check more input before consuming
+func (t *accountTokens) lookup(ctx context.Context, purpose, signed string) (*domain.AccountToken, error) {
+	token, ok := utils.VerifySignedToken(t.secret, signed)
+	if !ok {
+		return nil, domain.ErrAccountTokenNotFound
+	}
+
+	stored, err := t.repo.GetAccountTokenByHash(ctx, purpose, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.UsedAt != nil {
+		return nil, domain.ErrAccountTokenUsed
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, domain.ErrAccountTokenExpired
+	}
+
+	return stored, nil
+}
+
+// markUsed consumes a looked up token, it fails if the token was used in the meantime
+func (t *accountTokens) markUsed(ctx context.Context, stored *domain.AccountToken) error {
+	return t.repo.MarkAccountTokenUsed(ctx, stored.Id)
+}
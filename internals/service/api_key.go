@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	apiKeyBytes = 32
+	// apiKeyDisplayLength is how much of the token after the prefix is kept to tell keys apart
+	apiKeyDisplayLength = 8
+	// apiKeyTouchInterval limits last use updates to one write per key and interval
+	apiKeyTouchInterval = time.Minute
+)
+
+type apiKeyService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accountService domain.AccountService
+	apiKeyRepo     domain.APIKeyRepository
+	auditService   domain.AuditService
+}
+
+func NewAPIKeyService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	apiKeyRepo domain.APIKeyRepository,
+	auditService domain.AuditService,
+) domain.APIKeyService {
+	logger := container.Logger.With("path", "apiKeyService")
+	return &apiKeyService{
+		logger:         logger,
+		tracer:         container.Tracer,
+		accountService: accountService,
+		apiKeyRepo:     apiKeyRepo,
+		auditService:   auditService,
+	}
+}
+
+func (a *apiKeyService) Create(
+	ctx context.Context,
+	account *domain.Account,
+	name string,
+	scopes []string,
+	expiresAt *time.Time,
+) (*domain.APIKey, string, error) {
+	ctx, span := a.tracer.Start(ctx, "APIKeyService.Create")
+	defer span.End()
+
+	// A key would outlive the impersonation session
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", domain.ErrAPIKeyNameRequired
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", domain.ErrAPIKeyExpired
+	}
+
+	// Without scopes the key gets every role of the session creating it
+	if len(scopes) == 0 {
+		for _, role := range account.Roles {
+			if account.HasRole(role) {
+				scopes = append(scopes, role)
+			}
+		}
+	}
+
+	// A key never gets more than the session creating it, so a key cannot mint a broader one
+	for _, scope := range scopes {
+		if !account.HasRole(scope) {
+			return nil, "", domain.ErrAPIKeyInvalidScope
+		}
+	}
+
+	random, err := utils.GenerateRandomToken(apiKeyBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	token := domain.API_KEY_PREFIX + random
+
+	key := &domain.APIKey{
+		AccountId: account.Id,
+		Name:      name,
+		Prefix:    domain.API_KEY_PREFIX + random[:apiKeyDisplayLength],
+		TokenHash: utils.HashToken(token),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+
+	err = a.apiKeyRepo.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_API_KEY_CREATE,
+		ActorId:   account.Id,
+		SubjectId: account.Id,
+		Metadata:  map[string]interface{}{"apiKeyId": key.Id, "name": key.Name, "scopes": key.Scopes},
+	})
+	return key, token, nil
+}
+
+func (a *apiKeyService) List(ctx context.Context, accountId int) ([]*domain.APIKey, error) {
+	ctx, span := a.tracer.Start(ctx, "APIKeyService.List")
+	defer span.End()
+
+	return a.apiKeyRepo.ListAPIKeys(ctx, accountId)
+}
+
+func (a *apiKeyService) Revoke(ctx context.Context, accountId, id int) error {
+	ctx, span := a.tracer.Start(ctx, "APIKeyService.Revoke")
+	defer span.End()
+
+	err := a.apiKeyRepo.DeleteAPIKey(ctx, accountId, id)
+	if err != nil {
+		return err
+	}
+
+	a.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_API_KEY_REVOKE,
+		ActorId:   accountId,
+		SubjectId: accountId,
+		Metadata:  map[string]interface{}{"apiKeyId": id},
+	})
+	return nil
+}
+
+func (a *apiKeyService) Authenticate(ctx context.Context, token string) (*domain.Account, error) {
+	ctx, span := a.tracer.Start(ctx, "APIKeyService.Authenticate")
+	defer span.End()
+
+	if !strings.HasPrefix(token, domain.API_KEY_PREFIX) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	key, err := a.apiKeyRepo.GetAPIKeyByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.IsExpired(now) {
+		return nil, domain.ErrAPIKeyExpired
+	}
+
+	account, err := a.accountService.GetAccountByID(ctx, key.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if account.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	// Roles are read from the account on every request, scopes only narrow them
+	account.Scopes = append([]string{}, key.Scopes...)
+	// Keys are created from sessions that already passed MFA
+	account.MFAVerified = true
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		err = a.apiKeyRepo.TouchAPIKey(ctx, key.Id, now)
+		if err != nil {
+			a.logger.ErrorContext(ctx, "failed to record api key use", "error", err, "apiKeyId", key.Id)
+		}
+	}
+
+	return account, nil
+}
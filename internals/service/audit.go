@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type auditService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	retention time.Duration
+
+	auditRepo domain.AuditRepository
+}
+
+func NewAuditService(container *infra.Container, auditRepo domain.AuditRepository) domain.AuditService {
+	logger := container.Logger.With("path", "auditService")
+	return &auditService{
+		logger:    logger,
+		tracer:    container.Tracer,
+		retention: time.Hour * 24 * time.Duration(container.Cfg.Audit.RetentionDays),
+		auditRepo: auditRepo,
+	}
+}
+
+func (a *auditService) Record(ctx context.Context, event *domain.AuditEvent) {
+	ctx, span := a.tracer.Start(ctx, "AuditService.Record")
+	defer span.End()
+
+	// The actor defaults to the account of the request
+	if account, ok := ctx.Value("account").(*domain.Account); ok && event.ActorId == 0 {
+		event.ActorId = account.Id
+	}
+
+	info := domain.RequestInfoFromContext(ctx)
+	event.IP = info.IP
+	event.UserAgent = info.UserAgent
+	event.RequestId = info.RequestId
+
+	// Changes made in an impersonation session are made by the admin behind it
+	if impersonatorId, ok := domain.ImpersonatorFromContext(ctx); ok && event.ActorId != impersonatorId {
+		event.ImpersonatedId = event.ActorId
+		event.ActorId = impersonatorId
+	}
+
+	if event.OrganizationId == 0 {
+		event.OrganizationId, _ = domain.TenantFromContext(ctx)
+	}
+	if event.Metadata == nil {
+		event.Metadata = map[string]interface{}{}
+	}
+
+	// The log line keeps a trail when the store is unavailable
+	a.logger.InfoContext(ctx, event.Action,
+		"audit", true,
+		"action", event.Action,
+		"actorId", event.ActorId,
+		"subjectId", event.SubjectId,
+		"impersonatedId", event.ImpersonatedId,
+		"organizationId", event.OrganizationId,
+		"metadata", event.Metadata,
+	)
+
+	err := a.auditRepo.CreateEvent(ctx, event)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to record audit event", "error", err, "action", event.Action)
+	}
+}
+
+func (a *auditService) ListEvents(ctx context.Context, filter *domain.AuditFilter, pagination *domain.Pagination) ([]*domain.AuditEvent, error) {
+	ctx, span := a.tracer.Start(ctx, "AuditService.ListEvents")
+	defer span.End()
+
+	return a.auditRepo.ListEvents(ctx, filter, pagination)
+}
+
+func (a *auditService) ExportEvents(ctx context.Context, filter *domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	ctx, span := a.tracer.Start(ctx, "AuditService.ExportEvents")
+	defer span.End()
+
+	return a.auditRepo.StreamEvents(ctx, filter, fn)
+}
+
+func (a *auditService) PruneEvents(ctx context.Context) (int64, error) {
+	ctx, span := a.tracer.Start(ctx, "AuditService.PruneEvents")
+	defer span.End()
+
+	if a.retention <= 0 {
+		return 0, nil
+	}
+
+	return a.auditRepo.DeleteEventsBefore(ctx, time.Now().Add(-a.retention))
+}
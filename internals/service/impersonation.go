@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type impersonationService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accountService    domain.AccountService
+	tokenService      domain.TokenService
+	permissionService domain.PermissionService
+	auditService      domain.AuditService
+}
+
+func NewImpersonationService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	tokenService domain.TokenService,
+	permissionService domain.PermissionService,
+	auditService domain.AuditService,
+) domain.ImpersonationService {
+	logger := container.Logger.With("path", "impersonationService")
+	return &impersonationService{
+		logger:            logger,
+		tracer:            container.Tracer,
+		accountService:    accountService,
+		tokenService:      tokenService,
+		permissionService: permissionService,
+		auditService:      auditService,
+	}
+}
+
+func (i *impersonationService) Impersonate(ctx context.Context, actor *domain.Account, accountId int) (string, error) {
+	ctx, span := i.tracer.Start(ctx, "ImpersonationService.Impersonate")
+	defer span.End()
+
+	if actor.IsImpersonated() {
+		return "", domain.ErrImpersonationNested
+	}
+	if actor.Id == accountId {
+		return "", domain.ErrImpersonateSelf
+	}
+
+	subject, err := i.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return "", err
+	}
+	if subject.IsDisabled() {
+		return "", domain.ErrAccountDisabled
+	}
+
+	// Acting as an account granted more than the actor would escalate its privileges
+	permissions, err := i.permissionService.ResolvePermissions(ctx, subject.Roles)
+	if err != nil {
+		return "", err
+	}
+	for _, permission := range permissions {
+		allowed, err := i.permissionService.HasPermission(ctx, actor, permission)
+		if err != nil {
+			return "", err
+		}
+		if !allowed {
+			return "", domain.ErrImpersonationEscalation
+		}
+	}
+
+	token, err := i.tokenService.GenerateImpersonationJWT(actor, subject)
+	if err != nil {
+		return "", err
+	}
+
+	i.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_IMPERSONATION_START,
+		ActorId:   actor.Id,
+		SubjectId: subject.Id,
+	})
+	return token, nil
+}
+
+func (i *impersonationService) StopImpersonation(ctx context.Context, session *domain.Account, token string) error {
+	ctx, span := i.tracer.Start(ctx, "ImpersonationService.StopImpersonation")
+	defer span.End()
+
+	if !session.IsImpersonated() {
+		return domain.ErrNotImpersonating
+	}
+
+	err := i.tokenService.RevokeJWT(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	i.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_IMPERSONATION_STOP,
+		ActorId:   session.Impersonator.Id,
+		SubjectId: session.Id,
+	})
+	return nil
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gostarter/infra"
+	"gostarter/infra/mailer"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultInvitationExpiry = 7 * 24 * time.Hour
+	invitationTokenBytes    = 32
+)
+
+type invitationService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+	mailer mailer.Mailer
+
+	baseURL string
+	secret  string
+	expiry  time.Duration
+
+	accountService   domain.AccountService
+	organizationRepo domain.OrganizationRepository
+	invitationRepo   domain.InvitationRepository
+	auditService     domain.AuditService
+}
+
+func NewInvitationService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	organizationRepo domain.OrganizationRepository,
+	invitationRepo domain.InvitationRepository,
+	auditService domain.AuditService,
+) domain.InvitationService {
+	expiry := time.Hour * time.Duration(container.Cfg.Auth.InvitationExpirationHours)
+	if expiry <= 0 {
+		expiry = defaultInvitationExpiry
+	}
+
+	logger := container.Logger.With("path", "invitationService")
+	return &invitationService{
+		logger:           logger,
+		tracer:           container.Tracer,
+		mailer:           container.Mailer,
+		baseURL:          container.Cfg.Server.GetBaseURL(),
+		secret:           container.Cfg.Encryption.Key,
+		expiry:           expiry,
+		accountService:   accountService,
+		organizationRepo: organizationRepo,
+		invitationRepo:   invitationRepo,
+		auditService:     auditService,
+	}
+}
+
+func (i *invitationService) Invite(
+	ctx context.Context,
+	actor *domain.OrganizationMember,
+	email, role string,
+) (*domain.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "InvitationService.Invite")
+	defer span.End()
+
+	if strings.TrimSpace(email) == "" {
+		return nil, domain.ErrInvitationEmailRequired
+	}
+
+	// Pending invitations are replaced by email, so it is kept in normalized form
+	email, err := parseEmail(email)
+	if err != nil {
+		return nil, err
+	}
+	email = utils.NormalizeIdentifier(email)
+
+	if !domain.ValidOrgRole(role) {
+		return nil, domain.ErrInvalidOrgRole
+	}
+
+	err = authorizeRoleChange(actor, role)
+	if err != nil {
+		return nil, err
+	}
+
+	organization, err := i.organizationRepo.GetOrganizationByID(ctx, actor.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomToken(invitationTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &domain.Invitation{
+		Email:     email,
+		Role:      role,
+		TokenHash: utils.HashToken(token),
+		InvitedBy: actor.AccountId,
+		ExpiresAt: time.Now().Add(i.expiry),
+	}
+
+	err = i.invitationRepo.CreateInvitation(domain.WithTenant(ctx, actor.OrganizationId), invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	link := i.baseURL + "/invitations/accept?token=" + url.QueryEscape(utils.SignToken(i.secret, token))
+
+	err = i.mailer.Send(ctx, mailer.Message{
+		To:      []string{email},
+		Subject: fmt.Sprintf("You are invited to join %s", organization.Name),
+		Body: fmt.Sprintf(
+			"You have been invited to join %s as %s. Accept the invitation by opening the link below.\n\n%s\n\nThe link expires in %s.",
+			organization.Name, role, link, i.expiry,
+		),
+	})
+	if err != nil {
+		// An invitation nobody received is not left to be accepted
+		deleteErr := i.invitationRepo.DeleteInvitation(domain.WithTenant(ctx, actor.OrganizationId), invitation.Id)
+		if deleteErr != nil {
+			i.logger.ErrorContext(ctx, "failed to delete unsent invitation", "invitationId", invitation.Id, "error", deleteErr)
+		}
+		return nil, err
+	}
+
+	i.auditService.Record(ctx, &domain.AuditEvent{
+		Action:         domain.AUDIT_INVITATION_CREATE,
+		ActorId:        actor.AccountId,
+		OrganizationId: actor.OrganizationId,
+		Metadata:       map[string]interface{}{"invitationId": invitation.Id, "email": email, "role": role},
+	})
+
+	return invitation, nil
+}
+
+func (i *invitationService) ListInvitations(ctx context.Context, organizationId int) ([]*domain.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "InvitationService.ListInvitations")
+	defer span.End()
+
+	return i.invitationRepo.ListInvitations(domain.WithTenant(ctx, organizationId))
+}
+
+func (i *invitationService) RevokeInvitation(ctx context.Context, actor *domain.OrganizationMember, id int) error {
+	ctx, span := i.tracer.Start(ctx, "InvitationService.RevokeInvitation")
+	defer span.End()
+
+	if !actor.HasRole(domain.ORG_ROLE_ADMIN) {
+		return domain.ErrPermissionDenied
+	}
+
+	err := i.invitationRepo.DeleteInvitation(domain.WithTenant(ctx, actor.OrganizationId), id)
+	if err != nil {
+		return err
+	}
+
+	i.auditService.Record(ctx, &domain.AuditEvent{
+		Action:         domain.AUDIT_INVITATION_REVOKE,
+		ActorId:        actor.AccountId,
+		OrganizationId: actor.OrganizationId,
+		Metadata:       map[string]interface{}{"invitationId": id},
+	})
+	return nil
+}
+
+// lookup returns the pending invitation of a signed token
+func (i *invitationService) lookup(ctx context.Context, signed string) (*domain.Invitation, error) {
+	token, ok := utils.VerifySignedToken(i.secret, signed)
+	if !ok {
+		return nil, domain.ErrInvitationNotFound
+	}
+
+	invitation, err := i.invitationRepo.GetInvitationByHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.AcceptedAt != nil {
+		return nil, domain.ErrInvitationUsed
+	}
+
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, domain.ErrInvitationExpired
+	}
+
+	return invitation, nil
+}
+
+func (i *invitationService) GetInvitation(ctx context.Context, token string) (*domain.Invitation, *domain.Organization, error) {
+	ctx, span := i.tracer.Start(ctx, "InvitationService.GetInvitation")
+	defer span.End()
+
+	invitation, err := i.lookup(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	organization, err := i.organizationRepo.GetOrganizationByID(ctx, invitation.OrganizationId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return invitation, organization, nil
+}
+
+func (i *invitationService) Accept(ctx context.Context, token string, account *domain.Account) (*domain.OrganizationMember, error) {
+	ctx, span := i.tracer.Start(ctx, "InvitationService.Accept")
+	defer span.End()
+
+	invitation, err := i.lookup(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if utils.NormalizeIdentifier(invitation.Email) != utils.NormalizeIdentifier(account.Email) {
+		return nil, domain.ErrInvitationEmailMismatch
+	}
+
+	_, err = i.organizationRepo.GetMember(domain.WithTenant(ctx, invitation.OrganizationId), account.Id)
+	if err == nil {
+		return nil, domain.ErrAlreadyOrganizationMember
+	}
+	if !errors.Is(err, domain.ErrNotOrganizationMember) {
+		return nil, err
+	}
+
+	return i.join(ctx, invitation, account.Id)
+}
+
+func (i *invitationService) AcceptWithRegistration(
+	ctx context.Context,
+	token string,
+	account *domain.Account,
+) (*domain.OrganizationMember, error) {
+	ctx, span := i.tracer.Start(ctx, "InvitationService.AcceptWithRegistration")
+	defer span.End()
+
+	invitation, err := i.lookup(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = i.accountService.GetAccountByEmail(ctx, invitation.Email)
+	if err == nil {
+		return nil, domain.ErrInvitationAccountExists
+	}
+	if !errors.Is(err, domain.ErrAccountNotFound) {
+		return nil, err
+	}
+
+	// The invite link reached the address, so it needs no verification mail
+	now := time.Now()
+	account.Email = invitation.Email
+	account.EmailVerifiedAt = &now
+
+	err = i.accountService.Register(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.join(ctx, invitation, account.Id)
+}
+
+// join uses the invitation and adds the account with the invited role
+func (i *invitationService) join(ctx context.Context, invitation *domain.Invitation, accountId int) (*domain.OrganizationMember, error) {
+	err := i.invitationRepo.MarkInvitationAccepted(ctx, invitation.Id, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &domain.OrganizationMember{AccountId: accountId, Role: invitation.Role}
+	err = i.organizationRepo.AddMember(domain.WithTenant(ctx, invitation.OrganizationId), member)
+	if err != nil {
+		return nil, err
+	}
+
+	i.auditService.Record(ctx, &domain.AuditEvent{
+		Action:         domain.AUDIT_INVITATION_ACCEPT,
+		ActorId:        accountId,
+		SubjectId:      accountId,
+		OrganizationId: invitation.OrganizationId,
+		Metadata:       map[string]interface{}{"invitationId": invitation.Id, "role": invitation.Role},
+	})
+	return member, nil
+}
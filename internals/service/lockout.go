@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultLockoutAccountThreshold = 5
+	defaultLockoutIPThreshold      = 50
+	defaultLockoutBase             = 30 * time.Second
+	defaultLockoutMax              = time.Hour
+	defaultLockoutWindow           = time.Hour
+)
+
+type lockoutService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accountThreshold int
+	ipThreshold      int
+	base             time.Duration
+	max              time.Duration
+	window           time.Duration
+
+	accountRepo  domain.AccountRepository
+	lockoutRepo  domain.LockoutRepository
+	auditService domain.AuditService
+}
+
+func NewLockoutService(
+	container *infra.Container,
+	accountRepo domain.AccountRepository,
+	lockoutRepo domain.LockoutRepository,
+	auditService domain.AuditService,
+) domain.LockoutService {
+	cfg := container.Cfg.Auth
+
+	accountThreshold := cfg.LockoutAccountThreshold
+	if accountThreshold <= 0 {
+		accountThreshold = defaultLockoutAccountThreshold
+	}
+	ipThreshold := cfg.LockoutIPThreshold
+	if ipThreshold <= 0 {
+		ipThreshold = defaultLockoutIPThreshold
+	}
+	base := time.Second * time.Duration(cfg.LockoutBaseSeconds)
+	if base <= 0 {
+		base = defaultLockoutBase
+	}
+	maxLock := time.Second * time.Duration(cfg.LockoutMaxSeconds)
+	if maxLock <= 0 {
+		maxLock = defaultLockoutMax
+	}
+	window := time.Minute * time.Duration(cfg.LockoutWindowMinutes)
+	if window <= 0 {
+		window = defaultLockoutWindow
+	}
+
+	logger := container.Logger.With("path", "lockoutService")
+	return &lockoutService{
+		logger:           logger,
+		tracer:           container.Tracer,
+		accountThreshold: accountThreshold,
+		ipThreshold:      ipThreshold,
+		base:             base,
+		max:              maxLock,
+		window:           window,
+		accountRepo:      accountRepo,
+		lockoutRepo:      lockoutRepo,
+		auditService:     auditService,
+	}
+}
+
+func (l *lockoutService) threshold(key domain.LockoutKey) int {
+	if key.Scope == domain.LOCKOUT_SCOPE_IP {
+		return l.ipThreshold
+	}
+	return l.accountThreshold
+}
+
+// lockDuration doubles the base lock for every failure past the threshold
+func (l *lockoutService) lockDuration(failures, threshold int) time.Duration {
+	lock := l.base
+	for i := threshold; i < failures && lock < l.max; i++ {
+		lock *= 2
+	}
+	return min(lock, l.max)
+}
+
+func (l *lockoutService) Check(ctx context.Context, keys ...domain.LockoutKey) error {
+	ctx, span := l.tracer.Start(ctx, "LockoutService.Check")
+	defer span.End()
+
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, key := range keys {
+		if key.Key == "" {
+			continue
+		}
+
+		attempt, err := l.lockoutRepo.GetLoginAttempt(ctx, key)
+		if errors.Is(err, domain.ErrLoginAttemptNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if attempt.IsLocked(now) {
+			retryAfter = max(retryAfter, attempt.LockedUntil.Sub(now))
+		}
+	}
+
+	if retryAfter > 0 {
+		return &domain.LockoutError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (l *lockoutService) RecordFailure(ctx context.Context, keys ...domain.LockoutKey) error {
+	ctx, span := l.tracer.Start(ctx, "LockoutService.RecordFailure")
+	defer span.End()
+
+	now := time.Now()
+	for _, key := range keys {
+		if key.Key == "" {
+			continue
+		}
+
+		attempt, err := l.lockoutRepo.RecordLoginFailure(ctx, key, now, now.Add(-l.window))
+		if err != nil {
+			return err
+		}
+
+		threshold := l.threshold(key)
+		if attempt.Failures < threshold {
+			continue
+		}
+
+		lockedUntil := now.Add(l.lockDuration(attempt.Failures, threshold))
+		err = l.lockoutRepo.LockLoginAttempt(ctx, key, lockedUntil)
+		if err != nil {
+			return err
+		}
+		l.logger.WarnContext(ctx, "login locked", "scope", key.Scope, "key", key.Key, "failures", attempt.Failures, "lockedUntil", lockedUntil)
+	}
+
+	return nil
+}
+
+func (l *lockoutService) Reset(ctx context.Context, keys ...domain.LockoutKey) error {
+	ctx, span := l.tracer.Start(ctx, "LockoutService.Reset")
+	defer span.End()
+
+	for _, key := range keys {
+		if key.Key == "" {
+			continue
+		}
+
+		err := l.lockoutRepo.DeleteLoginAttempt(ctx, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (l *lockoutService) Unlock(ctx context.Context, accountId int) error {
+	ctx, span := l.tracer.Start(ctx, "LockoutService.Unlock")
+	defer span.End()
+
+	account, err := l.accountRepo.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	err = l.Reset(ctx, domain.AccountLockoutKey(account.Email), domain.MFALockoutKey(account.Id))
+	if err != nil {
+		return err
+	}
+
+	// The actor is the admin of the request
+	l.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_UNLOCK,
+		SubjectId: account.Id,
+	})
+	return nil
+}
+
+func (l *lockoutService) PruneLoginAttempts(ctx context.Context) (int64, error) {
+	ctx, span := l.tracer.Start(ctx, "LockoutService.PruneLoginAttempts")
+	defer span.End()
+
+	return l.lockoutRepo.DeleteStaleLoginAttempts(ctx, time.Now().Add(-l.window))
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"gostarter/pkg/totp"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultMFAIssuer   = "gostarter"
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+	// totpSkew accepts codes from one time step before and after the current one
+	totpSkew = 1
+)
+
+type mfaService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	issuer        string
+	encryptionKey string
+	requiredRoles []string
+
+	mfaRepo        domain.MFARepository
+	lockoutService domain.LockoutService
+	auditService   domain.AuditService
+}
+
+func NewMFAService(
+	container *infra.Container,
+	mfaRepo domain.MFARepository,
+	lockoutService domain.LockoutService,
+	auditService domain.AuditService,
+) domain.MFAService {
+	issuer := container.Cfg.Auth.MFAIssuer
+	if issuer == "" {
+		issuer = defaultMFAIssuer
+	}
+
+	logger := container.Logger.With("path", "mfaService")
+	return &mfaService{
+		logger:         logger,
+		tracer:         container.Tracer,
+		issuer:         issuer,
+		encryptionKey:  container.Cfg.Encryption.Key,
+		requiredRoles:  container.Cfg.Auth.MFARequiredRoles,
+		mfaRepo:        mfaRepo,
+		lockoutService: lockoutService,
+		auditService:   auditService,
+	}
+}
+
+func (m *mfaService) Enroll(ctx context.Context, account *domain.Account) (*domain.MFAEnrollment, error) {
+	ctx, span := m.tracer.Start(ctx, "MFAService.Enroll")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := m.mfaRepo.GetMFA(ctx, account.Id)
+	if err != nil && !errors.Is(err, domain.ErrMFANotEnrolled) {
+		return nil, err
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := utils.Encrypt(m.encryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.mfaRepo.SaveMFA(ctx, &domain.AccountMFA{
+		AccountId: account.Id,
+		Secret:    encrypted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(m.issuer, account.Email, secret)
+	qrCode, err := totp.QRCodeDataURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret: secret,
+		URI:    uri,
+		QRCode: qrCode,
+	}, nil
+}
+
+func (m *mfaService) Confirm(ctx context.Context, accountId int, code string) ([]string, error) {
+	ctx, span := m.tracer.Start(ctx, "MFAService.Confirm")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := m.mfaRepo.GetMFA(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.IsEnabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	ok, err := m.validateTOTP(ctx, mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	err = m.mfaRepo.ConfirmMFA(ctx, accountId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	m.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_MFA_ENABLE,
+		ActorId:   accountId,
+		SubjectId: accountId,
+	})
+
+	return m.issueRecoveryCodes(ctx, accountId)
+}
+
+func (m *mfaService) Disable(ctx context.Context, account *domain.Account, code string) error {
+	ctx, span := m.tracer.Start(ctx, "MFAService.Disable")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return err
+	}
+
+	if m.IsRequired(account) {
+		return domain.ErrMFARequired
+	}
+
+	err = m.Verify(ctx, account.Id, code)
+	if err != nil {
+		return err
+	}
+
+	err = m.mfaRepo.DeleteMFA(ctx, account.Id)
+	if err != nil {
+		return err
+	}
+
+	m.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_MFA_DISABLE,
+		ActorId:   account.Id,
+		SubjectId: account.Id,
+	})
+	return nil
+}
+
+func (m *mfaService) RegenerateRecoveryCodes(ctx context.Context, accountId int, code string) ([]string, error) {
+	ctx, span := m.tracer.Start(ctx, "MFAService.RegenerateRecoveryCodes")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.Verify(ctx, accountId, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.issueRecoveryCodes(ctx, accountId)
+}
+
+func (m *mfaService) IsEnabled(ctx context.Context, accountId int) (bool, error) {
+	ctx, span := m.tracer.Start(ctx, "MFAService.IsEnabled")
+	defer span.End()
+
+	mfa, err := m.mfaRepo.GetMFA(ctx, accountId)
+	if errors.Is(err, domain.ErrMFANotEnrolled) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return mfa.IsEnabled(), nil
+}
+
+func (m *mfaService) IsRequired(account *domain.Account) bool {
+	for _, role := range account.Roles {
+		if slices.Contains(m.requiredRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *mfaService) Verify(ctx context.Context, accountId int, code string) error {
+	ctx, span := m.tracer.Start(ctx, "MFAService.Verify")
+	defer span.End()
+
+	mfa, err := m.mfaRepo.GetMFA(ctx, accountId)
+	if errors.Is(err, domain.ErrMFANotEnrolled) {
+		return domain.ErrMFANotEnabled
+	}
+	if err != nil {
+		return err
+	}
+	if !mfa.IsEnabled() {
+		return domain.ErrMFANotEnabled
+	}
+
+	ok, err := m.validateTOTP(ctx, mfa, code)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+
+	// Fall back to a single use recovery code
+	err = m.mfaRepo.UseRecoveryCode(ctx, accountId, utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+
+	m.logger.InfoContext(ctx, "recovery code used", "accountId", accountId)
+	return nil
+}
+
+func (m *mfaService) VerifyLogin(ctx context.Context, accountId int, code, ip string) error {
+	ctx, span := m.tracer.Start(ctx, "MFAService.VerifyLogin")
+	defer span.End()
+
+	keys := []domain.LockoutKey{domain.MFALockoutKey(accountId), domain.IPLockoutKey(ip)}
+	err := m.lockoutService.Check(ctx, keys...)
+	if err != nil {
+		return err
+	}
+
+	err = m.Verify(ctx, accountId, code)
+	if errors.Is(err, domain.ErrInvalidMFACode) {
+		lockErr := m.lockoutService.RecordFailure(ctx, keys...)
+		if lockErr != nil {
+			m.logger.ErrorContext(ctx, "failed to record mfa failure", "error", lockErr)
+		}
+		m.auditService.Record(ctx, &domain.AuditEvent{
+			Action:    domain.AUDIT_MFA_LOGIN_FAILED,
+			SubjectId: accountId,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	err = m.lockoutService.Reset(ctx, domain.MFALockoutKey(accountId))
+	if err != nil {
+		m.logger.ErrorContext(ctx, "failed to reset mfa failures", "error", err)
+	}
+
+	return nil
+}
+
+// validateTOTP accepts a code once, a code of a time step at or before the last accepted one is refused
+func (m *mfaService) validateTOTP(ctx context.Context, mfa *domain.AccountMFA, code string) (bool, error) {
+	secret, err := utils.Decrypt(m.encryptionKey, mfa.Secret)
+	if err != nil {
+		m.logger.ErrorContext(ctx, "failed to decrypt mfa secret", "accountId", mfa.AccountId, "error", err)
+		return false, err
+	}
+
+	step, ok := totp.ValidateStep(secret, code, time.Now(), totpSkew)
+	if !ok || step <= mfa.LastUsedStep {
+		return false, nil
+	}
+
+	// Recording the step only succeeds once, so a code raced in twice is accepted once
+	err = m.mfaRepo.UseTOTPStep(ctx, mfa.AccountId, step)
+	if errors.Is(err, domain.ErrInvalidMFACode) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// issueRecoveryCodes replaces the recovery codes of the account, the plain codes are only returned here
+func (m *mfaService) issueRecoveryCodes(ctx context.Context, accountId int) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = utils.HashToken(normalizeRecoveryCode(code))
+	}
+
+	err := m.mfaRepo.ReplaceRecoveryCodes(ctx, accountId, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCode returns a code formatted as xxxxx-xxxxx for readability
+func generateRecoveryCode() (string, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(secret[:recoveryCodeLength])
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/domain"
+	"gostarter/pkg/testUtils"
+	"gostarter/pkg/totp"
+	"gostarter/pkg/utils"
+	"testing"
+	"time"
+)
+
+const testMFAEncryptionKey = "test-encryption-key"
+
+// mfaTestRepository keeps one enrollment and records steps like the conditional update of the postgres store
+type mfaTestRepository struct {
+	domain.MFARepository
+	mfa domain.AccountMFA
+}
+
+func (r *mfaTestRepository) GetMFA(ctx context.Context, accountId int) (*domain.AccountMFA, error) {
+	mfa := r.mfa
+	return &mfa, nil
+}
+
+func (r *mfaTestRepository) UseTOTPStep(ctx context.Context, accountId int, step int64) error {
+	if step <= r.mfa.LastUsedStep {
+		return domain.ErrInvalidMFACode
+	}
+	r.mfa.LastUsedStep = step
+	return nil
+}
+
+func newMFATest(t *testing.T) (*mfaService, *mfaTestRepository, string) {
+	t.Helper()
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	encrypted, err := utils.Encrypt(testMFAEncryptionKey, secret)
+	if err != nil {
+		t.Fatalf("encrypt secret: %v", err)
+	}
+
+	container := &infra.Container{
+		Cfg: &config.Config{
+			Encryption: config.EncryptionConfig{Key: testMFAEncryptionKey},
+		},
+		Logger: testUtils.NewNoopLogger(),
+		Tracer: testUtils.NewNoopTracer(),
+	}
+
+	repo := &mfaTestRepository{mfa: domain.AccountMFA{AccountId: 1, Secret: encrypted}}
+	service := NewMFAService(container, repo, nil, nil).(*mfaService)
+
+	return service, repo, secret
+}
+
+func validateStoredTOTP(t *testing.T, m *mfaService, repo *mfaTestRepository, code string) bool {
+	t.Helper()
+
+	mfa, err := repo.GetMFA(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetMFA: %v", err)
+	}
+	ok, err := m.validateTOTP(context.Background(), mfa, code)
+	if err != nil {
+		t.Fatalf("validateTOTP: %v", err)
+	}
+	return ok
+}
+
+func TestValidateTOTPRejectsReusedCode(t *testing.T) {
+	m, repo, secret := newMFATest(t)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+
+	if !validateStoredTOTP(t, m, repo, code) {
+		t.Fatalf("first use of the code was refused")
+	}
+	if validateStoredTOTP(t, m, repo, code) {
+		t.Fatalf("reused code was accepted")
+	}
+}
+
+func TestValidateTOTPRejectsEarlierStep(t *testing.T) {
+	m, repo, secret := newMFATest(t)
+	now := time.Now()
+
+	current, err := totp.GenerateCode(secret, now)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	previous, err := totp.GenerateCode(secret, now.Add(-totp.Period))
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+
+	if !validateStoredTOTP(t, m, repo, current) {
+		t.Fatalf("code of the current step was refused")
+	}
+	// Still within the skew, but older than the accepted code
+	if validateStoredTOTP(t, m, repo, previous) {
+		t.Fatalf("code of an earlier step was accepted after a later one")
+	}
+}
+
+func TestValidateTOTPConcurrentReuse(t *testing.T) {
+	m, repo, secret := newMFATest(t)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+
+	// Both requests read the enrollment before either accepted the code
+	first, _ := repo.GetMFA(context.Background(), 1)
+	second, _ := repo.GetMFA(context.Background(), 1)
+
+	ok, err := m.validateTOTP(context.Background(), first, code)
+	if err != nil || !ok {
+		t.Fatalf("first validateTOTP = %v, %v, want true", ok, err)
+	}
+	ok, err = m.validateTOTP(context.Background(), second, code)
+	if err != nil || ok {
+		t.Fatalf("second validateTOTP = %v, %v, want false", ok, err)
+	}
+}
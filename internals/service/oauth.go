@@ -0,0 +1,647 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	oauthClientIdBytes     = 16
+	oauthClientSecretBytes = 32
+	oauthCodeBytes         = 32
+	oauthTokenBytes        = 32
+
+	defaultOAuthCodeExpiry    = 5 * time.Minute
+	defaultOAuthAccessExpiry  = time.Hour
+	defaultOAuthRefreshExpiry = 30 * 24 * time.Hour
+
+	pkceMethodS256     = "S256"
+	pkceVerifierMinLen = 43
+	pkceVerifierMaxLen = 128
+)
+
+var supportedOAuthScopes = []string{
+	domain.OAUTH_SCOPE_OPENID,
+	domain.OAUTH_SCOPE_EMAIL,
+	domain.OAUTH_SCOPE_PROFILE,
+	domain.OAUTH_SCOPE_OFFLINE_ACCESS,
+}
+
+type oauthService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	issuer        string
+	codeExpiry    time.Duration
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+
+	accountService domain.AccountService
+	tokenService   domain.TokenService
+	clientRepo     domain.OAuthClientRepository
+	grantRepo      domain.OAuthGrantRepository
+}
+
+func NewOAuthService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	tokenService domain.TokenService,
+	clientRepo domain.OAuthClientRepository,
+	grantRepo domain.OAuthGrantRepository,
+) domain.OAuthService {
+	cfg := container.Cfg.OAuth
+
+	codeExpiry := time.Minute * time.Duration(cfg.AuthorizationCodeExpirationMinutes)
+	if codeExpiry <= 0 {
+		codeExpiry = defaultOAuthCodeExpiry
+	}
+	accessExpiry := time.Minute * time.Duration(cfg.AccessTokenExpirationMinutes)
+	if accessExpiry <= 0 {
+		accessExpiry = defaultOAuthAccessExpiry
+	}
+	refreshExpiry := time.Hour * time.Duration(cfg.RefreshTokenExpirationHours)
+	if refreshExpiry <= 0 {
+		refreshExpiry = defaultOAuthRefreshExpiry
+	}
+
+	logger := container.Logger.With("path", "oauthService")
+	return &oauthService{
+		logger:         logger,
+		tracer:         container.Tracer,
+		issuer:         container.Cfg.Server.GetBaseURL(),
+		codeExpiry:     codeExpiry,
+		accessExpiry:   accessExpiry,
+		refreshExpiry:  refreshExpiry,
+		accountService: accountService,
+		tokenService:   tokenService,
+		clientRepo:     clientRepo,
+		grantRepo:      grantRepo,
+	}
+}
+
+func (o *oauthService) RegisterClient(ctx context.Context, client *domain.OAuthClient, public bool) (string, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.RegisterClient")
+	defer span.End()
+
+	if client.Name == "" || len(client.RedirectURIs) == 0 {
+		return "", domain.NewOAuthError(domain.OAUTH_ERR_INVALID_REQUEST, "name and redirect uri are required")
+	}
+
+	for _, redirectURI := range client.RedirectURIs {
+		parsed, err := url.Parse(redirectURI)
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return "", domain.NewOAuthError(domain.OAUTH_ERR_INVALID_REQUEST, "invalid redirect uri "+redirectURI)
+		}
+	}
+
+	if len(client.Scopes) == 0 {
+		client.Scopes = []string{domain.OAUTH_SCOPE_OPENID, domain.OAUTH_SCOPE_EMAIL, domain.OAUTH_SCOPE_PROFILE}
+	}
+	for _, scope := range client.Scopes {
+		if !slices.Contains(supportedOAuthScopes, scope) {
+			return "", domain.NewOAuthError(domain.OAUTH_ERR_INVALID_SCOPE, "unsupported scope "+scope)
+		}
+	}
+
+	clientId, err := utils.GenerateRandomToken(oauthClientIdBytes)
+	if err != nil {
+		return "", err
+	}
+	client.ClientId = clientId
+
+	var secret string
+	if !public {
+		secret, err = utils.GenerateRandomToken(oauthClientSecretBytes)
+		if err != nil {
+			return "", err
+		}
+		client.SecretHash = utils.HashToken(secret)
+	}
+
+	err = o.clientRepo.CreateClient(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func (o *oauthService) ListClients(ctx context.Context) ([]*domain.OAuthClient, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.ListClients")
+	defer span.End()
+
+	return o.clientRepo.ListClients(ctx)
+}
+
+func (o *oauthService) DeleteClient(ctx context.Context, clientId string) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.DeleteClient")
+	defer span.End()
+
+	return o.clientRepo.DeleteClient(ctx, clientId)
+}
+
+// ValidateAuthorizationRequest returns the client once the redirect uri is trusted,
+// so a nil client with an error means the error must not be redirected.
+func (o *oauthService) ValidateAuthorizationRequest(ctx context.Context, req *domain.OAuthAuthorizationRequest) (*domain.OAuthClient, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.ValidateAuthorizationRequest")
+	defer span.End()
+
+	client, err := o.clientRepo.GetClient(ctx, req.ClientId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Exact match only, a client with a single uri may omit it
+	if req.RedirectURI == "" && len(client.RedirectURIs) == 1 {
+		req.RedirectURI = client.RedirectURIs[0]
+	}
+	if !slices.Contains(client.RedirectURIs, req.RedirectURI) {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_REQUEST, "redirect uri is not registered")
+	}
+
+	if req.ResponseType != "code" {
+		return client, domain.NewOAuthError(domain.OAUTH_ERR_UNSUPPORTED_RESPONSE_TYPE, "only the code response type is supported")
+	}
+
+	scopes := strings.Fields(req.Scope)
+	if len(scopes) == 0 {
+		return client, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_SCOPE, "scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(client.Scopes, scope) {
+			return client, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_SCOPE, "scope "+scope+" is not allowed")
+		}
+	}
+	req.Scope = normalizeScope(scopes)
+
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != pkceMethodS256 {
+		return client, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_REQUEST, "a S256 code challenge is required")
+	}
+
+	return client, nil
+}
+
+func (o *oauthService) HasConsent(ctx context.Context, accountId int, clientId, scope string) (bool, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.HasConsent")
+	defer span.End()
+
+	granted, err := o.grantRepo.GetConsentScope(ctx, accountId, clientId)
+	if err != nil {
+		return false, err
+	}
+
+	grantedScopes := strings.Fields(granted)
+	for _, s := range strings.Fields(scope) {
+		if !slices.Contains(grantedScopes, s) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (o *oauthService) Authorize(ctx context.Context, account *domain.Account, req *domain.OAuthAuthorizationRequest) (string, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.Authorize")
+	defer span.End()
+
+	_, err := o.ValidateAuthorizationRequest(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	// Remember the consent, keeping scopes granted earlier
+	granted, err := o.grantRepo.GetConsentScope(ctx, account.Id, req.ClientId)
+	if err != nil {
+		return "", err
+	}
+	err = o.grantRepo.SaveConsent(ctx, account.Id, req.ClientId, normalizeScope(append(strings.Fields(granted), strings.Fields(req.Scope)...)))
+	if err != nil {
+		return "", err
+	}
+
+	code, err := utils.GenerateRandomToken(oauthCodeBytes)
+	if err != nil {
+		return "", err
+	}
+
+	err = o.grantRepo.CreateAuthorizationCode(ctx, &domain.OAuthAuthorizationCode{
+		CodeHash:            utils.HashToken(code),
+		ClientId:            req.ClientId,
+		AccountId:           account.Id,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		ExpiresAt:           time.Now().Add(o.codeExpiry),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (o *oauthService) Exchange(ctx context.Context, req *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.Exchange")
+	defer span.End()
+
+	client, err := o.authenticateClient(ctx, req.ClientId, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case "authorization_code":
+		return o.exchangeCode(ctx, client, req)
+	case "refresh_token":
+		return o.exchangeRefreshToken(ctx, client, req)
+	default:
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_UNSUPPORTED_GRANT_TYPE, "")
+	}
+}
+
+func (o *oauthService) exchangeCode(ctx context.Context, client *domain.OAuthClient, req *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	code, err := o.grantRepo.GetAuthorizationCodeByHash(ctx, utils.HashToken(req.Code))
+	if err != nil {
+		return nil, err
+	}
+
+	if code.ClientId != client.ClientId || code.RedirectURI != req.RedirectURI {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "code was issued to another client or redirect uri")
+	}
+
+	// A replayed code may have leaked, revoke what it was exchanged for
+	if code.UsedAt != nil {
+		o.revokeGrant(ctx, code.ClientId, code.AccountId)
+		return nil, domain.ErrOAuthCodeUsed
+	}
+
+	if time.Now().After(code.ExpiresAt) {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "authorization code expired")
+	}
+
+	if !verifyPKCE(req.CodeVerifier, code.CodeChallenge) {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "code verifier does not match")
+	}
+
+	err = o.grantRepo.MarkAuthorizationCodeUsed(ctx, code.Id)
+	if errors.Is(err, domain.ErrOAuthCodeUsed) {
+		o.revokeGrant(ctx, code.ClientId, code.AccountId)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := o.accountService.GetAccountByID(ctx, code.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.issueTokens(ctx, client, account, code.Scope, code.Nonce)
+}
+
+func (o *oauthService) exchangeRefreshToken(ctx context.Context, client *domain.OAuthClient, req *domain.OAuthTokenRequest) (*domain.OAuthTokenResponse, error) {
+	stored, err := o.grantRepo.GetTokenByHash(ctx, utils.HashToken(req.RefreshToken))
+	if errors.Is(err, domain.ErrOAuthTokenNotFound) {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "refresh token not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Kind != domain.OAUTH_TOKEN_REFRESH || stored.ClientId != client.ClientId {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "refresh token not found")
+	}
+
+	// Refresh tokens rotate, presenting a revoked one means it was stolen or replayed
+	if stored.RevokedAt != nil {
+		o.revokeGrant(ctx, stored.ClientId, stored.AccountId)
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "refresh token revoked")
+	}
+	if !stored.IsActive(time.Now()) {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "refresh token expired")
+	}
+
+	// The scope can only be narrowed
+	scope := stored.Scope
+	if req.Scope != "" {
+		granted := strings.Fields(stored.Scope)
+		for _, s := range strings.Fields(req.Scope) {
+			if !slices.Contains(granted, s) {
+				return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_SCOPE, "scope "+s+" was not granted")
+			}
+		}
+		scope = normalizeScope(strings.Fields(req.Scope))
+	}
+
+	// A concurrent request may have rotated the token since it was read, only one of them wins
+	err = o.grantRepo.RevokeToken(ctx, stored.Id)
+	if errors.Is(err, domain.ErrOAuthTokenRevoked) {
+		o.revokeGrant(ctx, stored.ClientId, stored.AccountId)
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "refresh token revoked")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := o.accountService.GetAccountByID(ctx, stored.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.issueTokens(ctx, client, account, scope, "")
+}
+
+func (o *oauthService) issueTokens(
+	ctx context.Context,
+	client *domain.OAuthClient,
+	account *domain.Account,
+	scope string,
+	nonce string,
+) (*domain.OAuthTokenResponse, error) {
+	if account.IsDisabled() {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_GRANT, "the account is disabled")
+	}
+
+	scopes := strings.Fields(scope)
+
+	accessToken, err := o.createToken(ctx, domain.OAUTH_TOKEN_ACCESS, client.ClientId, account.Id, scope, o.accessExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &domain.OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(o.accessExpiry.Seconds()),
+		Scope:       scope,
+	}
+
+	if slices.Contains(scopes, domain.OAUTH_SCOPE_OFFLINE_ACCESS) {
+		resp.RefreshToken, err = o.createToken(ctx, domain.OAUTH_TOKEN_REFRESH, client.ClientId, account.Id, scope, o.refreshExpiry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if slices.Contains(scopes, domain.OAUTH_SCOPE_OPENID) {
+		resp.IdToken, err = o.generateIDToken(client, account, scopes, nonce, accessToken)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+func (o *oauthService) createToken(ctx context.Context, kind, clientId string, accountId int, scope string, expiry time.Duration) (string, error) {
+	token, err := utils.GenerateRandomToken(oauthTokenBytes)
+	if err != nil {
+		return "", err
+	}
+
+	err = o.grantRepo.CreateToken(ctx, &domain.OAuthToken{
+		TokenHash: utils.HashToken(token),
+		Kind:      kind,
+		ClientId:  clientId,
+		AccountId: accountId,
+		Scope:     scope,
+		ExpiresAt: time.Now().Add(expiry),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (o *oauthService) generateIDToken(
+	client *domain.OAuthClient,
+	account *domain.Account,
+	scopes []string,
+	nonce string,
+	accessToken string,
+) (string, error) {
+	now := time.Now()
+
+	claims := accountClaims(account, scopes)
+	claims["iss"] = o.issuer
+	claims["aud"] = client.ClientId
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(o.accessExpiry).Unix()
+	claims["at_hash"] = accessTokenHash(accessToken)
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	return o.tokenService.SignClaims(claims)
+}
+
+// accountClaims returns the standard claims of the account released for the scopes
+func accountClaims(account *domain.Account, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": strconv.Itoa(account.Id),
+	}
+
+	if slices.Contains(scopes, domain.OAUTH_SCOPE_EMAIL) {
+		claims["email"] = account.Email
+		claims["email_verified"] = account.IsEmailVerified()
+	}
+
+	if slices.Contains(scopes, domain.OAUTH_SCOPE_PROFILE) {
+		claims["preferred_username"] = account.Username
+		claims["updated_at"] = account.UpdatedAt.Unix()
+	}
+
+	return claims
+}
+
+func (o *oauthService) UserInfo(ctx context.Context, accessToken string) (map[string]interface{}, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.UserInfo")
+	defer span.End()
+
+	stored, err := o.grantRepo.GetTokenByHash(ctx, utils.HashToken(accessToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Kind != domain.OAUTH_TOKEN_ACCESS || !stored.IsActive(time.Now()) {
+		return nil, domain.ErrOAuthTokenNotFound
+	}
+
+	scopes := strings.Fields(stored.Scope)
+	if !slices.Contains(scopes, domain.OAUTH_SCOPE_OPENID) {
+		return nil, domain.NewOAuthError("insufficient_scope", "the openid scope is required")
+	}
+
+	account, err := o.accountService.GetAccountByID(ctx, stored.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountClaims(account, scopes), nil
+}
+
+func (o *oauthService) Introspect(ctx context.Context, clientId, clientSecret, token string) (*domain.OAuthIntrospection, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.Introspect")
+	defer span.End()
+
+	client, err := o.authenticateClient(ctx, clientId, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := o.grantRepo.GetTokenByHash(ctx, utils.HashToken(token))
+	if errors.Is(err, domain.ErrOAuthTokenNotFound) {
+		return &domain.OAuthIntrospection{Active: false}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Clients only learn about their own tokens
+	if stored.ClientId != client.ClientId || !stored.IsActive(time.Now()) {
+		return &domain.OAuthIntrospection{Active: false}, nil
+	}
+
+	return &domain.OAuthIntrospection{
+		Active:    true,
+		Scope:     stored.Scope,
+		ClientId:  stored.ClientId,
+		Sub:       strconv.Itoa(stored.AccountId),
+		TokenType: stored.Kind,
+		Exp:       stored.ExpiresAt.Unix(),
+		Iat:       stored.CreatedAt.Unix(),
+	}, nil
+}
+
+// Revoke invalidates a token of the client. Unknown tokens are ignored, RFC 7009.
+func (o *oauthService) Revoke(ctx context.Context, clientId, clientSecret, token string) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthService.Revoke")
+	defer span.End()
+
+	client, err := o.authenticateClient(ctx, clientId, clientSecret)
+	if err != nil {
+		return err
+	}
+
+	stored, err := o.grantRepo.GetTokenByHash(ctx, utils.HashToken(token))
+	if errors.Is(err, domain.ErrOAuthTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if stored.ClientId != client.ClientId {
+		return nil
+	}
+
+	// Revoking a refresh token ends the whole grant
+	if stored.Kind == domain.OAUTH_TOKEN_REFRESH {
+		return o.grantRepo.RevokeTokensByGrant(ctx, stored.ClientId, stored.AccountId)
+	}
+
+	err = o.grantRepo.RevokeToken(ctx, stored.Id)
+	if errors.Is(err, domain.ErrOAuthTokenRevoked) {
+		return nil
+	}
+	return err
+}
+
+func (o *oauthService) Discovery() map[string]interface{} {
+	return map[string]interface{}{
+		"issuer":                                o.issuer,
+		"authorization_endpoint":                o.issuer + "/oauth/authorize",
+		"token_endpoint":                        o.issuer + "/oauth/token",
+		"userinfo_endpoint":                     o.issuer + "/oauth/userinfo",
+		"introspection_endpoint":                o.issuer + "/oauth/introspect",
+		"revocation_endpoint":                   o.issuer + "/oauth/revoke",
+		"jwks_uri":                              o.issuer + "/.well-known/jwks.json",
+		"scopes_supported":                      supportedOAuthScopes,
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"ES512"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{pkceMethodS256},
+		"claims_supported": []string{
+			"sub", "iss", "aud", "exp", "iat", "nonce", "at_hash",
+			"email", "email_verified", "preferred_username", "updated_at",
+		},
+	}
+}
+
+func (o *oauthService) JWKS() []domain.JSONWebKey {
+	return o.tokenService.JWKS()
+}
+
+// authenticateClient checks the secret of confidential clients, public clients must not send one
+func (o *oauthService) authenticateClient(ctx context.Context, clientId, clientSecret string) (*domain.OAuthClient, error) {
+	client, err := o.clientRepo.GetClient(ctx, clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsPublic() {
+		if clientSecret != "" {
+			return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_CLIENT, "public clients have no secret")
+		}
+		return client, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashToken(clientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, domain.NewOAuthError(domain.OAUTH_ERR_INVALID_CLIENT, "client authentication failed")
+	}
+
+	return client, nil
+}
+
+func (o *oauthService) revokeGrant(ctx context.Context, clientId string, accountId int) {
+	o.logger.WarnContext(ctx, "oauth grant replay detected, revoking tokens", "clientId", clientId, "accountId", accountId)
+
+	err := o.grantRepo.RevokeTokensByGrant(ctx, clientId, accountId)
+	if err != nil {
+		o.logger.ErrorContext(ctx, "failed to revoke oauth grant", "error", err)
+	}
+}
+
+func verifyPKCE(verifier, challenge string) bool {
+	if len(verifier) < pkceVerifierMinLen || len(verifier) > pkceVerifierMaxLen {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// accessTokenHash is the at_hash claim, the left half of the hash matching the ES512 signature
+func accessTokenHash(accessToken string) string {
+	sum := sha512.Sum512([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
+
+// normalizeScope removes duplicates and sorts the scopes so stored values compare equal
+func normalizeScope(scopes []string) string {
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	return strings.Join(slices.Compact(scopes), " ")
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+)
+
+const (
+	oidcFlowExpiry     = 10 * time.Minute
+	oidcStateBytes     = 16
+	oidcPasswordBytes  = 32
+	oidcCallbackFormat = "/auth/oidc/%s/callback"
+)
+
+// oidcFlow is kept by the browser in an encrypted cookie between the redirect and the callback
+type oidcFlow struct {
+	Provider  string    `json:"provider"`
+	State     string    `json:"state"`
+	Nonce     string    `json:"nonce"`
+	Verifier  string    `json:"verifier"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type oidcIdentityClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// oidcProvider discovers the issuer on first use so an unreachable provider does not block startup
+type oidcProvider struct {
+	cfg         config.OIDCProviderConfig
+	redirectURL string
+
+	mu           sync.Mutex
+	oauth2Config *oauth2.Config
+	verifier     *oidc.IDTokenVerifier
+}
+
+func (p *oidcProvider) init(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth2Config != nil {
+		return nil
+	}
+
+	provider, err := oidc.NewProvider(ctx, p.cfg.Issuer)
+	if err != nil {
+		return err
+	}
+
+	scopes := []string{oidc.ScopeOpenID}
+	for _, scope := range p.cfg.Scopes {
+		if scope != oidc.ScopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	p.oauth2Config = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  p.redirectURL,
+		Scopes:       scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.cfg.ClientID})
+
+	return nil
+}
+
+type oidcService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	encryptionKey string
+	providers     map[string]*oidcProvider
+	providerNames []string
+
+	accountService domain.AccountService
+	identityRepo   domain.IdentityRepository
+	auditService   domain.AuditService
+}
+
+func NewOIDCService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	identityRepo domain.IdentityRepository,
+	auditService domain.AuditService,
+) domain.OIDCService {
+	logger := container.Logger.With("path", "oidcService")
+	baseURL := container.Cfg.Server.GetBaseURL()
+
+	providers := make(map[string]*oidcProvider)
+	var providerNames []string
+	for _, cfg := range container.Cfg.OIDC.Providers {
+		if cfg.Name == "" || cfg.Issuer == "" || cfg.ClientID == "" {
+			logger.Warn("skipping incomplete oidc provider", "name", cfg.Name)
+			continue
+		}
+
+		providers[cfg.Name] = &oidcProvider{
+			cfg:         cfg,
+			redirectURL: baseURL + fmt.Sprintf(oidcCallbackFormat, cfg.Name),
+		}
+		providerNames = append(providerNames, cfg.Name)
+	}
+
+	return &oidcService{
+		logger:         logger,
+		tracer:         container.Tracer,
+		encryptionKey:  container.Cfg.Encryption.Key,
+		providers:      providers,
+		providerNames:  providerNames,
+		accountService: accountService,
+		identityRepo:   identityRepo,
+		auditService:   auditService,
+	}
+}
+
+func (o *oidcService) Providers() []domain.OIDCProvider {
+	providers := make([]domain.OIDCProvider, 0, len(o.providerNames))
+	for _, name := range o.providerNames {
+		displayName := o.providers[name].cfg.DisplayName
+		if displayName == "" {
+			displayName = name
+		}
+		providers = append(providers, domain.OIDCProvider{
+			Name:        name,
+			DisplayName: displayName,
+		})
+	}
+	return providers
+}
+
+func (o *oidcService) getProvider(ctx context.Context, name string) (*oidcProvider, error) {
+	provider, ok := o.providers[name]
+	if !ok {
+		return nil, domain.ErrOIDCProviderNotFound
+	}
+
+	err := provider.init(ctx)
+	if err != nil {
+		o.logger.ErrorContext(ctx, "failed to discover oidc provider", "provider", name, "error", err)
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func (o *oidcService) BeginLogin(ctx context.Context, providerName string) (string, string, error) {
+	ctx, span := o.tracer.Start(ctx, "OIDCService.BeginLogin")
+	defer span.End()
+
+	provider, err := o.getProvider(ctx, providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	state, err := utils.GenerateRandomToken(oidcStateBytes)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := utils.GenerateRandomToken(oidcStateBytes)
+	if err != nil {
+		return "", "", err
+	}
+
+	flow := oidcFlow{
+		Provider:  providerName,
+		State:     state,
+		Nonce:     nonce,
+		Verifier:  oauth2.GenerateVerifier(),
+		ExpiresAt: time.Now().Add(oidcFlowExpiry),
+	}
+
+	flowJSON, err := json.Marshal(flow)
+	if err != nil {
+		return "", "", err
+	}
+
+	flowState, err := utils.Encrypt(o.encryptionKey, string(flowJSON))
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL := provider.oauth2Config.AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(flow.Verifier),
+	)
+
+	return authURL, flowState, nil
+}
+
+func (o *oidcService) CompleteLogin(ctx context.Context, providerName, state, code, flowState string) (*domain.Account, error) {
+	ctx, span := o.tracer.Start(ctx, "OIDCService.CompleteLogin")
+	defer span.End()
+
+	flow, err := o.decodeFlow(flowState)
+	if err != nil {
+		return nil, err
+	}
+
+	if flow.Provider != providerName || subtle.ConstantTimeCompare([]byte(flow.State), []byte(state)) != 1 {
+		return nil, domain.ErrOIDCInvalidState
+	}
+
+	provider, err := o.getProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := provider.oauth2Config.Exchange(ctx, code, oauth2.VerifierOption(flow.Verifier))
+	if err != nil {
+		o.logger.ErrorContext(ctx, "failed to exchange oidc code", "provider", providerName, "error", err)
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, domain.ErrInvalidToken
+	}
+
+	// Checks the signature against the provider JWKS along with issuer, audience and expiry
+	idToken, err := provider.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		o.logger.ErrorContext(ctx, "failed to verify id token", "provider", providerName, "error", err)
+		return nil, domain.ErrInvalidToken
+	}
+
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(flow.Nonce)) != 1 {
+		return nil, domain.ErrOIDCInvalidNonce
+	}
+
+	claims := oidcIdentityClaims{}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := o.linkAccount(ctx, provider.cfg, idToken.Subject, claims)
+	if err != nil {
+		return nil, err
+	}
+	if account.IsDisabled() {
+		return nil, domain.ErrAccountDisabled
+	}
+
+	o.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ACCOUNT_LOGIN,
+		ActorId:   account.Id,
+		SubjectId: account.Id,
+		Metadata:  map[string]interface{}{"provider": providerName},
+	})
+	return account, nil
+}
+
+func (o *oidcService) decodeFlow(flowState string) (*oidcFlow, error) {
+	flowJSON, err := utils.Decrypt(o.encryptionKey, flowState)
+	if err != nil {
+		return nil, domain.ErrOIDCInvalidState
+	}
+
+	flow := &oidcFlow{}
+	err = json.Unmarshal([]byte(flowJSON), flow)
+	if err != nil {
+		return nil, domain.ErrOIDCInvalidState
+	}
+
+	if time.Now().After(flow.ExpiresAt) {
+		return nil, domain.ErrOIDCInvalidState
+	}
+
+	return flow, nil
+}
+
+// linkAccount resolves the account of an identity. Unknown identities are linked to the
+// account with the same email, only when the provider verified that email and so did the account.
+func (o *oidcService) linkAccount(
+	ctx context.Context,
+	cfg config.OIDCProviderConfig,
+	subject string,
+	claims oidcIdentityClaims,
+) (*domain.Account, error) {
+	identity, err := o.identityRepo.GetIdentity(ctx, cfg.Name, subject)
+	if err == nil {
+		return o.accountService.GetAccountByID(ctx, identity.AccountId)
+	}
+	if !errors.Is(err, domain.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, domain.ErrOIDCEmailNotVerified
+	}
+
+	now := time.Now()
+	account, err := o.accountService.GetAccountByEmail(ctx, claims.Email)
+	switch {
+	case err == nil:
+		// Anyone can register an email they do not own and keep the password they chose,
+		// linking would hand them the account once the owner signs in with the provider.
+		// The owner logs in with the password, or resets it, and verifies the email first.
+		if !account.IsEmailVerified() {
+			return nil, domain.ErrOIDCAccountUnverified
+		}
+	case errors.Is(err, domain.ErrAccountNotFound):
+		if !cfg.AutoCreate {
+			return nil, domain.ErrOIDCRegistrationClosed
+		}
+
+		// The password is never shown, it can be set later through a password reset
+		password, err := utils.GenerateRandomToken(oidcPasswordBytes)
+		if err != nil {
+			return nil, err
+		}
+
+		account = &domain.Account{
+			Email:           claims.Email,
+			Password:        password,
+			Roles:           []string{domain.ROLE_USER},
+			EmailVerifiedAt: &now,
+		}
+		err = o.accountService.RegisterExternal(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	err = o.identityRepo.CreateIdentity(ctx, &domain.Identity{
+		AccountId: account.Id,
+		Provider:  cfg.Name,
+		Subject:   subject,
+		Email:     claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o.logger.InfoContext(ctx, "linked oidc identity", "provider", cfg.Name, "accountId", account.Id)
+	return account, nil
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/domain"
+	"gostarter/internals/storage/memory"
+	"gostarter/pkg/testUtils"
+	"gostarter/pkg/utils"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	testOIDCProvider      = "stub"
+	testOIDCClientID      = "gostarter"
+	testOIDCClientSecret  = "secret"
+	testOIDCEncryptionKey = "test-encryption-key"
+)
+
+// oidcTestAccountService serves the account lookups of the OIDC login from the memory store
+type oidcTestAccountService struct {
+	domain.AccountService
+	repo domain.AccountRepository
+}
+
+func (s *oidcTestAccountService) GetAccountByID(ctx context.Context, id int) (*domain.Account, error) {
+	return s.repo.GetAccountByID(ctx, id)
+}
+
+func (s *oidcTestAccountService) GetAccountByEmail(ctx context.Context, email string) (*domain.Account, error) {
+	return s.repo.GetAccountByEmail(ctx, email)
+}
+
+func (s *oidcTestAccountService) RegisterExternal(ctx context.Context, account *domain.Account) error {
+	return s.repo.CreateAccount(ctx, account)
+}
+
+type oidcTestIdentityRepository struct {
+	identities []*domain.Identity
+}
+
+func (r *oidcTestIdentityRepository) CreateIdentity(ctx context.Context, identity *domain.Identity) error {
+	identity.Id = len(r.identities) + 1
+	r.identities = append(r.identities, identity)
+	return nil
+}
+
+func (r *oidcTestIdentityRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.Identity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, domain.ErrIdentityNotFound
+}
+
+type oidcTestAuditService struct {
+	domain.AuditService
+}
+
+func (oidcTestAuditService) Record(ctx context.Context, event *domain.AuditEvent) {}
+
+type oidcTest struct {
+	server     *testUtils.StubOIDCServer
+	service    *oidcService
+	accounts   domain.AccountRepository
+	identities *oidcTestIdentityRepository
+}
+
+func newOIDCTest(t *testing.T, autoCreate bool) *oidcTest {
+	t.Helper()
+
+	server := testUtils.NewStubOIDCServer(testOIDCClientID, testOIDCClientSecret)
+	t.Cleanup(server.Close)
+
+	container := &infra.Container{
+		Cfg: &config.Config{
+			Server:     config.ServerConfig{BaseURL: "http://localhost:8080"},
+			Encryption: config.EncryptionConfig{Key: testOIDCEncryptionKey},
+			OIDC: config.OIDCConfig{
+				Providers: []config.OIDCProviderConfig{
+					{
+						Name:         testOIDCProvider,
+						Issuer:       server.URL,
+						ClientID:     testOIDCClientID,
+						ClientSecret: testOIDCClientSecret,
+						Scopes:       []string{"openid", "email"},
+						AutoCreate:   autoCreate,
+					},
+				},
+			},
+		},
+		Logger: testUtils.NewNoopLogger(),
+		Tracer: testUtils.NewNoopTracer(),
+	}
+
+	accounts := memory.NewAccountRepository(container)
+	identities := &oidcTestIdentityRepository{}
+
+	service := NewOIDCService(
+		container,
+		&oidcTestAccountService{repo: accounts},
+		identities,
+		oidcTestAuditService{},
+	).(*oidcService)
+
+	return &oidcTest{
+		server:     server,
+		service:    service,
+		accounts:   accounts,
+		identities: identities,
+	}
+}
+
+// authorize begins a login and lets the stub approve it, edit changes the authorization
+// request before it is sent. It returns the state and code of the callback and the flow state.
+func (o *oidcTest) authorize(t *testing.T, edit func(query url.Values)) (string, string, string) {
+	t.Helper()
+
+	authURL, flowState, err := o.service.BeginLogin(context.Background(), testOIDCProvider)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+
+	authorizeURL, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+	if edit != nil {
+		query := authorizeURL.Query()
+		edit(query)
+		authorizeURL.RawQuery = query.Encode()
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authorizeURL.String())
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize status = %d, want %d", resp.StatusCode, http.StatusFound)
+	}
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("parse callback: %v", err)
+	}
+
+	return callback.Query().Get("state"), callback.Query().Get("code"), flowState
+}
+
+func (o *oidcTest) completeLogin(t *testing.T, edit func(query url.Values)) (*domain.Account, error) {
+	t.Helper()
+
+	state, code, flowState := o.authorize(t, edit)
+	return o.service.CompleteLogin(context.Background(), testOIDCProvider, state, code, flowState)
+}
+
+func TestCompleteLoginStateMismatch(t *testing.T) {
+	o := newOIDCTest(t, true)
+
+	_, code, flowState := o.authorize(t, nil)
+
+	_, err := o.service.CompleteLogin(context.Background(), testOIDCProvider, "forged-state", code, flowState)
+	if !errors.Is(err, domain.ErrOIDCInvalidState) {
+		t.Fatalf("CompleteLogin error = %v, want %v", err, domain.ErrOIDCInvalidState)
+	}
+	if len(o.identities.identities) != 0 {
+		t.Fatalf("identities linked after a state mismatch")
+	}
+}
+
+func TestCompleteLoginNonceMismatch(t *testing.T) {
+	o := newOIDCTest(t, true)
+
+	_, err := o.completeLogin(t, func(query url.Values) {
+		query.Set("nonce", "replayed-nonce")
+	})
+	if !errors.Is(err, domain.ErrOIDCInvalidNonce) {
+		t.Fatalf("CompleteLogin error = %v, want %v", err, domain.ErrOIDCInvalidNonce)
+	}
+	if len(o.identities.identities) != 0 {
+		t.Fatalf("identities linked after a nonce mismatch")
+	}
+}
+
+func TestCompleteLoginPKCEVerifier(t *testing.T) {
+	o := newOIDCTest(t, true)
+
+	state, code, flowState := o.authorize(t, nil)
+
+	// The code was bound to the challenge of the flow verifier, another verifier is refused
+	flowJSON, err := utils.Decrypt(testOIDCEncryptionKey, flowState)
+	if err != nil {
+		t.Fatalf("decrypt flow: %v", err)
+	}
+	flow := oidcFlow{}
+	if err = json.Unmarshal([]byte(flowJSON), &flow); err != nil {
+		t.Fatalf("decode flow: %v", err)
+	}
+	flow.Verifier = oauth2.GenerateVerifier()
+	forgedJSON, err := json.Marshal(flow)
+	if err != nil {
+		t.Fatalf("encode flow: %v", err)
+	}
+	forgedState, err := utils.Encrypt(testOIDCEncryptionKey, string(forgedJSON))
+	if err != nil {
+		t.Fatalf("encrypt flow: %v", err)
+	}
+
+	_, err = o.service.CompleteLogin(context.Background(), testOIDCProvider, state, code, forgedState)
+	retrieveErr := &oauth2.RetrieveError{}
+	if !errors.As(err, &retrieveErr) || retrieveErr.ErrorCode != "invalid_grant" {
+		t.Fatalf("CompleteLogin error = %v, want an invalid_grant from the token endpoint", err)
+	}
+
+	// The verifier the challenge was made from completes the login
+	state, code, flowState = o.authorize(t, nil)
+	_, err = o.service.CompleteLogin(context.Background(), testOIDCProvider, state, code, flowState)
+	if err != nil {
+		t.Fatalf("CompleteLogin with the flow verifier: %v", err)
+	}
+}
+
+func TestCompleteLoginBadJWKSSignature(t *testing.T) {
+	o := newOIDCTest(t, true)
+	o.server.SignWithUnpublishedKey()
+
+	_, err := o.completeLogin(t, nil)
+	if !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("CompleteLogin error = %v, want %v", err, domain.ErrInvalidToken)
+	}
+	if len(o.identities.identities) != 0 {
+		t.Fatalf("identities linked from an ID token with a bad signature")
+	}
+}
+
+func TestCompleteLoginLinksVerifiedEmail(t *testing.T) {
+	o := newOIDCTest(t, false)
+	ctx := context.Background()
+
+	verifiedAt := time.Now()
+	existing := &domain.Account{
+		Email:           o.server.Email,
+		Password:        "hash",
+		Roles:           []string{domain.ROLE_USER},
+		EmailVerifiedAt: &verifiedAt,
+	}
+	if err := o.accounts.CreateAccount(ctx, existing); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	account, err := o.completeLogin(t, nil)
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if account.Id != existing.Id {
+		t.Fatalf("CompleteLogin account = %d, want the account %d with the email", account.Id, existing.Id)
+	}
+	if !account.IsEmailVerified() {
+		t.Fatalf("email of the linked account is not verified")
+	}
+
+	identity, err := o.identities.GetIdentity(ctx, testOIDCProvider, o.server.Subject)
+	if err != nil {
+		t.Fatalf("GetIdentity: %v", err)
+	}
+	if identity.AccountId != existing.Id {
+		t.Fatalf("identity account = %d, want %d", identity.AccountId, existing.Id)
+	}
+
+	// The linked identity logs in even after the provider stops verifying the email
+	o.server.EmailVerified = false
+	account, err = o.completeLogin(t, nil)
+	if err != nil || account.Id != existing.Id {
+		t.Fatalf("CompleteLogin of the linked identity = %v, %v", account, err)
+	}
+}
+
+func TestCompleteLoginUnverifiedEmailIsNotLinked(t *testing.T) {
+	o := newOIDCTest(t, true)
+	ctx := context.Background()
+
+	existing := &domain.Account{Email: o.server.Email, Password: "hash", Roles: []string{domain.ROLE_USER}}
+	if err := o.accounts.CreateAccount(ctx, existing); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+	o.server.EmailVerified = false
+
+	_, err := o.completeLogin(t, nil)
+	if !errors.Is(err, domain.ErrOIDCEmailNotVerified) {
+		t.Fatalf("CompleteLogin error = %v, want %v", err, domain.ErrOIDCEmailNotVerified)
+	}
+	if len(o.identities.identities) != 0 {
+		t.Fatalf("identity linked to an unverified email")
+	}
+}
+
+func TestCompleteLoginUnverifiedAccountIsNotLinked(t *testing.T) {
+	o := newOIDCTest(t, true)
+	ctx := context.Background()
+
+	// Registered by someone else with the email of the identity and never verified
+	squatter := &domain.Account{Email: o.server.Email, Password: "squatter-hash", Roles: []string{domain.ROLE_USER}}
+	if err := o.accounts.CreateAccount(ctx, squatter); err != nil {
+		t.Fatalf("CreateAccount: %v", err)
+	}
+
+	_, err := o.completeLogin(t, nil)
+	if !errors.Is(err, domain.ErrOIDCAccountUnverified) {
+		t.Fatalf("CompleteLogin error = %v, want %v", err, domain.ErrOIDCAccountUnverified)
+	}
+	if len(o.identities.identities) != 0 {
+		t.Fatalf("identity linked to an unverified account")
+	}
+
+	stored, err := o.accounts.GetAccountByID(ctx, squatter.Id)
+	if err != nil {
+		t.Fatalf("GetAccountByID: %v", err)
+	}
+	if stored.IsEmailVerified() {
+		t.Fatalf("email of the unverified account was verified by the provider login")
+	}
+}
+
+func TestCompleteLoginAutoCreatesAccount(t *testing.T) {
+	o := newOIDCTest(t, true)
+	ctx := context.Background()
+
+	account, err := o.completeLogin(t, nil)
+	if err != nil {
+		t.Fatalf("CompleteLogin: %v", err)
+	}
+	if account.Id == 0 || account.Email != o.server.Email {
+		t.Fatalf("CompleteLogin account = %+v, want a new account for %s", account, o.server.Email)
+	}
+	if !account.IsEmailVerified() || !slices.Contains(account.Roles, domain.ROLE_USER) {
+		t.Fatalf("created account = %+v, want a verified email and the user role", account)
+	}
+
+	stored, err := o.accounts.GetAccountByEmail(ctx, o.server.Email)
+	if err != nil || stored.Id != account.Id {
+		t.Fatalf("GetAccountByEmail = %v, %v, want the created account", stored, err)
+	}
+	if _, err = o.identities.GetIdentity(ctx, testOIDCProvider, o.server.Subject); err != nil {
+		t.Fatalf("GetIdentity: %v", err)
+	}
+}
+
+func TestCompleteLoginRegistrationClosed(t *testing.T) {
+	o := newOIDCTest(t, false)
+
+	_, err := o.completeLogin(t, nil)
+	if !errors.Is(err, domain.ErrOIDCRegistrationClosed) {
+		t.Fatalf("CompleteLogin error = %v, want %v", err, domain.ErrOIDCRegistrationClosed)
+	}
+}
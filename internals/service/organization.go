@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// organizationSlugAttempts bounds the random suffixes tried when a slug is taken
+const organizationSlugAttempts = 3
+
+type organizationService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accountService   domain.AccountService
+	organizationRepo domain.OrganizationRepository
+	auditService     domain.AuditService
+}
+
+func NewOrganizationService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	organizationRepo domain.OrganizationRepository,
+	auditService domain.AuditService,
+) domain.OrganizationService {
+	logger := container.Logger.With("path", "organizationService")
+	return &organizationService{
+		logger:           logger,
+		tracer:           container.Tracer,
+		accountService:   accountService,
+		organizationRepo: organizationRepo,
+		auditService:     auditService,
+	}
+}
+
+func (o *organizationService) CreateOrganization(ctx context.Context, account *domain.Account, name string) (*domain.Organization, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.CreateOrganization")
+	defer span.End()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.ErrOrganizationNameRequired
+	}
+
+	slug := utils.Slugify(name)
+	if slug == "" {
+		slug = "org"
+	}
+
+	organization := &domain.Organization{Name: name, Slug: slug}
+
+	// Names are not unique, a taken slug gets a random suffix
+	var err error
+	for attempt := 0; attempt < organizationSlugAttempts; attempt++ {
+		err = o.organizationRepo.CreateOrganization(ctx, organization, account.Id)
+		if !errors.Is(err, domain.ErrOrganizationExists) {
+			break
+		}
+
+		suffix := make([]byte, 3)
+		if _, err = rand.Read(suffix); err != nil {
+			return nil, err
+		}
+		organization.Slug = slug + "-" + hex.EncodeToString(suffix)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	o.auditService.Record(ctx, &domain.AuditEvent{
+		Action:         domain.AUDIT_ORGANIZATION_CREATE,
+		ActorId:        account.Id,
+		OrganizationId: organization.Id,
+		Metadata:       map[string]interface{}{"slug": organization.Slug},
+	})
+	return organization, nil
+}
+
+func (o *organizationService) GetOrganization(ctx context.Context, id int) (*domain.Organization, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.GetOrganization")
+	defer span.End()
+
+	return o.organizationRepo.GetOrganizationByID(ctx, id)
+}
+
+func (o *organizationService) ListOrganizations(ctx context.Context, accountId int) ([]*domain.Organization, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.ListOrganizations")
+	defer span.End()
+
+	return o.organizationRepo.ListOrganizationsByAccount(ctx, accountId)
+}
+
+func (o *organizationService) GetMembership(ctx context.Context, organizationId, accountId int) (*domain.OrganizationMember, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.GetMembership")
+	defer span.End()
+
+	return o.organizationRepo.GetMember(domain.WithTenant(ctx, organizationId), accountId)
+}
+
+func (o *organizationService) ListMembers(ctx context.Context, organizationId int) ([]*domain.OrganizationMember, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.ListMembers")
+	defer span.End()
+
+	return o.organizationRepo.ListMembers(domain.WithTenant(ctx, organizationId))
+}
+
+// authorizeRoleChange checks that the actor may hand out the role, or change a member holding it
+func authorizeRoleChange(actor *domain.OrganizationMember, role string) error {
+	if !actor.HasRole(domain.ORG_ROLE_ADMIN) {
+		return domain.ErrPermissionDenied
+	}
+	if role == domain.ORG_ROLE_OWNER && !actor.HasRole(domain.ORG_ROLE_OWNER) {
+		return domain.ErrPermissionDenied
+	}
+	return nil
+}
+
+func (o *organizationService) AddMember(
+	ctx context.Context,
+	actor *domain.OrganizationMember,
+	accountId int,
+	role string,
+) (*domain.OrganizationMember, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.AddMember")
+	defer span.End()
+
+	if !domain.ValidOrgRole(role) {
+		return nil, domain.ErrInvalidOrgRole
+	}
+
+	err := authorizeRoleChange(actor, role)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = o.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &domain.OrganizationMember{AccountId: accountId, Role: role}
+	err = o.organizationRepo.AddMember(domain.WithTenant(ctx, actor.OrganizationId), member)
+	if err != nil {
+		return nil, err
+	}
+
+	o.auditService.Record(ctx, &domain.AuditEvent{
+		Action:         domain.AUDIT_ORGANIZATION_MEMBER_ADD,
+		ActorId:        actor.AccountId,
+		SubjectId:      accountId,
+		OrganizationId: actor.OrganizationId,
+		Metadata:       map[string]interface{}{"role": role},
+	})
+	return member, nil
+}
+
+func (o *organizationService) UpdateMemberRole(
+	ctx context.Context,
+	actor *domain.OrganizationMember,
+	accountId int,
+	role string,
+) (*domain.OrganizationMember, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.UpdateMemberRole")
+	defer span.End()
+
+	if !domain.ValidOrgRole(role) {
+		return nil, domain.ErrInvalidOrgRole
+	}
+
+	ctx = domain.WithTenant(ctx, actor.OrganizationId)
+
+	member, err := o.organizationRepo.GetMember(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both the current and the new role must be within reach of the actor
+	for _, r := range []string{member.Role, role} {
+		if err = authorizeRoleChange(actor, r); err != nil {
+			return nil, err
+		}
+	}
+
+	err = o.organizationRepo.UpdateMemberRole(ctx, accountId, role)
+	if err != nil {
+		return nil, err
+	}
+
+	o.auditService.Record(ctx, &domain.AuditEvent{
+		Action:         domain.AUDIT_ORGANIZATION_MEMBER_UPDATE,
+		ActorId:        actor.AccountId,
+		SubjectId:      accountId,
+		OrganizationId: actor.OrganizationId,
+		Metadata:       map[string]interface{}{"from": member.Role, "role": role},
+	})
+
+	member.Role = role
+	return member, nil
+}
+
+func (o *organizationService) RemoveMember(ctx context.Context, actor *domain.OrganizationMember, accountId int) error {
+	ctx, span := o.tracer.Start(ctx, "OrganizationService.RemoveMember")
+	defer span.End()
+
+	ctx = domain.WithTenant(ctx, actor.OrganizationId)
+
+	member, err := o.organizationRepo.GetMember(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	err = authorizeRoleChange(actor, member.Role)
+	if err != nil {
+		return err
+	}
+
+	err = o.organizationRepo.RemoveMember(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	o.auditService.Record(ctx, &domain.AuditEvent{
+		Action:         domain.AUDIT_ORGANIZATION_MEMBER_REMOVE,
+		ActorId:        actor.AccountId,
+		SubjectId:      accountId,
+		OrganizationId: actor.OrganizationId,
+		Metadata:       map[string]interface{}{"role": member.Role},
+	})
+	return nil
+}
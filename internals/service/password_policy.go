@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gostarter/infra"
+	"gostarter/infra/config"
+	"gostarter/internals/domain"
+	"gostarter/pkg/breach"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adharshmk96/goutils/auth"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultPasswordMinLength = 8
+	defaultPasswordMaxLength = 128
+	// minIdentifierLength skips identifiers too short to be worth denying, like a one letter username
+	minIdentifierLength = 3
+)
+
+type passwordPolicyService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	cfg    config.PasswordConfig
+	corpus *breach.Corpus
+
+	historyRepo domain.PasswordHistoryRepository
+}
+
+func NewPasswordPolicyService(
+	container *infra.Container,
+	historyRepo domain.PasswordHistoryRepository,
+) domain.PasswordPolicyService {
+	logger := container.Logger.With("path", "passwordPolicyService")
+
+	cfg := container.Cfg.Password
+	if cfg.MinLength <= 0 {
+		cfg.MinLength = defaultPasswordMinLength
+	}
+	if cfg.MaxLength <= 0 {
+		cfg.MaxLength = defaultPasswordMaxLength
+	}
+
+	// A missing corpus disables the breach check rather than every password change
+	var corpus *breach.Corpus
+	if cfg.BreachCorpusPath != "" {
+		var err error
+		corpus, err = breach.Open(cfg.BreachCorpusPath)
+		if err != nil {
+			logger.Error("failed to open breached password corpus", "path", cfg.BreachCorpusPath, "error", err)
+		}
+	}
+
+	return &passwordPolicyService{
+		logger:      logger,
+		tracer:      container.Tracer,
+		cfg:         cfg,
+		corpus:      corpus,
+		historyRepo: historyRepo,
+	}
+}
+
+func (p *passwordPolicyService) Validate(ctx context.Context, account *domain.Account, password string) error {
+	ctx, span := p.tracer.Start(ctx, "PasswordPolicyService.Validate")
+	defer span.End()
+
+	violations := p.checkRules(account, password)
+
+	// Hashing is slow, the history is only compared once the cheap rules pass
+	if len(violations) == 0 && account.Id != 0 {
+		reused, err := p.isReused(ctx, account, password)
+		if err != nil {
+			return err
+		}
+		if reused {
+			violations = append(violations, domain.PasswordViolation{
+				Rule:    domain.PASSWORD_RULE_REUSE,
+				Message: fmt.Sprintf("password must differ from the last %d passwords", p.cfg.HistoryCount),
+			})
+		}
+	}
+
+	if p.corpus != nil {
+		breached, err := p.corpus.Contains(password)
+		if err != nil {
+			p.logger.ErrorContext(ctx, "failed to check breached password corpus", "error", err)
+		}
+		if breached {
+			violations = append(violations, domain.PasswordViolation{
+				Rule:    domain.PASSWORD_RULE_BREACHED,
+				Message: "password appears in a known data breach",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &domain.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func (p *passwordPolicyService) checkRules(account *domain.Account, password string) []domain.PasswordViolation {
+	var violations []domain.PasswordViolation
+	add := func(rule, message string) {
+		violations = append(violations, domain.PasswordViolation{Rule: rule, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.cfg.MinLength {
+		add(domain.PASSWORD_RULE_MIN_LENGTH, fmt.Sprintf("password must be at least %d characters", p.cfg.MinLength))
+	}
+	if length > p.cfg.MaxLength {
+		add(domain.PASSWORD_RULE_MAX_LENGTH, fmt.Sprintf("password must be at most %d characters", p.cfg.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	if p.cfg.RequireUpper && !upper {
+		add(domain.PASSWORD_RULE_UPPERCASE, "password must contain an uppercase letter")
+	}
+	if p.cfg.RequireLower && !lower {
+		add(domain.PASSWORD_RULE_LOWERCASE, "password must contain a lowercase letter")
+	}
+	if p.cfg.RequireDigit && !digit {
+		add(domain.PASSWORD_RULE_DIGIT, "password must contain a digit")
+	}
+	if p.cfg.RequireSymbol && !symbol {
+		add(domain.PASSWORD_RULE_SYMBOL, "password must contain a symbol")
+	}
+
+	if p.cfg.DenyIdentifiers && containsIdentifier(password, account) {
+		add(domain.PASSWORD_RULE_IDENTIFIER, "password must not contain your email or username")
+	}
+
+	return violations
+}
+
+// containsIdentifier reports whether the password holds the email, its local part or the username
+func containsIdentifier(password string, account *domain.Account) bool {
+	lowered := strings.ToLower(password)
+
+	identifiers := []string{account.Email, account.Username}
+	if local, _, ok := strings.Cut(account.Email, "@"); ok {
+		identifiers = append(identifiers, local)
+	}
+
+	for _, identifier := range identifiers {
+		identifier = strings.ToLower(strings.TrimSpace(identifier))
+		if utf8.RuneCountInString(identifier) >= minIdentifierLength && strings.Contains(lowered, identifier) {
+			return true
+		}
+	}
+	return false
+}
+
+// isReused compares the password with the current hash and the remembered ones
+func (p *passwordPolicyService) isReused(ctx context.Context, account *domain.Account, password string) (bool, error) {
+	if p.cfg.HistoryCount <= 0 {
+		return false, nil
+	}
+
+	hashes, err := p.historyRepo.ListPasswordHistory(ctx, account.Id, p.cfg.HistoryCount)
+	if err != nil {
+		return false, err
+	}
+
+	if account.Password != "" && !slices.Contains(hashes, account.Password) {
+		hashes = append(hashes, account.Password)
+	}
+
+	for _, hash := range hashes {
+		match, err := auth.VerifyPasswordHash(password, hash)
+		if err != nil {
+			continue
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *passwordPolicyService) Remember(ctx context.Context, accountId int, passwordHash string) error {
+	ctx, span := p.tracer.Start(ctx, "PasswordPolicyService.Remember")
+	defer span.End()
+
+	if p.cfg.HistoryCount <= 0 {
+		return nil
+	}
+
+	err := p.historyRepo.AddPasswordHistory(ctx, accountId, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	return p.historyRepo.PrunePasswordHistory(ctx, accountId, p.cfg.HistoryCount)
+}
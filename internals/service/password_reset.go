@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gostarter/infra"
+	"gostarter/infra/mailer"
+	"gostarter/internals/domain"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultPasswordResetExpiry = 30 * time.Minute
+
+type passwordResetService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+	mailer mailer.Mailer
+
+	baseURL string
+	expiry  time.Duration
+
+	accountService domain.AccountService
+	tokenService   domain.TokenService
+	tokens         *accountTokens
+}
+
+func NewPasswordResetService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	tokenService domain.TokenService,
+	accountTokenRepo domain.AccountTokenRepository,
+) domain.PasswordResetService {
+	expiry := time.Minute * time.Duration(container.Cfg.Auth.PasswordResetExpirationMinutes)
+	if expiry <= 0 {
+		expiry = defaultPasswordResetExpiry
+	}
+
+	logger := container.Logger.With("path", "passwordResetService")
+	return &passwordResetService{
+		logger:         logger,
+		tracer:         container.Tracer,
+		mailer:         container.Mailer,
+		baseURL:        container.Cfg.Server.GetBaseURL(),
+		expiry:         expiry,
+		accountService: accountService,
+		tokenService:   tokenService,
+		tokens:         newAccountTokens(container.Cfg.Encryption.Key, accountTokenRepo),
+	}
+}
+
+// RequestPasswordReset looks up the account, issues the token and sends the mail in the
+// background, so the response takes the same time whether the email is registered or not
+func (p *passwordResetService) RequestPasswordReset(ctx context.Context, email string) error {
+	_, span := p.tracer.Start(ctx, "PasswordResetService.RequestPasswordReset")
+	defer span.End()
+
+	go p.sendPasswordReset(context.WithoutCancel(ctx), email)
+
+	return nil
+}
+
+func (p *passwordResetService) sendPasswordReset(ctx context.Context, email string) {
+	ctx, span := p.tracer.Start(ctx, "PasswordResetService.sendPasswordReset")
+	defer span.End()
+
+	account, err := p.accountService.GetAccountByEmail(ctx, email)
+	if errors.Is(err, domain.ErrAccountNotFound) {
+		return
+	}
+	if err != nil {
+		p.logger.ErrorContext(ctx, "failed to look up password reset account", "error", err)
+		return
+	}
+
+	token, err := p.tokens.issue(ctx, account.Id, domain.TOKEN_PURPOSE_PASSWORD_RESET, p.expiry)
+	if err != nil {
+		p.logger.ErrorContext(ctx, "failed to issue password reset token", "accountId", account.Id, "error", err)
+		return
+	}
+
+	link := p.baseURL + "/reset-password?token=" + url.QueryEscape(token)
+
+	err = p.mailer.Send(ctx, mailer.Message{
+		To:      []string{account.Email},
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Someone requested a password reset for your account. Open the link below to choose a new password.\n\n%s\n\n"+
+				"The link expires in %s. If you did not request this, you can ignore this email.",
+			link, p.expiry,
+		),
+	})
+	if err != nil {
+		p.logger.ErrorContext(ctx, "failed to send password reset mail", "accountId", account.Id, "error", err)
+	}
+}
+
+// ResetPassword sets the new password and ends every existing session of the account
+func (p *passwordResetService) ResetPassword(ctx context.Context, token, password string) error {
+	ctx, span := p.tracer.Start(ctx, "PasswordResetService.ResetPassword")
+	defer span.End()
+
+	stored, err := p.tokens.lookup(ctx, domain.TOKEN_PURPOSE_PASSWORD_RESET, token)
+	if err != nil {
+		return err
+	}
+
+	account, err := p.accountService.GetAccountByID(ctx, stored.AccountId)
+	if err != nil {
+		return err
+	}
+
+	// A password rejected by the policy leaves the token usable for another try
+	err = p.accountService.ValidatePassword(ctx, account, password)
+	if err != nil {
+		return err
+	}
+
+	err = p.tokens.markUsed(ctx, stored)
+	if err != nil {
+		return err
+	}
+
+	err = p.accountService.SetPassword(ctx, account, password)
+	if err != nil {
+		return err
+	}
+
+	return p.tokenService.RevokeAllSessions(ctx, account.Id)
+}
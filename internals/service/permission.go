@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultPermissionCacheTTL = time.Minute
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+type permissionService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	ttl   time.Duration
+	mu    sync.RWMutex
+	cache map[string]cachedPermissions
+
+	permissionRepo domain.PermissionRepository
+}
+
+func NewPermissionService(
+	container *infra.Container,
+	permissionRepo domain.PermissionRepository,
+) domain.PermissionService {
+	ttl := time.Second * time.Duration(container.Cfg.Auth.PermissionCacheSeconds)
+	if ttl <= 0 {
+		ttl = defaultPermissionCacheTTL
+	}
+
+	logger := container.Logger.With("path", "permissionService")
+	return &permissionService{
+		logger:         logger,
+		tracer:         container.Tracer,
+		ttl:            ttl,
+		cache:          make(map[string]cachedPermissions),
+		permissionRepo: permissionRepo,
+	}
+}
+
+func (p *permissionService) ResolvePermissions(ctx context.Context, roles []string) ([]string, error) {
+	ctx, span := p.tracer.Start(ctx, "PermissionService.ResolvePermissions")
+	defer span.End()
+
+	now := time.Now()
+	var permissions, missing []string
+
+	p.mu.RLock()
+	for _, role := range roles {
+		cached, ok := p.cache[role]
+		if ok && now.Before(cached.expiresAt) {
+			permissions = append(permissions, cached.permissions...)
+			continue
+		}
+		missing = append(missing, role)
+	}
+	p.mu.RUnlock()
+
+	if len(missing) > 0 {
+		resolved, err := p.permissionRepo.GetRolePermissions(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		p.mu.Lock()
+		for _, role := range missing {
+			// Roles without grants are cached too, so they do not query on every request
+			p.cache[role] = cachedPermissions{permissions: resolved[role], expiresAt: now.Add(p.ttl)}
+			permissions = append(permissions, resolved[role]...)
+		}
+		p.mu.Unlock()
+	}
+
+	slices.Sort(permissions)
+	return slices.Compact(permissions), nil
+}
+
+func (p *permissionService) HasPermission(ctx context.Context, account *domain.Account, permission string) (bool, error) {
+	ctx, span := p.tracer.Start(ctx, "PermissionService.HasPermission")
+	defer span.End()
+
+	granted, err := p.ResolvePermissions(ctx, account.EffectiveRoles())
+	if err != nil {
+		return false, err
+	}
+
+	return slices.ContainsFunc(granted, func(g string) bool {
+		return domain.PermissionMatches(g, permission)
+	}), nil
+}
+
+func (p *permissionService) InvalidateCache() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clear(p.cache)
+}
+
+func (p *permissionService) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	ctx, span := p.tracer.Start(ctx, "PermissionService.ListPermissions")
+	defer span.End()
+
+	return p.permissionRepo.ListPermissions(ctx)
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type roleService struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	accountService    domain.AccountService
+	tokenService      domain.TokenService
+	permissionService domain.PermissionService
+	roleRepo          domain.RoleRepository
+	auditService      domain.AuditService
+}
+
+func NewRoleService(
+	container *infra.Container,
+	accountService domain.AccountService,
+	tokenService domain.TokenService,
+	permissionService domain.PermissionService,
+	roleRepo domain.RoleRepository,
+	auditService domain.AuditService,
+) domain.RoleService {
+	logger := container.Logger.With("path", "roleService")
+	return &roleService{
+		logger:            logger,
+		tracer:            container.Tracer,
+		accountService:    accountService,
+		tokenService:      tokenService,
+		permissionService: permissionService,
+		roleRepo:          roleRepo,
+		auditService:      auditService,
+	}
+}
+
+func (r *roleService) CreateRole(ctx context.Context, actorId int, name string, permissions []string) (*domain.Role, error) {
+	ctx, span := r.tracer.Start(ctx, "RoleService.CreateRole")
+	defer span.End()
+
+	name = strings.TrimSpace(name)
+	if !domain.ValidRoleName(name) {
+		return nil, domain.ErrInvalidRoleName
+	}
+
+	permissions = slices.Clone(permissions)
+	slices.Sort(permissions)
+
+	role := &domain.Role{
+		Name:        name,
+		Permissions: slices.Compact(permissions),
+	}
+
+	err := r.roleRepo.CreateRole(ctx, role)
+	if err != nil {
+		return nil, err
+	}
+
+	r.auditService.Record(ctx, &domain.AuditEvent{
+		Action:   domain.AUDIT_ROLE_CREATE,
+		ActorId:  actorId,
+		Metadata: map[string]interface{}{"role": role.Name, "permissions": role.Permissions},
+	})
+	return role, nil
+}
+
+func (r *roleService) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	ctx, span := r.tracer.Start(ctx, "RoleService.ListRoles")
+	defer span.End()
+
+	return r.roleRepo.ListRoles(ctx)
+}
+
+func (r *roleService) GrantPermission(ctx context.Context, actorId int, role, permission string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleService.GrantPermission")
+	defer span.End()
+
+	_, err := r.roleRepo.GetRoleByName(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	err = r.roleRepo.GrantPermission(ctx, role, permission)
+	if err != nil {
+		return err
+	}
+
+	// Permissions are resolved per request, dropping the cache applies the grant to every session
+	r.permissionService.InvalidateCache()
+
+	r.auditService.Record(ctx, &domain.AuditEvent{
+		Action:   domain.AUDIT_ROLE_PERMISSION_GRANT,
+		ActorId:  actorId,
+		Metadata: map[string]interface{}{"role": role, "permission": permission},
+	})
+	return nil
+}
+
+func (r *roleService) RevokePermission(ctx context.Context, actorId int, role, permission string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleService.RevokePermission")
+	defer span.End()
+
+	_, err := r.roleRepo.GetRoleByName(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	err = r.roleRepo.RevokePermission(ctx, role, permission)
+	if err != nil {
+		return err
+	}
+
+	r.permissionService.InvalidateCache()
+
+	r.auditService.Record(ctx, &domain.AuditEvent{
+		Action:   domain.AUDIT_ROLE_PERMISSION_REVOKE,
+		ActorId:  actorId,
+		Metadata: map[string]interface{}{"role": role, "permission": permission},
+	})
+	return nil
+}
+
+func (r *roleService) AssignRole(ctx context.Context, actorId, accountId int, role string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleService.AssignRole")
+	defer span.End()
+
+	_, err := r.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	err = r.roleRepo.AssignRole(ctx, accountId, role)
+	if err != nil {
+		return err
+	}
+
+	r.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ROLE_ASSIGN,
+		ActorId:   actorId,
+		SubjectId: accountId,
+		Metadata:  map[string]interface{}{"role": role},
+	})
+	return r.refreshSessions(ctx, accountId)
+}
+
+func (r *roleService) UnassignRole(ctx context.Context, actorId, accountId int, role string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleService.UnassignRole")
+	defer span.End()
+
+	_, err := r.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.roleRepo.GetRoleByName(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	err = r.roleRepo.UnassignRole(ctx, accountId, role)
+	if err != nil {
+		return err
+	}
+
+	r.auditService.Record(ctx, &domain.AuditEvent{
+		Action:    domain.AUDIT_ROLE_UNASSIGN,
+		ActorId:   actorId,
+		SubjectId: accountId,
+		Metadata:  map[string]interface{}{"role": role},
+	})
+	return r.refreshSessions(ctx, accountId)
+}
+
+func (r *roleService) SetRoles(ctx context.Context, actorId, accountId int, roles []string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleService.SetRoles")
+	defer span.End()
+
+	account, err := r.accountService.GetAccountByID(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		_, err = r.roleRepo.GetRoleByName(ctx, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	changed := false
+	for _, role := range roles {
+		if slices.Contains(account.Roles, role) {
+			continue
+		}
+
+		err = r.roleRepo.AssignRole(ctx, accountId, role)
+		if err != nil && !errors.Is(err, domain.ErrRoleAlreadyAssigned) {
+			return err
+		}
+		changed = true
+
+		r.auditService.Record(ctx, &domain.AuditEvent{
+			Action:    domain.AUDIT_ROLE_ASSIGN,
+			ActorId:   actorId,
+			SubjectId: accountId,
+			Metadata:  map[string]interface{}{"role": role},
+		})
+	}
+
+	for _, role := range account.Roles {
+		if slices.Contains(roles, role) {
+			continue
+		}
+
+		err = r.roleRepo.UnassignRole(ctx, accountId, role)
+		if err != nil && !errors.Is(err, domain.ErrRoleNotAssigned) {
+			return err
+		}
+		changed = true
+
+		r.auditService.Record(ctx, &domain.AuditEvent{
+			Action:    domain.AUDIT_ROLE_UNASSIGN,
+			ActorId:   actorId,
+			SubjectId: accountId,
+			Metadata:  map[string]interface{}{"role": role},
+		})
+	}
+
+	if !changed {
+		return nil
+	}
+	return r.refreshSessions(ctx, accountId)
+}
+
+// refreshSessions makes the sessions of the account drop the roles carried in their access tokens
+func (r *roleService) refreshSessions(ctx context.Context, accountId int) error {
+	err := r.tokenService.RevokeAccessTokens(ctx, accountId)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "failed to revoke access tokens after role change", "error", err, "accountId", accountId)
+	}
+	return err
+}
@@ -1,32 +1,105 @@
 package service
 
 import (
-	"github.com/adharshmk96/goutils/token"
+	"context"
+	"crypto/ecdsa"
+	"errors"
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel/trace"
+	"gostarter/infra"
 	"gostarter/infra/config"
 	"gostarter/internals/domain"
+	"gostarter/pkg/keyring"
 	"gostarter/pkg/utils"
+	"log/slog"
+	"math"
+	"sync/atomic"
 	"time"
 )
 
+const (
+	refreshTokenBytes = 32
+	tokenFamilyBytes  = 16
+	jtiBytes          = 16
+
+	defaultMFAPendingExpiry = 5 * time.Minute
+)
+
 type tokenService struct {
-	jwtUtil     *token.JWTUtil
-	tokenExpiry int
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	jwtConfig     config.JWTConfig
+	keys          atomic.Pointer[keyring.Keyring]
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+	pendingExpiry time.Duration
+
+	refreshTokenRepo    domain.RefreshTokenRepository
+	tokenRevocationRepo domain.TokenRevocationRepository
+}
+
+func (a *tokenService) GenerateJWT(account *domain.Account) (string, error) {
+	claims, err := a.accessClaims(account)
+	if err != nil {
+		return "", err
+	}
+
+	return a.signJWT(claims)
+}
+
+// GenerateImpersonationJWT follows the act claim of RFC 8693, the token identifies the subject and
+// names the actor. The second factor is the one of the actor session.
+func (a *tokenService) GenerateImpersonationJWT(actor, subject *domain.Account) (string, error) {
+	claims, err := a.accessClaims(subject)
+	if err != nil {
+		return "", err
+	}
+
+	claims["act"] = map[string]interface{}{
+		"userId": actor.Id,
+		"email":  actor.Email,
+	}
+
+	delete(claims, "mfa")
+	if actor.MFAVerified {
+		claims["mfa"] = true
+	}
+
+	return a.signJWT(claims)
 }
 
-func (a *tokenService) GenerateJWT(id int, email string, roles []string) (string, error) {
+// accessClaims returns the claims of an access token identifying the account
+func (a *tokenService) accessClaims(account *domain.Account) (jwt.MapClaims, error) {
+	jti, err := utils.GenerateRandomToken(jtiBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"userId": id,
-		"email":  email,
-		"roles":  roles,
-		"exp":    time.Now().Add(time.Hour * time.Duration(a.tokenExpiry)).Unix(),
+		"jti":    jti,
+		"typ":    domain.TOKEN_TYPE_ACCESS,
+		"userId": account.Id,
+		"email":  account.Email,
+		"roles":  account.Roles,
+		"iat":    issuedAtClaim(now),
+		"exp":    now.Add(a.accessExpiry).Unix(),
+	}
+
+	if account.IsEmailVerified() {
+		claims["emailVerifiedAt"] = account.EmailVerifiedAt.Unix()
+	}
+
+	if account.MFAVerified {
+		claims["mfa"] = true
 	}
 
-	return a.jwtUtil.EncodeJWT(claims)
+	return claims, nil
 }
 
 func (a *tokenService) VerifyJWT(userJWT string) (bool, error) {
-	_, err := a.jwtUtil.DecodeJWT(userJWT)
+	_, err := a.decodeJWT(userJWT)
 	if err != nil {
 		return false, err
 	}
@@ -49,11 +122,16 @@ func getList[T any](key string, decodedJwt *jwt.Token) ([]T, bool) {
 }
 
 func (a *tokenService) ExtractAccount(userJWT string) (*domain.Account, error) {
-	decodedJwt, err := a.jwtUtil.DecodeJWT(userJWT)
+	decodedJwt, err := a.decodeJWT(userJWT)
 	if err != nil {
 		return nil, err
 	}
 
+	// Only access tokens identify a session, tokens issued before the typ claim count as access tokens
+	if typ, ok := decodedJwt.Claims.(jwt.MapClaims)["typ"].(string); ok && typ != domain.TOKEN_TYPE_ACCESS {
+		return nil, domain.ErrInvalidToken
+	}
+
 	userIdFloat, ok := decodedJwt.Claims.(jwt.MapClaims)["userId"].(float64)
 	userId := int(userIdFloat)
 	if !ok {
@@ -75,23 +153,435 @@ func (a *tokenService) ExtractAccount(userJWT string) (*domain.Account, error) {
 		Roles:    roles,
 	}
 
+	if verifiedAt, ok := decodedJwt.Claims.(jwt.MapClaims)["emailVerifiedAt"].(float64); ok {
+		emailVerifiedAt := time.Unix(int64(verifiedAt), 0)
+		userAccount.EmailVerifiedAt = &emailVerifiedAt
+	}
+
+	userAccount.MFAVerified, _ = decodedJwt.Claims.(jwt.MapClaims)["mfa"].(bool)
+
+	if act, ok := decodedJwt.Claims.(jwt.MapClaims)["act"].(map[string]interface{}); ok {
+		actorId, ok := act["userId"].(float64)
+		if !ok {
+			return nil, domain.ErrInvalidToken
+		}
+		actorEmail, _ := act["email"].(string)
+
+		userAccount.Impersonator = &domain.Account{
+			Id:       int(actorId),
+			Username: actorEmail,
+			Email:    actorEmail,
+		}
+	}
+
 	return userAccount, nil
 }
 
-func NewTokenService(cfg config.JWTConfig) domain.TokenService {
+func (a *tokenService) GenerateMFAPendingToken(account *domain.Account) (string, error) {
+	jti, err := utils.GenerateRandomToken(jtiBytes)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"jti":    jti,
+		"typ":    domain.TOKEN_TYPE_MFA_PENDING,
+		"userId": account.Id,
+		"iat":    issuedAtClaim(now),
+		"exp":    now.Add(a.pendingExpiry).Unix(),
+	}
+
+	return a.signJWT(claims)
+}
+
+// ValidateMFAPendingToken checks the token type and the denylist, callers revoke the
+// token with RevokeJWT once the second factor succeeds so it cannot be replayed.
+func (a *tokenService) ValidateMFAPendingToken(ctx context.Context, pendingToken string) (int, error) {
+	ctx, span := a.tracer.Start(ctx, "TokenService.ValidateMFAPendingToken")
+	defer span.End()
 
-	privateKey, publicKey, err := utils.LoadECDSAKeyPair(cfg.PrivateKeyPath, cfg.PublicKeyPath)
+	decodedJwt, err := a.decodeJWT(pendingToken)
 	if err != nil {
-		panic(err)
+		return 0, domain.ErrInvalidToken
+	}
+
+	claims := decodedJwt.Claims.(jwt.MapClaims)
+	if typ, _ := claims["typ"].(string); typ != domain.TOKEN_TYPE_MFA_PENDING {
+		return 0, domain.ErrInvalidToken
+	}
+	userIdFloat, ok := claims["userId"].(float64)
+	if !ok {
+		return 0, domain.ErrInvalidToken
+	}
+
+	revoked, err := a.IsRevoked(ctx, pendingToken)
+	if err != nil {
+		return 0, err
+	}
+	if revoked {
+		return 0, domain.ErrInvalidToken
+	}
+
+	return int(userIdFloat), nil
+}
+
+func (a *tokenService) SignClaims(claims map[string]interface{}) (string, error) {
+	return a.signJWT(jwt.MapClaims(claims))
+}
+
+// signJWT signs with the active key of the keyring, the kid header names the key
+func (a *tokenService) signJWT(claims jwt.MapClaims) (string, error) {
+	active := a.keys.Load().Active()
+
+	signed := jwt.NewWithClaims(jwt.SigningMethodES512, claims)
+	signed.Header["kid"] = active.Kid
+
+	return signed.SignedString(active.PrivateKey)
+}
+
+// decodeJWT verifies the token with the keyring key named by its kid header.
+// Tokens signed before key ids were introduced are verified with the active key.
+func (a *tokenService) decodeJWT(userJWT string) (*jwt.Token, error) {
+	keys := a.keys.Load()
+
+	return jwt.Parse(userJWT, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return keys.Active().PublicKey, nil
+		}
+
+		key, found := keys.Get(kid)
+		if !found {
+			return nil, domain.ErrUnknownSigningKey
+		}
+		return key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES512.Alg()}))
+}
+
+func (a *tokenService) JWKS() []domain.JSONWebKey {
+	var jwks []domain.JSONWebKey
+	for _, key := range a.keys.Load().Keys() {
+		jwk, err := newJSONWebKey(key.Kid, key.PublicKey)
+		if err != nil {
+			a.logger.Error("failed to encode signing key", "error", err, "kid", key.Kid)
+			continue
+		}
+		jwks = append(jwks, jwk)
+	}
+
+	return jwks
+}
+
+// ReloadKeys reads the keyring again, so rotated keys are used without a restart.
+// The current keys stay in use if the keyring cannot be read.
+func (a *tokenService) ReloadKeys(ctx context.Context) error {
+	_, span := a.tracer.Start(ctx, "TokenService.ReloadKeys")
+	defer span.End()
+
+	keys, err := loadKeyring(a.jwtConfig)
+	if err != nil {
+		return err
+	}
+
+	previous := a.keys.Swap(keys)
+	if previous.Active().Kid != keys.Active().Kid {
+		a.logger.InfoContext(ctx, "signing key rotated", "kid", keys.Active().Kid, "previousKid", previous.Active().Kid)
+	}
+
+	return nil
+}
+
+// GenerateRefreshToken starts a new refresh token family for the account
+func (a *tokenService) GenerateRefreshToken(ctx context.Context, accountId int) (string, error) {
+	ctx, span := a.tracer.Start(ctx, "TokenService.GenerateRefreshToken")
+	defer span.End()
+
+	familyId, err := utils.GenerateRandomToken(tokenFamilyBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return a.issueRefreshToken(ctx, accountId, familyId)
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one in the same family.
+// Presenting a token that was already rotated revokes the whole family.
+func (a *tokenService) RotateRefreshToken(ctx context.Context, refreshToken string) (int, string, error) {
+	ctx, span := a.tracer.Start(ctx, "TokenService.RotateRefreshToken")
+	defer span.End()
+
+	stored, err := a.refreshTokenRepo.GetRefreshTokenByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return 0, "", err
+	}
+
+	if stored.RevokedAt != nil {
+		a.revokeFamily(ctx, stored)
+		return 0, "", domain.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return 0, "", domain.ErrRefreshTokenExpired
+	}
+
+	err = a.refreshTokenRepo.RevokeRefreshToken(ctx, stored.Id)
+	if err == domain.ErrRefreshTokenReused {
+		a.revokeFamily(ctx, stored)
+		return 0, "", err
+	}
+	if err != nil {
+		return 0, "", err
+	}
+
+	newToken, err := a.issueRefreshToken(ctx, stored.AccountId, stored.FamilyId)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return stored.AccountId, newToken, nil
+}
+
+func (a *tokenService) issueRefreshToken(ctx context.Context, accountId int, familyId string) (string, error) {
+	refreshToken, err := utils.GenerateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return "", err
+	}
+
+	err = a.refreshTokenRepo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		AccountId: accountId,
+		FamilyId:  familyId,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(a.refreshExpiry),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+func (a *tokenService) revokeFamily(ctx context.Context, stored *domain.RefreshToken) {
+	a.logger.WarnContext(ctx, "refresh token reuse detected, revoking family",
+		"accountId", stored.AccountId,
+		"familyId", stored.FamilyId,
+	)
+
+	err := a.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyId)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "failed to revoke refresh token family", "error", err)
+	}
+}
+
+// RevokeRefreshToken revokes the family of the given refresh token, ending that session
+func (a *tokenService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	ctx, span := a.tracer.Start(ctx, "TokenService.RevokeRefreshToken")
+	defer span.End()
+
+	stored, err := a.refreshTokenRepo.GetRefreshTokenByHash(ctx, utils.HashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+
+	return a.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyId)
+}
+
+// RevokeJWT adds the access token to the denylist until it expires
+func (a *tokenService) RevokeJWT(ctx context.Context, userJWT string) error {
+	ctx, span := a.tracer.Start(ctx, "TokenService.RevokeJWT")
+	defer span.End()
+
+	decodedJwt, err := a.decodeJWT(userJWT)
+	if err != nil {
+		return err
+	}
+
+	return a.revokeDecodedJWT(ctx, decodedJwt)
+}
+
+// revokeDecodedJWT adds a verified access token to the denylist until it expires
+func (a *tokenService) revokeDecodedJWT(ctx context.Context, decodedJwt *jwt.Token) error {
+	claims := decodedJwt.Claims.(jwt.MapClaims)
+	jti, ok := claims["jti"].(string)
+	if !ok {
+		return domain.ErrInvalidToken
+	}
+	userIdFloat, ok := claims["userId"].(float64)
+	if !ok {
+		return domain.ErrInvalidToken
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return domain.ErrInvalidToken
 	}
 
-	jwtUtil := token.NewJwtUtil(token.JWTConfig{
-		PrivateKey: privateKey,
-		PublicKey:  publicKey,
+	return a.tokenRevocationRepo.RevokeToken(ctx, &domain.RevokedToken{
+		Jti:       jti,
+		AccountId: int(userIdFloat),
+		ExpiresAt: expiresAt.Time,
 	})
+}
+
+// RevokeSession ends a single login, revoking the access token and its refresh token family.
+// Either token may be empty. An access token that does not decode, such as an expired one,
+// is no longer accepted anyway and is left alone.
+func (a *tokenService) RevokeSession(ctx context.Context, accessToken, refreshToken string) error {
+	ctx, span := a.tracer.Start(ctx, "TokenService.RevokeSession")
+	defer span.End()
+
+	if accessToken != "" {
+		decodedJwt, err := a.decodeJWT(accessToken)
+		if err == nil {
+			err = a.revokeDecodedJWT(ctx, decodedJwt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if refreshToken != "" {
+		err := a.RevokeRefreshToken(ctx, refreshToken)
+		if err != nil && !errors.Is(err, domain.ErrRefreshTokenNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevokeAllSessions logs the account out everywhere, invalidating every
+// access token issued so far and every refresh token family.
+func (a *tokenService) RevokeAllSessions(ctx context.Context, accountId int) error {
+	ctx, span := a.tracer.Start(ctx, "TokenService.RevokeAllSessions")
+	defer span.End()
+
+	err := domain.ForbidImpersonation(ctx)
+	if err != nil {
+		return err
+	}
 
-	return &tokenService{
-		jwtUtil:     jwtUtil,
-		tokenExpiry: cfg.ExpirationHours,
+	// Access tokens issued until now stay valid at most for accessExpiry
+	now := time.Now().Truncate(time.Microsecond)
+	err = a.tokenRevocationRepo.RevokeAccountTokens(ctx, accountId, now, now.Add(a.accessExpiry))
+	if err != nil {
+		return err
+	}
+
+	return a.refreshTokenRepo.RevokeRefreshTokensByAccount(ctx, accountId)
+}
+
+func (a *tokenService) RevokeAccessTokens(ctx context.Context, accountId int) error {
+	ctx, span := a.tracer.Start(ctx, "TokenService.RevokeAccessTokens")
+	defer span.End()
+
+	now := time.Now().Truncate(time.Microsecond)
+	return a.tokenRevocationRepo.RevokeAccountTokens(ctx, accountId, now, now.Add(a.accessExpiry))
+}
+
+func (a *tokenService) IsRevoked(ctx context.Context, userJWT string) (bool, error) {
+	ctx, span := a.tracer.Start(ctx, "TokenService.IsRevoked")
+	defer span.End()
+
+	decodedJwt, err := a.decodeJWT(userJWT)
+	if err != nil {
+		return false, err
+	}
+
+	claims := decodedJwt.Claims.(jwt.MapClaims)
+	userIdFloat, ok := claims["userId"].(float64)
+	if !ok {
+		return false, domain.ErrInvalidToken
+	}
+	issuedAt, ok := claims["iat"].(float64)
+	if !ok {
+		return false, domain.ErrInvalidToken
+	}
+	jti, _ := claims["jti"].(string)
+
+	return a.tokenRevocationRepo.IsTokenRevoked(ctx, jti, int(userIdFloat), issuedAtTime(issuedAt))
+}
+
+// issuedAtClaim returns the iat of a token in seconds with microsecond precision, which
+// account revocations are compared against, so tokens issued right after a revocation in
+// the same second stay valid
+func issuedAtClaim(now time.Time) float64 {
+	return float64(now.UnixMicro()) / 1e6
+}
+
+// issuedAtTime reads an iat claim, GetIssuedAt would truncate it to the second
+func issuedAtTime(iat float64) time.Time {
+	return time.UnixMicro(int64(math.Round(iat * 1e6)))
+}
+
+// PruneRevokedTokens removes denylist entries for tokens that have expired anyway
+func (a *tokenService) PruneRevokedTokens(ctx context.Context) (int64, error) {
+	ctx, span := a.tracer.Start(ctx, "TokenService.PruneRevokedTokens")
+	defer span.End()
+
+	return a.tokenRevocationRepo.PruneExpired(ctx, time.Now())
+}
+
+func NewTokenService(
+	container *infra.Container,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	tokenRevocationRepo domain.TokenRevocationRepository,
+) domain.TokenService {
+	cfg := container.Cfg.JWT
+
+	keys, err := loadKeyring(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	pendingExpiry := time.Minute * time.Duration(container.Cfg.Auth.MFAPendingExpirationMinutes)
+	if pendingExpiry <= 0 {
+		pendingExpiry = defaultMFAPendingExpiry
+	}
+
+	logger := container.Logger.With("path", "tokenService")
+	service := &tokenService{
+		logger:              logger,
+		tracer:              container.Tracer,
+		jwtConfig:           cfg,
+		accessExpiry:        time.Minute * time.Duration(cfg.AccessExpirationMinutes),
+		refreshExpiry:       time.Hour * time.Duration(cfg.RefreshExpirationHours),
+		pendingExpiry:       pendingExpiry,
+		refreshTokenRepo:    refreshTokenRepo,
+		tokenRevocationRepo: tokenRevocationRepo,
+	}
+	service.keys.Store(keys)
+
+	return service
+}
+
+// loadKeyring reads the keyring directory, falling back to the configured key pair until
+// the first rotation creates a keyring
+func loadKeyring(cfg config.JWTConfig) (*keyring.Keyring, error) {
+	if cfg.KeyringDir != "" && keyring.Exists(cfg.KeyringDir) {
+		return keyring.Load(cfg.KeyringDir, time.Now())
+	}
+
+	privateKey, _, err := utils.LoadECDSAKeyPair(cfg.PrivateKeyPath, cfg.PublicKeyPath)
+	if err != nil {
+		return nil, err
 	}
+
+	return keyring.FromKeyPair(privateKey)
+}
+
+func newJSONWebKey(kid string, publicKey *ecdsa.PublicKey) (domain.JSONWebKey, error) {
+	crv, x, y, err := utils.ECDSAJWKParams(publicKey)
+	if err != nil {
+		return domain.JSONWebKey{}, err
+	}
+
+	return domain.JSONWebKey{
+		Kty: "EC",
+		Use: "sig",
+		Alg: jwt.SigningMethodES512.Alg(),
+		Kid: kid,
+		Crv: crv,
+		X:   x,
+		Y:   y,
+	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"gostarter/infra"
 	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
 	"log/slog"
 	"time"
 
@@ -29,16 +30,17 @@ func (a *accountRepository) CreateAccount(ctx context.Context, account *domain.A
 	_, span := a.tracer.Start(ctx, "AccountRepository.CreateAccount")
 	defer span.End()
 
+	err := a.checkIdentifiers(account)
+	if err != nil {
+		return err
+	}
+
 	id := len(a.accounts) + 1
 	account.Id = id
 	now := time.Now()
 	account.CreatedAt = now
 	account.UpdatedAt = now
 
-	if account.Username == "" {
-		account.Username = account.Email
-	}
-
 	a.accounts = append(a.accounts, *account)
 
 	return nil
@@ -51,7 +53,7 @@ func (a *accountRepository) GetAccountByID(ctx context.Context, id int) (*domain
 	var account *domain.Account
 
 	for _, acc := range a.accounts {
-		if acc.Id == id {
+		if acc.Id == id && acc.DeletedAt == nil {
 			account = &acc
 			break
 		}
@@ -70,8 +72,9 @@ func (a *accountRepository) GetAccountByEmail(ctx context.Context, email string)
 
 	var account domain.Account
 
+	email = utils.NormalizeIdentifier(email)
 	for _, acc := range a.accounts {
-		if acc.Email == email {
+		if utils.NormalizeIdentifier(acc.Email) == email && acc.DeletedAt == nil {
 			account = acc
 			break
 		}
@@ -90,8 +93,9 @@ func (a *accountRepository) GetAccountByUsername(ctx context.Context, username s
 
 	var account domain.Account
 
+	username = utils.NormalizeIdentifier(username)
 	for _, acc := range a.accounts {
-		if acc.Username == username {
+		if username != "" && utils.NormalizeIdentifier(acc.Username) == username && acc.DeletedAt == nil {
 			account = acc
 			break
 		}
@@ -108,6 +112,11 @@ func (a *accountRepository) UpdateAccount(ctx context.Context, account *domain.A
 	_, span := a.tracer.Start(ctx, "AccountRepository.UpdateAccount")
 	defer span.End()
 
+	err := a.checkIdentifiers(account)
+	if err != nil {
+		return err
+	}
+
 	var updatedAccount *domain.Account
 
 	for i, acc := range a.accounts {
@@ -125,48 +134,105 @@ func (a *accountRepository) UpdateAccount(ctx context.Context, account *domain.A
 	return nil
 }
 
-func (a *accountRepository) DeleteAccount(ctx context.Context, id int) error {
-	_, span := a.tracer.Start(ctx, "AccountRepository.DeleteAccount")
-	defer span.End()
+// checkIdentifiers enforces the unique indexes of the postgres table, deleted accounts keep
+// their email and username until they are purged
+func (a *accountRepository) checkIdentifiers(account *domain.Account) error {
+	email := utils.NormalizeIdentifier(account.Email)
+	username := utils.NormalizeIdentifier(account.Username)
 
-	var deletedAccount *domain.Account
+	for _, acc := range a.accounts {
+		if acc.Id == account.Id {
+			continue
+		}
+		if utils.NormalizeIdentifier(acc.Email) == email {
+			return domain.ErrEmailTaken
+		}
+		if username != "" && utils.NormalizeIdentifier(acc.Username) == username {
+			return domain.ErrUsernameTaken
+		}
+	}
+
+	return nil
+}
+
+func (a *accountRepository) SetAccountDisabled(ctx context.Context, id int, disabledAt *time.Time) error {
+	_, span := a.tracer.Start(ctx, "AccountRepository.SetAccountDisabled")
+	defer span.End()
 
 	for i, acc := range a.accounts {
-		if acc.Id == id {
-			deletedAccount = &a.accounts[i]
-			a.accounts = append(a.accounts[:i], a.accounts[i+1:]...)
-			break
+		if acc.Id == id && acc.DeletedAt == nil {
+			a.accounts[i].DisabledAt = disabledAt
+			a.accounts[i].UpdatedAt = time.Now()
+			return nil
 		}
 	}
 
-	if deletedAccount == nil || deletedAccount.Id == 0 {
-		return domain.ErrAccountNotFound
+	return domain.ErrAccountNotFound
+}
+
+func (a *accountRepository) SoftDeleteAccount(ctx context.Context, id int, deletedAt time.Time) error {
+	_, span := a.tracer.Start(ctx, "AccountRepository.SoftDeleteAccount")
+	defer span.End()
+
+	for i, acc := range a.accounts {
+		if acc.Id == id && acc.DeletedAt == nil {
+			a.accounts[i].DeletedAt = &deletedAt
+			return nil
+		}
 	}
 
-	return nil
+	return domain.ErrAccountNotFound
 }
 
-func (a *accountRepository) ListAccounts(ctx context.Context, pagination *domain.Pagination) ([]*domain.Account, error) {
-	_, span := a.tracer.Start(ctx, "AccountRepository.ListAccounts")
+func (a *accountRepository) RestoreAccount(ctx context.Context, id int) error {
+	_, span := a.tracer.Start(ctx, "AccountRepository.RestoreAccount")
 	defer span.End()
 
-	offset := pagination.GetOffset()
-
-	if offset >= len(a.accounts) {
-		return []*domain.Account{}, nil
+	for i, acc := range a.accounts {
+		if acc.Id == id && acc.DeletedAt != nil {
+			a.accounts[i].DeletedAt = nil
+			return nil
+		}
 	}
 
-	end := offset + pagination.Size
+	return domain.ErrAccountNotDeleted
+}
+
+func (a *accountRepository) PurgeDeletedAccounts(ctx context.Context, before time.Time) ([]int, error) {
+	_, span := a.tracer.Start(ctx, "AccountRepository.PurgeDeletedAccounts")
+	defer span.End()
 
-	if end > len(a.accounts) {
-		end = len(a.accounts)
+	ids := []int{}
+	kept := a.accounts[:0]
+	for _, acc := range a.accounts {
+		if acc.DeletedAt != nil && acc.DeletedAt.Before(before) {
+			ids = append(ids, acc.Id)
+			continue
+		}
+		kept = append(kept, acc)
 	}
+	a.accounts = kept
 
-	var result []*domain.Account
+	return ids, nil
+}
 
-	for i := offset; i < end; i++ {
-		result = append(result, &a.accounts[i])
+func (a *accountRepository) DeleteAccount(ctx context.Context, id int) error {
+	_, span := a.tracer.Start(ctx, "AccountRepository.DeleteAccount")
+	defer span.End()
+
+	var deletedAccount *domain.Account
+
+	for i, acc := range a.accounts {
+		if acc.Id == id {
+			deletedAccount = &a.accounts[i]
+			a.accounts = append(a.accounts[:i], a.accounts[i+1:]...)
+			break
+		}
 	}
 
-	return result, nil
+	if deletedAccount == nil || deletedAccount.Id == 0 {
+		return domain.ErrAccountNotFound
+	}
+
+	return nil
 }
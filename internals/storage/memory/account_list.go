@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"slices"
+	"strings"
+	"time"
+)
+
+func (a *accountRepository) ListAccounts(
+	ctx context.Context,
+	filter *domain.AccountFilter,
+	sort []domain.AccountSort,
+	pagination *domain.Pagination,
+) ([]*domain.Account, error) {
+	_, span := a.tracer.Start(ctx, "AccountRepository.ListAccounts")
+	defer span.End()
+
+	sort = withIdSort(sort)
+	accounts := a.listAccounts(filter, sort)
+
+	pagination.SetTotal(len(accounts))
+
+	offset := pagination.GetOffset()
+
+	if offset >= len(accounts) {
+		return []*domain.Account{}, nil
+	}
+
+	end := offset + pagination.Size
+
+	if end > len(accounts) {
+		end = len(accounts)
+	}
+
+	return accounts[offset:end], nil
+}
+
+func (a *accountRepository) ListAccountsByCursor(
+	ctx context.Context,
+	filter *domain.AccountFilter,
+	sort []domain.AccountSort,
+	pagination *domain.CursorPagination,
+) (*domain.AccountConnection, error) {
+	_, span := a.tracer.Start(ctx, "AccountRepository.ListAccountsByCursor")
+	defer span.End()
+
+	sort = withIdSort(sort)
+	accounts := a.listAccounts(filter, sort)
+
+	cursor := pagination.After
+	if pagination.Backward() {
+		cursor = pagination.Before
+	}
+
+	// Read one account more than asked for, like the postgres queries
+	var page []*domain.Account
+	if pagination.Backward() {
+		end := len(accounts)
+		if cursor != "" {
+			pivot, err := decodeAccountCursor(cursor, sort)
+			if err != nil {
+				return nil, err
+			}
+			end, _ = slices.BinarySearchFunc(accounts, pivot, func(acc, pivot *domain.Account) int {
+				return compareAccounts(acc, pivot, sort)
+			})
+		}
+		page = accounts[max(0, end-pagination.Limit()-1):end]
+	} else {
+		start := 0
+		if cursor != "" {
+			pivot, err := decodeAccountCursor(cursor, sort)
+			if err != nil {
+				return nil, err
+			}
+			var found bool
+			start, found = slices.BinarySearchFunc(accounts, pivot, func(acc, pivot *domain.Account) int {
+				return compareAccounts(acc, pivot, sort)
+			})
+			if found {
+				start++
+			}
+		}
+		page = accounts[start:min(len(accounts), start+pagination.Limit()+1)]
+	}
+
+	connection := domain.NewAccountConnection(page, pagination, func(account *domain.Account) string {
+		return encodeAccountCursor(account, sort)
+	})
+	if pagination.WithTotal {
+		total := len(accounts)
+		connection.TotalCount = &total
+	}
+
+	return connection, nil
+}
+
+// listAccounts returns the accounts matching the filter in the sort order
+func (a *accountRepository) listAccounts(filter *domain.AccountFilter, sort []domain.AccountSort) []*domain.Account {
+	var accounts []*domain.Account
+	for i := range a.accounts {
+		if matchAccountFilter(&a.accounts[i], filter) {
+			// Listings leave the password empty like the postgres store
+			account := a.accounts[i]
+			account.Password = ""
+			accounts = append(accounts, &account)
+		}
+	}
+
+	slices.SortFunc(accounts, func(x, y *domain.Account) int {
+		return compareAccounts(x, y, sort)
+	})
+
+	return accounts
+}
+
+// matchAccountFilter mirrors the filter condition of the postgres listings
+func matchAccountFilter(acc *domain.Account, filter *domain.AccountFilter) bool {
+	if (acc.DeletedAt != nil) != (filter.Status == domain.ACCOUNT_STATUS_DELETED) {
+		return false
+	}
+
+	switch filter.Status {
+	case domain.ACCOUNT_STATUS_ACTIVE:
+		if acc.DisabledAt != nil {
+			return false
+		}
+	case domain.ACCOUNT_STATUS_DISABLED:
+		if acc.DisabledAt == nil {
+			return false
+		}
+	case domain.ACCOUNT_STATUS_UNVERIFIED:
+		if acc.EmailVerifiedAt != nil {
+			return false
+		}
+	}
+
+	if filter.Role != "" && !slices.Contains(acc.Roles, filter.Role) {
+		return false
+	}
+
+	if !inTimeRange(acc.CreatedAt, filter.CreatedFrom, filter.CreatedTo) ||
+		!inTimeRange(acc.UpdatedAt, filter.UpdatedFrom, filter.UpdatedTo) {
+		return false
+	}
+
+	email := utils.NormalizeIdentifier(acc.Email)
+	username := utils.NormalizeIdentifier(acc.Username)
+
+	if !strings.HasPrefix(email, utils.NormalizeIdentifier(filter.EmailPrefix)) ||
+		!strings.HasPrefix(username, utils.NormalizeIdentifier(filter.UsernamePrefix)) {
+		return false
+	}
+
+	query := utils.NormalizeIdentifier(filter.Query)
+	return strings.Contains(email, query) || strings.Contains(username, query)
+}
+
+// inTimeRange reports whether t is in [from, to), nil bounds are open
+func inTimeRange(t time.Time, from, to *time.Time) bool {
+	return (from == nil || !t.Before(*from)) && (to == nil || t.Before(*to))
+}
+
+// withIdSort appends the id to the sort unless it is sorted by already, it breaks ties
+func withIdSort(sort []domain.AccountSort) []domain.AccountSort {
+	if slices.ContainsFunc(sort, func(s domain.AccountSort) bool { return s.Field == domain.ACCOUNT_SORT_ID }) {
+		return sort
+	}
+	return append(slices.Clip(sort), domain.AccountSort{Field: domain.ACCOUNT_SORT_ID})
+}
+
+func compareAccounts(x, y *domain.Account, sort []domain.AccountSort) int {
+	for _, s := range sort {
+		var c int
+		switch s.Field {
+		case domain.ACCOUNT_SORT_EMAIL:
+			c = strings.Compare(utils.NormalizeIdentifier(x.Email), utils.NormalizeIdentifier(y.Email))
+		case domain.ACCOUNT_SORT_USERNAME:
+			c = strings.Compare(utils.NormalizeIdentifier(x.Username), utils.NormalizeIdentifier(y.Username))
+		case domain.ACCOUNT_SORT_CREATED_AT:
+			c = x.CreatedAt.Compare(y.CreatedAt)
+		case domain.ACCOUNT_SORT_UPDATED_AT:
+			c = x.UpdatedAt.Compare(y.UpdatedAt)
+		default:
+			c = cmp.Compare(x.Id, y.Id)
+		}
+
+		if s.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// encodeAccountCursor carries the sort key and the sort values of the account, like the postgres cursors
+func encodeAccountCursor(account *domain.Account, sort []domain.AccountSort) string {
+	values := []any{domain.AccountSortKey(sort)}
+	for _, s := range sort {
+		switch s.Field {
+		case domain.ACCOUNT_SORT_EMAIL:
+			values = append(values, utils.NormalizeIdentifier(account.Email))
+		case domain.ACCOUNT_SORT_USERNAME:
+			values = append(values, utils.NormalizeIdentifier(account.Username))
+		case domain.ACCOUNT_SORT_CREATED_AT:
+			values = append(values, account.CreatedAt)
+		case domain.ACCOUNT_SORT_UPDATED_AT:
+			values = append(values, account.UpdatedAt)
+		default:
+			values = append(values, account.Id)
+		}
+	}
+	return utils.EncodeCursor(values...)
+}
+
+// decodeAccountCursor returns an account holding the sort values of the cursor to compare with
+func decodeAccountCursor(cursor string, sort []domain.AccountSort) (*domain.Account, error) {
+	pivot := &domain.Account{}
+
+	var key string
+	dest := []any{&key}
+	for _, s := range sort {
+		switch s.Field {
+		case domain.ACCOUNT_SORT_EMAIL:
+			dest = append(dest, &pivot.Email)
+		case domain.ACCOUNT_SORT_USERNAME:
+			dest = append(dest, &pivot.Username)
+		case domain.ACCOUNT_SORT_CREATED_AT:
+			dest = append(dest, &pivot.CreatedAt)
+		case domain.ACCOUNT_SORT_UPDATED_AT:
+			dest = append(dest, &pivot.UpdatedAt)
+		default:
+			dest = append(dest, &pivot.Id)
+		}
+	}
+
+	err := utils.DecodeCursor(cursor, dest...)
+	if err != nil || key != domain.AccountSortKey(sort) {
+		return nil, domain.ErrInvalidCursor
+	}
+	return pivot, nil
+}
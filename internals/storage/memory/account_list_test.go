@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"gostarter/pkg/testUtils"
+	"slices"
+	"testing"
+)
+
+// newCursorTestRepository holds accounts 1 to 6, three of them without a username so a
+// username sort has ties for the id to break
+func newCursorTestRepository(t *testing.T) domain.AccountRepository {
+	t.Helper()
+
+	repo := NewAccountRepository(&infra.Container{
+		Logger: testUtils.NewNoopLogger(),
+		Tracer: testUtils.NewNoopTracer(),
+	})
+
+	for _, account := range []struct{ username, email string }{
+		{"", "one@example.com"},
+		{"", "two@example.com"},
+		{"carol", "three@example.com"},
+		{"", "four@example.com"},
+		{"bob", "five@example.com"},
+		{"alice", "six@example.com"},
+	} {
+		err := repo.CreateAccount(context.Background(), &domain.Account{Username: account.username, Email: account.email})
+		if err != nil {
+			t.Fatalf("CreateAccount: %v", err)
+		}
+	}
+
+	return repo
+}
+
+func connectionIds(connection *domain.AccountConnection) []int {
+	ids := []int{}
+	for _, edge := range connection.Edges {
+		ids = append(ids, edge.Node.Id)
+	}
+	return ids
+}
+
+func TestListAccountsByCursor(t *testing.T) {
+	byUsername := []domain.AccountSort{{Field: domain.ACCOUNT_SORT_USERNAME}}
+	byUsernameDesc := []domain.AccountSort{{Field: domain.ACCOUNT_SORT_USERNAME, Descending: true}}
+
+	tests := []struct {
+		name  string
+		sort  []domain.AccountSort
+		first *domain.CursorPagination
+		// next returns the pagination of the following page from the current one
+		next     func(connection *domain.AccountConnection) *domain.CursorPagination
+		ids      [][]int
+		hasNext  []bool
+		hasPrior []bool
+	}{
+		{
+			name:  "first and after",
+			sort:  byUsername,
+			first: &domain.CursorPagination{First: 2},
+			next: func(connection *domain.AccountConnection) *domain.CursorPagination {
+				return &domain.CursorPagination{First: 2, After: *connection.PageInfo.EndCursor}
+			},
+			ids:      [][]int{{1, 2}, {4, 6}, {5, 3}},
+			hasNext:  []bool{true, true, false},
+			hasPrior: []bool{false, true, true},
+		},
+		{
+			name:  "last and before",
+			sort:  byUsername,
+			first: &domain.CursorPagination{Last: 2},
+			next: func(connection *domain.AccountConnection) *domain.CursorPagination {
+				return &domain.CursorPagination{Last: 2, Before: *connection.PageInfo.StartCursor}
+			},
+			ids:      [][]int{{5, 3}, {4, 6}, {1, 2}},
+			hasNext:  []bool{false, true, true},
+			hasPrior: []bool{true, true, false},
+		},
+		{
+			name:  "descending across ties",
+			sort:  byUsernameDesc,
+			first: &domain.CursorPagination{First: 4},
+			next: func(connection *domain.AccountConnection) *domain.CursorPagination {
+				return &domain.CursorPagination{First: 4, After: *connection.PageInfo.EndCursor}
+			},
+			ids:      [][]int{{3, 5, 6, 1}, {2, 4}},
+			hasNext:  []bool{true, false},
+			hasPrior: []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newCursorTestRepository(t)
+			pagination := tt.first
+
+			for i, want := range tt.ids {
+				connection, err := repo.ListAccountsByCursor(context.Background(), &domain.AccountFilter{}, tt.sort, pagination)
+				if err != nil {
+					t.Fatalf("page %d: ListAccountsByCursor: %v", i, err)
+				}
+
+				if got := connectionIds(connection); !slices.Equal(got, want) {
+					t.Fatalf("page %d: ids = %v, want %v", i, got, want)
+				}
+				if connection.PageInfo.HasNextPage != tt.hasNext[i] || connection.PageInfo.HasPreviousPage != tt.hasPrior[i] {
+					t.Fatalf("page %d: has next, previous = %v, %v, want %v, %v", i,
+						connection.PageInfo.HasNextPage, connection.PageInfo.HasPreviousPage, tt.hasNext[i], tt.hasPrior[i])
+				}
+
+				pagination = tt.next(connection)
+			}
+		})
+	}
+}
+
+func TestListAccountsByCursorRejectsForeignCursors(t *testing.T) {
+	repo := newCursorTestRepository(t)
+	ctx := context.Background()
+	byUsername := []domain.AccountSort{{Field: domain.ACCOUNT_SORT_USERNAME}}
+
+	connection, err := repo.ListAccountsByCursor(ctx, &domain.AccountFilter{}, byUsername, &domain.CursorPagination{First: 2})
+	if err != nil {
+		t.Fatalf("ListAccountsByCursor: %v", err)
+	}
+	cursor := *connection.PageInfo.EndCursor
+
+	tests := []struct {
+		name       string
+		sort       []domain.AccountSort
+		pagination *domain.CursorPagination
+	}{
+		{"garbage", byUsername, &domain.CursorPagination{First: 2, After: "garbage"}},
+		{"tampered", byUsername, &domain.CursorPagination{First: 2, After: cursor[:len(cursor)-3]}},
+		{"other sort", []domain.AccountSort{{Field: domain.ACCOUNT_SORT_EMAIL}}, &domain.CursorPagination{First: 2, After: cursor}},
+		{"other direction", []domain.AccountSort{{Field: domain.ACCOUNT_SORT_USERNAME, Descending: true}}, &domain.CursorPagination{Last: 2, Before: cursor}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.ListAccountsByCursor(ctx, &domain.AccountFilter{}, tt.sort, tt.pagination)
+			if !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Fatalf("ListAccountsByCursor error = %v, want %v", err, domain.ErrInvalidCursor)
+			}
+		})
+	}
+}
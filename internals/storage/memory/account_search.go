@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"slices"
+	"strings"
+)
+
+func (a *accountRepository) SearchAccounts(ctx context.Context, query string, limit int) ([]*domain.AccountSearchResult, error) {
+	_, span := a.tracer.Start(ctx, "AccountRepository.SearchAccounts")
+	defer span.End()
+
+	terms := utils.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, domain.ErrInvalidSearchQuery
+	}
+
+	queryTrigrams := trigrams(terms)
+
+	results := []*domain.AccountSearchResult{}
+	for i := range a.accounts {
+		if a.accounts[i].DeletedAt != nil {
+			continue
+		}
+		acc := a.accounts[i]
+		acc.Password = ""
+
+		usernameWords := utils.SearchTerms(acc.Username)
+		emailWords := utils.SearchTerms(acc.Email)
+
+		// Like the postgres search, every term has to start a word for a text match, or
+		// the query has to be similar enough to the words of the username or email.
+		// Username words rank above email words.
+		textRank, matched := 0.0, true
+		for _, term := range terms {
+			switch {
+			case hasWordPrefix(usernameWords, term):
+				textRank += 1
+			case hasWordPrefix(emailWords, term):
+				textRank += 0.4
+			default:
+				matched = false
+			}
+		}
+		if !matched {
+			textRank = 0
+		}
+
+		similarity := max(
+			trigramSimilarity(queryTrigrams, trigrams(usernameWords)),
+			trigramSimilarity(queryTrigrams, trigrams(emailWords)),
+		)
+
+		if !matched && similarity < domain.ACCOUNT_SEARCH_SIMILARITY {
+			continue
+		}
+
+		results = append(results, &domain.AccountSearchResult{
+			Account: &acc,
+			Rank:    textRank/float64(len(terms)) + similarity,
+		})
+	}
+
+	slices.SortFunc(results, func(x, y *domain.AccountSearchResult) int {
+		if c := cmp.Compare(y.Rank, x.Rank); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.Account.Id, y.Account.Id)
+	})
+
+	return results[:min(len(results), limit)], nil
+}
+
+func hasWordPrefix(words []string, prefix string) bool {
+	return slices.ContainsFunc(words, func(word string) bool {
+		return strings.HasPrefix(word, prefix)
+	})
+}
+
+// trigrams returns the trigrams of the words, padded like pg_trgm pads them
+func trigrams(words []string) map[string]bool {
+	set := map[string]bool{}
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// trigramSimilarity approximates the word_similarity of pg_trgm, the share of the query
+// trigrams found in the text
+func trigramSimilarity(query, text map[string]bool) float64 {
+	if len(query) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range query {
+		if text[trigram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(query))
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type accountRevocation struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+type tokenRevocationRepository struct {
+	logger *slog.Logger
+	tracer trace.Tracer
+
+	mu       sync.RWMutex
+	tokens   map[string]domain.RevokedToken
+	accounts map[int]accountRevocation
+}
+
+func NewTokenRevocationRepository(container *infra.Container) domain.TokenRevocationRepository {
+	logger := container.Logger.With("path", "tokenRevocationRepository")
+	return &tokenRevocationRepository{
+		logger:   logger,
+		tracer:   container.Tracer,
+		tokens:   map[string]domain.RevokedToken{},
+		accounts: map[int]accountRevocation{},
+	}
+}
+
+func (t *tokenRevocationRepository) RevokeToken(ctx context.Context, token *domain.RevokedToken) error {
+	_, span := t.tracer.Start(ctx, "TokenRevocationRepository.RevokeToken")
+	defer span.End()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tokens[token.Jti] = *token
+
+	return nil
+}
+
+func (t *tokenRevocationRepository) RevokeAccountTokens(ctx context.Context, accountId int, revokedBefore, expiresAt time.Time) error {
+	_, span := t.tracer.Start(ctx, "TokenRevocationRepository.RevokeAccountTokens")
+	defer span.End()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.accounts[accountId] = accountRevocation{
+		revokedBefore: revokedBefore,
+		expiresAt:     expiresAt,
+	}
+
+	return nil
+}
+
+func (t *tokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string, accountId int, issuedAt time.Time) (bool, error) {
+	_, span := t.tracer.Start(ctx, "TokenRevocationRepository.IsTokenRevoked")
+	defer span.End()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if _, ok := t.tokens[jti]; ok {
+		return true, nil
+	}
+
+	revocation, ok := t.accounts[accountId]
+	if ok && issuedAt.Before(revocation.revokedBefore) {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (t *tokenRevocationRepository) PruneExpired(ctx context.Context, now time.Time) (int64, error) {
+	_, span := t.tracer.Start(ctx, "TokenRevocationRepository.PruneExpired")
+	defer span.End()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var pruned int64
+	for jti, token := range t.tokens {
+		if token.ExpiresAt.Before(now) {
+			delete(t.tokens, jti)
+			pruned++
+		}
+	}
+
+	for accountId, revocation := range t.accounts {
+		if revocation.expiresAt.Before(now) {
+			delete(t.accounts, accountId)
+			pruned++
+		}
+	}
+
+	return pruned, nil
+}
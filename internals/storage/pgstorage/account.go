@@ -3,11 +3,14 @@ package pgstorage
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"gostarter/infra"
 	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
 	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -25,20 +28,22 @@ func NewAccountRepository(container *infra.Container) domain.AccountRepository {
 	}
 }
 
+// Unique indexes on the normalized columns, see migration 000017
+const (
+	accountEmailKey    = "gostarter_account_email_normalized_key"
+	accountUsernameKey = "gostarter_account_username_normalized_key"
+)
+
 const (
 	createAccountQuery = `
-		INSERT INTO gostarter_account (username, email, password, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO gostarter_account
+		    (username, username_normalized, email, email_normalized, password, email_verified_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id`
 
 	getRoleIDByNameQuery = `
 		SELECT id FROM gostarter_role WHERE name = $1`
 
-	createRoleQuery = `
-		INSERT INTO gostarter_role (name, created_at, updated_at)
-		VALUES ($1, $2, $3)
-		RETURNING id`
-
 	assignRoleToAccountQuery = `
 		INSERT INTO gostarter_account_role (account_id, role_id, created_at)
 		VALUES ($1, $2, $3)`
@@ -50,44 +55,60 @@ const (
 		WHERE ar.account_id = $1`
 
 	getAccountByIDQuery = `
-		SELECT a.id, a.username, a.email, a.password, a.created_at, a.updated_at 
+		SELECT a.id, a.username, a.email, a.email_verified_at, a.disabled_at, a.created_at, a.updated_at, a.password
 		FROM gostarter_account a
-		WHERE a.id = $1
+		WHERE a.id = $1 AND a.deleted_at IS NULL
 		GROUP BY a.id`
 
 	getAccountByEmailQuery = `
-		SELECT a.id, a.username, a.email, a.password, a.created_at, a.updated_at
+		SELECT a.id, a.username, a.email, a.email_verified_at, a.disabled_at, a.created_at, a.updated_at, a.password
 		FROM gostarter_account a
-		WHERE a.email = $1
+		WHERE a.email_normalized = $1 AND a.deleted_at IS NULL
 		GROUP BY a.id`
 
 	getAccountByUsernameQuery = `
-		SELECT a.id, a.username, a.email, a.password, a.created_at, a.updated_at
+		SELECT a.id, a.username, a.email, a.email_verified_at, a.disabled_at, a.created_at, a.updated_at, a.password
 		FROM gostarter_account a
-		WHERE a.username = $1
+		WHERE a.username_normalized = $1 AND a.deleted_at IS NULL
 		GROUP BY a.id`
 
 	updateAccountQuery = `
 		UPDATE gostarter_account
-		SET username = $1, email = $2, password = $3, updated_at = $4
-		WHERE id = $5`
+		SET username = $1, username_normalized = $2, email = $3, email_normalized = $4,
+		    password = $5, email_verified_at = $6, updated_at = $7
+		WHERE id = $8`
+
+	setAccountDisabledQuery = `
+		UPDATE gostarter_account
+		SET disabled_at = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL`
 
+	softDeleteAccountQuery = `
+		UPDATE gostarter_account
+		SET deleted_at = $1
+		WHERE id = $2 AND deleted_at IS NULL`
+
+	restoreAccountQuery = `
+		UPDATE gostarter_account
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	// Dependent rows go with the account through ON DELETE CASCADE
 	deleteAccountQuery = `
-		DELETE FROM gostarter_account WHERE id = $1`
+		DELETE FROM gostarter_account WHERE id = $1
+		RETURNING id, email`
 
-	listAccountsQuery = `
-		SELECT a.id, a.username, a.email, a.password, a.created_at, a.updated_at,
-			   array_agg(r.name) as roles
-		FROM gostarter_account a
-		LEFT JOIN gostarter_account_role ar ON a.id = ar.account_id
-		LEFT JOIN gostarter_role r ON ar.role_id = r.id
-		GROUP BY a.id
-		ORDER BY a.id
-		LIMIT $1 OFFSET $2`
-
-	totalAccountsQuery = `
-		SELECT COUNT(id) FROM gostarter_account
-		`
+	purgeDeletedAccountsQuery = `
+		DELETE FROM gostarter_account WHERE deleted_at < $1
+		RETURNING id, email`
+
+	// Denylist entries and login attempts are not tied to the account by a foreign key
+	deleteAccountRevokedTokensQuery = `
+		DELETE FROM gostarter_revoked_token WHERE account_id = $1`
+
+	deleteAccountLoginAttemptsQuery = `
+		DELETE FROM gostarter_login_attempt
+		WHERE (scope = $1 AND key = $2) OR (scope = $3 AND key = $4)`
 )
 
 func (a *accountRepository) CreateAccount(ctx context.Context, account *domain.Account) error {
@@ -109,47 +130,37 @@ func (a *accountRepository) CreateAccount(ctx context.Context, account *domain.A
 	}()
 
 	now := time.Now()
-	if account.Username == "" {
-		account.Username = account.Email
-	}
 
 	// Insert account
 	err = tx.QueryRowContext(
 		ctx,
 		createAccountQuery,
 		account.Username,
+		utils.NormalizeIdentifier(account.Username),
 		account.Email,
+		utils.NormalizeIdentifier(account.Email),
 		account.Password,
+		account.EmailVerifiedAt,
 		now,
 		now,
 	).Scan(&account.Id)
 
 	if err != nil {
+		err = identifierTakenError(err)
 		a.logger.Error("failed to create account", "error", err)
 		return err
 	}
 
-	// Assign roles
+	// Assign roles, they are managed separately and must exist
 	for _, roleName := range account.Roles {
-		// Try to get existing role Id
 		var roleID int
 		err = tx.QueryRowContext(ctx, getRoleIDByNameQuery, roleName).Scan(&roleID)
-
 		if err == sql.ErrNoRows {
-			// Role doesn't exist, create it
-			err = tx.QueryRowContext(
-				ctx,
-				createRoleQuery,
-				roleName,
-				now,
-				now,
-			).Scan(&roleID)
-
-			if err != nil {
-				a.logger.Error("failed to create role", "error", err, "role", roleName)
-				return err
-			}
-		} else if err != nil {
+			err = domain.ErrRoleNotFound
+			a.logger.Error("failed to assign unknown role", "role", roleName)
+			return err
+		}
+		if err != nil {
 			a.logger.Error("failed to get role id", "error", err, "role", roleName)
 			return err
 		}
@@ -188,17 +199,7 @@ func (a *accountRepository) GetAccountByID(ctx context.Context, id int) (*domain
 	ctx, span := a.tracer.Start(ctx, "AccountRepository.GetAccountByID")
 	defer span.End()
 
-	account := &domain.Account{}
-	var roles []string
-
-	err := a.conn.QueryRowContext(ctx, getAccountByIDQuery, id).Scan(
-		&account.Id,
-		&account.Username,
-		&account.Email,
-		&account.Password,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
+	account, err := scanAccountWithPassword(a.conn.QueryRowContext(ctx, getAccountByIDQuery, id))
 
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrAccountNotFound
@@ -209,13 +210,11 @@ func (a *accountRepository) GetAccountByID(ctx context.Context, id int) (*domain
 		return nil, err
 	}
 
-	err = a.conn.QueryRowContext(ctx, getRolesByAccountIDQuery, id).Scan(&roles)
+	account.Roles, err = a.getAccountRoles(ctx, account.Id)
 	if err != nil {
-		a.logger.Error("failed to get account roles", "error", err)
 		return nil, err
 	}
 
-	account.Roles = roles
 	return account, nil
 }
 
@@ -223,17 +222,7 @@ func (a *accountRepository) GetAccountByEmail(ctx context.Context, email string)
 	ctx, span := a.tracer.Start(ctx, "AccountRepository.GetAccountByEmail")
 	defer span.End()
 
-	var account domain.Account
-	var roles []string
-
-	err := a.conn.QueryRowContext(ctx, getAccountByEmailQuery, email).Scan(
-		&account.Id,
-		&account.Username,
-		&account.Email,
-		&account.Password,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
+	account, err := scanAccountWithPassword(a.conn.QueryRowContext(ctx, getAccountByEmailQuery, utils.NormalizeIdentifier(email)))
 
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrAccountNotFound
@@ -244,24 +233,41 @@ func (a *accountRepository) GetAccountByEmail(ctx context.Context, email string)
 		return nil, err
 	}
 
-	rows, err := a.conn.QueryContext(ctx, getRolesByAccountIDQuery, account.Id)
+	account.Roles, err = a.getAccountRoles(ctx, account.Id)
 	if err != nil {
-		a.logger.Error("failed to get account roles", "error", err)
 		return nil, err
 	}
 
-	for rows.Next() {
-		var role string
-		err := rows.Scan(&role)
-		if err != nil {
-			a.logger.Error("failed to scan role row", "error", err)
-			return nil, err
-		}
-		roles = append(roles, role)
+	return account, nil
+}
+
+func (a *accountRepository) GetAccountByUsername(ctx context.Context, username string) (*domain.Account, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.GetAccountByUsername")
+	defer span.End()
+
+	// Accounts without a username have an empty normalized username
+	normalized := utils.NormalizeIdentifier(username)
+	if normalized == "" {
+		return nil, domain.ErrAccountNotFound
 	}
 
-	account.Roles = roles
-	return &account, nil
+	account, err := scanAccountWithPassword(a.conn.QueryRowContext(ctx, getAccountByUsernameQuery, normalized))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAccountNotFound
+	}
+
+	if err != nil {
+		a.logger.Error("failed to get account by username", "error", err)
+		return nil, err
+	}
+
+	account.Roles, err = a.getAccountRoles(ctx, account.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
 }
 
 func (a *accountRepository) UpdateAccount(ctx context.Context, account *domain.Account) error {
@@ -270,13 +276,17 @@ func (a *accountRepository) UpdateAccount(ctx context.Context, account *domain.A
 
 	res, err := a.conn.ExecContext(ctx, updateAccountQuery,
 		account.Username,
+		utils.NormalizeIdentifier(account.Username),
 		account.Email,
+		utils.NormalizeIdentifier(account.Email),
 		account.Password,
+		account.EmailVerifiedAt,
 		time.Now(),
 		account.Id,
 	)
 
 	if err != nil {
+		err = identifierTakenError(err)
 		a.logger.Error("failed to update account", "error", err)
 		return err
 	}
@@ -293,13 +303,13 @@ func (a *accountRepository) UpdateAccount(ctx context.Context, account *domain.A
 	return nil
 }
 
-func (a *accountRepository) DeleteAccount(ctx context.Context, id int) error {
-	ctx, span := a.tracer.Start(ctx, "AccountRepository.DeleteAccount")
+func (a *accountRepository) SetAccountDisabled(ctx context.Context, id int, disabledAt *time.Time) error {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.SetAccountDisabled")
 	defer span.End()
 
-	res, err := a.conn.ExecContext(ctx, deleteAccountQuery, id)
+	res, err := a.conn.ExecContext(ctx, setAccountDisabledQuery, disabledAt, time.Now(), id)
 	if err != nil {
-		a.logger.Error("failed to delete account", "error", err)
+		a.logger.Error("failed to set account disabled", "error", err)
 		return err
 	}
 
@@ -315,67 +325,233 @@ func (a *accountRepository) DeleteAccount(ctx context.Context, id int) error {
 	return nil
 }
 
-func (a *accountRepository) ListAccounts(ctx context.Context, pagination *domain.Pagination) ([]*domain.Account, error) {
-	ctx, span := a.tracer.Start(ctx, "AccountRepository.ListAccounts")
+func (a *accountRepository) SoftDeleteAccount(ctx context.Context, id int, deletedAt time.Time) error {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.SoftDeleteAccount")
 	defer span.End()
 
-	var total int
+	res, err := a.conn.ExecContext(ctx, softDeleteAccountQuery, deletedAt, id)
+	if err != nil {
+		a.logger.Error("failed to soft delete account", "error", err)
+		return err
+	}
 
-	err := a.conn.QueryRowContext(ctx, totalAccountsQuery).Scan(&total)
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		a.logger.Error("failed to get total accounts", "error", err)
-		return nil, err
+		return err
 	}
 
-	pagination.SetTotal(total)
+	if rowsAffected == 0 {
+		return domain.ErrAccountNotFound
+	}
 
-	rows, err := a.conn.QueryContext(ctx, listAccountsQuery,
-		pagination.Size,
-		pagination.GetOffset(),
-	)
+	return nil
+}
+
+func (a *accountRepository) RestoreAccount(ctx context.Context, id int) error {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.RestoreAccount")
+	defer span.End()
+
+	res, err := a.conn.ExecContext(ctx, restoreAccountQuery, id)
 	if err != nil {
-		a.logger.Error("failed to list accounts", "error", err)
+		a.logger.Error("failed to restore account", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAccountNotDeleted
+	}
+
+	return nil
+}
+
+func (a *accountRepository) DeleteAccount(ctx context.Context, id int) error {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.DeleteAccount")
+	defer span.End()
+
+	ids, err := a.purgeAccounts(ctx, deleteAccountQuery, id)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return domain.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+func (a *accountRepository) PurgeDeletedAccounts(ctx context.Context, before time.Time) ([]int, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.PurgeDeletedAccounts")
+	defer span.End()
+
+	return a.purgeAccounts(ctx, purgeDeletedAccountsQuery, before)
+}
+
+// purgeAccounts runs a delete returning the id and email of the removed accounts, then clears
+// the rows keyed by them that the foreign keys do not cascade to
+func (a *accountRepository) purgeAccounts(ctx context.Context, query string, args ...any) ([]int, error) {
+	tx, err := a.conn.BeginTx(ctx, nil)
+	if err != nil {
+		a.logger.Error("failed to begin transaction", "error", err)
 		return nil, err
 	}
-	defer func(rows *sql.Rows) {
-		err := rows.Close()
+	defer func() {
 		if err != nil {
-			a.logger.Error("failed to close rows", slog.String("error", err.Error()))
+			if rbErr := tx.Rollback(); rbErr != nil {
+				a.logger.Error("failed to rollback transaction", "error", rbErr)
+			}
 		}
-	}(rows)
+	}()
 
-	var accounts []*domain.Account
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		a.logger.Error("failed to delete accounts", "error", err)
+		return nil, err
+	}
 
+	type purged struct {
+		id    int
+		email string
+	}
+
+	var accounts []purged
 	for rows.Next() {
-		account := &domain.Account{}
-		var roles []string
-
-		err := rows.Scan(
-			&account.Id,
-			&account.Username,
-			&account.Email,
-			&account.Password,
-			&account.CreatedAt,
-			&account.UpdatedAt,
-		)
+		var account purged
+		err = rows.Scan(&account.id, &account.email)
 		if err != nil {
-			a.logger.Error("failed to scan account row", "error", err)
+			_ = rows.Close()
+			a.logger.Error("failed to scan deleted account row", "error", err)
 			return nil, err
 		}
+		accounts = append(accounts, account)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, err
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
 
-		err = a.conn.QueryRowContext(ctx, getRolesByAccountIDQuery, account.Id).Scan(&roles)
+	ids := []int{}
+	for _, account := range accounts {
+		_, err = tx.ExecContext(ctx, deleteAccountRevokedTokensQuery, account.id)
 		if err != nil {
-			a.logger.Error("failed to get account roles", "error", err)
+			a.logger.Error("failed to delete revoked tokens", "error", err, "accountId", account.id)
 			return nil, err
 		}
 
-		account.Roles = roles
-		accounts = append(accounts, account)
+		accountKey := domain.AccountLockoutKey(account.email)
+		mfaKey := domain.MFALockoutKey(account.id)
+		_, err = tx.ExecContext(ctx, deleteAccountLoginAttemptsQuery, accountKey.Scope, accountKey.Key, mfaKey.Scope, mfaKey.Key)
+		if err != nil {
+			a.logger.Error("failed to delete login attempts", "error", err, "accountId", account.id)
+			return nil, err
+		}
+
+		ids = append(ids, account.id)
 	}
 
-	if err = rows.Err(); err != nil {
+	err = tx.Commit()
+	if err != nil {
+		a.logger.Error("failed to commit transaction", "error", err)
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// identifierTakenError maps unique violations of the email and username to their domain errors
+func identifierTakenError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+
+	switch pgErr.ConstraintName {
+	case accountEmailKey:
+		return domain.ErrEmailTaken
+	case accountUsernameKey:
+		return domain.ErrUsernameTaken
+	}
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAccount scans the account columns, followed by the extra columns of the query into extra.
+// Only the queries that check credentials select the password, listings leave it empty.
+func scanAccount(row rowScanner, extra ...any) (*domain.Account, error) {
+	account := &domain.Account{}
+	var emailVerifiedAt, disabledAt sql.NullTime
+
+	dest := []any{
+		&account.Id,
+		&account.Username,
+		&account.Email,
+		&emailVerifiedAt,
+		&disabledAt,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
 		return nil, err
 	}
 
-	return accounts, nil
+	if emailVerifiedAt.Valid {
+		account.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	if disabledAt.Valid {
+		account.DisabledAt = &disabledAt.Time
+	}
+
+	return account, nil
+}
+
+// scanAccountWithPassword scans the account columns followed by the password
+func scanAccountWithPassword(row rowScanner) (*domain.Account, error) {
+	var password string
+	account, err := scanAccount(row, &password)
+	if err != nil {
+		return nil, err
+	}
+
+	account.Password = password
+	return account, nil
+}
+
+func (a *accountRepository) getAccountRoles(ctx context.Context, accountId int) ([]string, error) {
+	rows, err := a.conn.QueryContext(ctx, getRolesByAccountIDQuery, accountId)
+	if err != nil {
+		a.logger.Error("failed to get account roles", "error", err)
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			a.logger.Error("failed to close rows", slog.String("error", err.Error()))
+		}
+	}(rows)
+
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		err := rows.Scan(&role)
+		if err != nil {
+			a.logger.Error("failed to scan role row", "error", err)
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
 }
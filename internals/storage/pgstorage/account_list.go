@@ -0,0 +1,311 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Listings take the filter as $1 to $9, a zero value disables its condition. Sorting and keyset
+// conditions are added after them, see accountListing.
+const (
+	accountListColumns = `
+		SELECT a.id, a.username, a.email, a.email_verified_at, a.disabled_at, a.created_at, a.updated_at
+		FROM gostarter_account a`
+
+	accountFilterCondition = `
+		WHERE (a.deleted_at IS NOT NULL) = ($1 = 'deleted')
+		  AND ($1 <> 'active' OR a.disabled_at IS NULL)
+		  AND ($1 <> 'disabled' OR a.disabled_at IS NOT NULL)
+		  AND ($1 <> 'unverified' OR a.email_verified_at IS NULL)
+		  AND ($2 = '' OR EXISTS (
+		      SELECT 1
+		      FROM gostarter_account_role ar
+		      JOIN gostarter_role r ON r.id = ar.role_id
+		      WHERE ar.account_id = a.id AND r.name = $2))
+		  AND ($3::timestamptz IS NULL OR a.created_at >= $3)
+		  AND ($4::timestamptz IS NULL OR a.created_at < $4)
+		  AND ($5::timestamptz IS NULL OR a.updated_at >= $5)
+		  AND ($6::timestamptz IS NULL OR a.updated_at < $6)
+		  AND ($7 = '' OR starts_with(a.email_normalized, $7))
+		  AND ($8 = '' OR starts_with(a.username_normalized, $8))
+		  AND ($9 = '' OR strpos(a.email_normalized, $9) > 0 OR strpos(a.username_normalized, $9) > 0)`
+
+	totalAccountsQuery = `
+		SELECT COUNT(*)
+		FROM gostarter_account a` + accountFilterCondition
+)
+
+// accountSortColumns are the only expressions listings are ordered by, the sort of a request
+// selects among them and never reaches the SQL. Text sorts byte by byte, like the memory store.
+var accountSortColumns = map[string]string{
+	domain.ACCOUNT_SORT_ID:         `a.id`,
+	domain.ACCOUNT_SORT_EMAIL:      `a.email_normalized COLLATE "C"`,
+	domain.ACCOUNT_SORT_USERNAME:   `a.username_normalized COLLATE "C"`,
+	domain.ACCOUNT_SORT_CREATED_AT: `a.created_at`,
+	domain.ACCOUNT_SORT_UPDATED_AT: `a.updated_at`,
+}
+
+// accountListing assembles a listing query from the constant fragments above, values only
+// travel as arguments
+type accountListing struct {
+	// sort ends with the id, which breaks ties
+	sort []domain.AccountSort
+	args []any
+}
+
+func newAccountListing(filter *domain.AccountFilter, sort []domain.AccountSort) *accountListing {
+	if !slices.ContainsFunc(sort, func(s domain.AccountSort) bool { return s.Field == domain.ACCOUNT_SORT_ID }) {
+		sort = append(slices.Clip(sort), domain.AccountSort{Field: domain.ACCOUNT_SORT_ID})
+	}
+
+	return &accountListing{
+		sort: sort,
+		args: []any{
+			filter.Status,
+			filter.Role,
+			nullableTime(filter.CreatedFrom),
+			nullableTime(filter.CreatedTo),
+			nullableTime(filter.UpdatedFrom),
+			nullableTime(filter.UpdatedTo),
+			utils.NormalizeIdentifier(filter.EmailPrefix),
+			utils.NormalizeIdentifier(filter.UsernamePrefix),
+			utils.NormalizeIdentifier(filter.Query),
+		},
+	}
+}
+
+// arg adds an argument and returns its placeholder
+func (l *accountListing) arg(value any) string {
+	l.args = append(l.args, value)
+	return fmt.Sprintf("$%d", len(l.args))
+}
+
+// orderBy returns the ORDER BY clause, reversed for backward pages
+func (l *accountListing) orderBy(reverse bool) string {
+	columns := make([]string, 0, len(l.sort))
+	for _, s := range l.sort {
+		direction := " ASC"
+		if s.Descending != reverse {
+			direction = " DESC"
+		}
+		columns = append(columns, accountSortColumns[s.Field]+direction)
+	}
+	return "\n\t\tORDER BY " + strings.Join(columns, ", ")
+}
+
+// keyset returns the condition matching the accounts after the cursor values in the order, or
+// before them for backward pages. For a sort by a then b it is `a > $x OR (a = $x AND b > $y)`.
+func (l *accountListing) keyset(values []any, reverse bool) string {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, l.arg(value))
+	}
+
+	alternatives := make([]string, 0, len(l.sort))
+	for i, s := range l.sort {
+		var terms []string
+		for j := 0; j < i; j++ {
+			terms = append(terms, accountSortColumns[l.sort[j].Field]+" = "+placeholders[j])
+		}
+
+		operator := " > "
+		if s.Descending != reverse {
+			operator = " < "
+		}
+		terms = append(terms, accountSortColumns[s.Field]+operator+placeholders[i])
+
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+	}
+	return "\n\t\t  AND (" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// cursor points at the account in the order, the sort key keeps it from being used with another one
+func (l *accountListing) cursor(account *domain.Account) string {
+	values := []any{domain.AccountSortKey(l.sort)}
+	for _, s := range l.sort {
+		values = append(values, accountSortValue(account, s.Field))
+	}
+	return utils.EncodeCursor(values...)
+}
+
+// decodeCursor returns the sort values of a cursor made for the same order
+func (l *accountListing) decodeCursor(cursor string) ([]any, error) {
+	var key string
+	dest := []any{&key}
+	for _, s := range l.sort {
+		dest = append(dest, accountSortDest(s.Field))
+	}
+
+	err := utils.DecodeCursor(cursor, dest...)
+	if err != nil || key != domain.AccountSortKey(l.sort) {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	values := make([]any, 0, len(l.sort))
+	for _, d := range dest[1:] {
+		switch value := d.(type) {
+		case *int:
+			values = append(values, *value)
+		case *string:
+			values = append(values, *value)
+		case *time.Time:
+			values = append(values, *value)
+		}
+	}
+	return values, nil
+}
+
+// accountSortValue returns the value of the account the field sorts by
+func accountSortValue(account *domain.Account, field string) any {
+	switch field {
+	case domain.ACCOUNT_SORT_EMAIL:
+		return utils.NormalizeIdentifier(account.Email)
+	case domain.ACCOUNT_SORT_USERNAME:
+		return utils.NormalizeIdentifier(account.Username)
+	case domain.ACCOUNT_SORT_CREATED_AT:
+		return account.CreatedAt
+	case domain.ACCOUNT_SORT_UPDATED_AT:
+		return account.UpdatedAt
+	default:
+		return account.Id
+	}
+}
+
+// accountSortDest returns a pointer to decode a sort value of the field into
+func accountSortDest(field string) any {
+	switch field {
+	case domain.ACCOUNT_SORT_EMAIL, domain.ACCOUNT_SORT_USERNAME:
+		return new(string)
+	case domain.ACCOUNT_SORT_CREATED_AT, domain.ACCOUNT_SORT_UPDATED_AT:
+		return new(time.Time)
+	default:
+		return new(int)
+	}
+}
+
+func (a *accountRepository) ListAccounts(
+	ctx context.Context,
+	filter *domain.AccountFilter,
+	sort []domain.AccountSort,
+	pagination *domain.Pagination,
+) ([]*domain.Account, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.ListAccounts")
+	defer span.End()
+
+	listing := newAccountListing(filter, sort)
+
+	var total int
+
+	err := a.conn.QueryRowContext(ctx, totalAccountsQuery, listing.args...).Scan(&total)
+	if err != nil {
+		a.logger.Error("failed to get total accounts", "error", err)
+		return nil, err
+	}
+
+	pagination.SetTotal(total)
+
+	query := accountListColumns + accountFilterCondition + listing.orderBy(false) +
+		"\n\t\tLIMIT " + listing.arg(pagination.Size) + " OFFSET " + listing.arg(pagination.GetOffset())
+
+	return a.queryAccounts(ctx, query, listing.args...)
+}
+
+func (a *accountRepository) ListAccountsByCursor(
+	ctx context.Context,
+	filter *domain.AccountFilter,
+	sort []domain.AccountSort,
+	pagination *domain.CursorPagination,
+) (*domain.AccountConnection, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.ListAccountsByCursor")
+	defer span.End()
+
+	listing := newAccountListing(filter, sort)
+
+	query := accountListColumns + accountFilterCondition
+
+	cursor := pagination.After
+	if pagination.Backward() {
+		cursor = pagination.Before
+	}
+	if cursor != "" {
+		values, err := listing.decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		query += listing.keyset(values, pagination.Backward())
+	}
+
+	// Backward pages are read from the cursor in reverse, one account more than asked for
+	// tells whether there are more
+	query += listing.orderBy(pagination.Backward()) + "\n\t\tLIMIT " + listing.arg(pagination.Limit()+1)
+
+	accounts, err := a.queryAccounts(ctx, query, listing.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if pagination.Backward() {
+		slices.Reverse(accounts)
+	}
+
+	connection := domain.NewAccountConnection(accounts, pagination, listing.cursor)
+
+	if pagination.WithTotal {
+		var total int
+		err = a.conn.QueryRowContext(ctx, totalAccountsQuery, listing.args[:9]...).Scan(&total)
+		if err != nil {
+			a.logger.Error("failed to get total accounts", "error", err)
+			return nil, err
+		}
+		connection.TotalCount = &total
+	}
+
+	return connection, nil
+}
+
+// queryAccounts runs a listing query and loads the roles of the accounts
+func (a *accountRepository) queryAccounts(ctx context.Context, query string, args ...any) ([]*domain.Account, error) {
+	rows, err := a.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		a.logger.Error("failed to list accounts", "error", err)
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			a.logger.Error("failed to close rows", slog.String("error", err.Error()))
+		}
+	}(rows)
+
+	accounts := []*domain.Account{}
+
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			a.logger.Error("failed to scan account row", "error", err)
+			return nil, err
+		}
+
+		accounts = append(accounts, account)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, account := range accounts {
+		account.Roles, err = a.getAccountRoles(ctx, account.Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return accounts, nil
+}
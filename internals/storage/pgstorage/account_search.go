@@ -0,0 +1,112 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/internals/domain"
+	"gostarter/pkg/utils"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+const (
+	// The similarity threshold only holds for the transaction of the search
+	setSearchSimilarityQuery = `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`
+
+	// $1 is the normalized query for trigram matching and $2 the tsquery of its words, each
+	// matching by prefix. Words that match rank by ts_rank, misspellings by their similarity.
+	searchAccountsQuery = `
+		SELECT a.id, a.username, a.email, a.email_verified_at, a.disabled_at, a.created_at, a.updated_at,
+		       ts_rank(a.search_vector, to_tsquery('simple', $2)) +
+		       greatest(word_similarity($1, a.username_normalized), word_similarity($1, a.email_normalized)) AS rank
+		FROM gostarter_account a
+		WHERE a.deleted_at IS NULL
+		  AND (a.search_vector @@ to_tsquery('simple', $2)
+		       OR $1 <% a.username_normalized
+		       OR $1 <% a.email_normalized)
+		ORDER BY rank DESC, a.id
+		LIMIT $3`
+)
+
+func (a *accountRepository) SearchAccounts(ctx context.Context, query string, limit int) ([]*domain.AccountSearchResult, error) {
+	ctx, span := a.tracer.Start(ctx, "AccountRepository.SearchAccounts")
+	defer span.End()
+
+	terms := utils.SearchTerms(query)
+	if len(terms) == 0 {
+		return nil, domain.ErrInvalidSearchQuery
+	}
+
+	// Terms hold only letters and digits, none of the tsquery operators
+	prefixes := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixes = append(prefixes, term+":*")
+	}
+
+	tx, err := a.conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		a.logger.Error("failed to begin transaction", "error", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				a.logger.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	threshold := strconv.FormatFloat(domain.ACCOUNT_SEARCH_SIMILARITY, 'f', -1, 64)
+	_, err = tx.ExecContext(ctx, setSearchSimilarityQuery, threshold)
+	if err != nil {
+		a.logger.Error("failed to set search similarity", "error", err)
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, searchAccountsQuery, strings.Join(terms, " "), strings.Join(prefixes, " & "), limit)
+	if err != nil {
+		a.logger.Error("failed to search accounts", "error", err)
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			a.logger.Error("failed to close rows", slog.String("error", err.Error()))
+		}
+	}(rows)
+
+	results := []*domain.AccountSearchResult{}
+
+	for rows.Next() {
+		result := &domain.AccountSearchResult{}
+
+		result.Account, err = scanAccount(rows, &result.Rank)
+		if err != nil {
+			a.logger.Error("failed to scan account search row", "error", err)
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		a.logger.Error("failed to commit transaction", "error", err)
+		return nil, err
+	}
+
+	for _, result := range results {
+		roles, err := a.getAccountRoles(ctx, result.Account.Id)
+		if err != nil {
+			return nil, err
+		}
+		result.Account.Roles = roles
+	}
+
+	return results, nil
+}
@@ -0,0 +1,143 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type accountTokenRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewAccountTokenRepository(container *infra.Container) domain.AccountTokenRepository {
+	return &accountTokenRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	createAccountTokenQuery = `
+		INSERT INTO gostarter_account_token (account_id, purpose, token_hash, payload, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	getAccountTokenByHashQuery = `
+		SELECT id, account_id, purpose, token_hash, payload, expires_at, used_at, created_at
+		FROM gostarter_account_token
+		WHERE purpose = $1 AND token_hash = $2`
+
+	markAccountTokenUsedQuery = `
+		UPDATE gostarter_account_token
+		SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL`
+
+	invalidateAccountTokensQuery = `
+		UPDATE gostarter_account_token
+		SET used_at = $1
+		WHERE account_id = $2 AND purpose = $3 AND used_at IS NULL`
+)
+
+func (t *accountTokenRepository) CreateAccountToken(ctx context.Context, token *domain.AccountToken) error {
+	ctx, span := t.tracer.Start(ctx, "AccountTokenRepository.CreateAccountToken")
+	defer span.End()
+
+	now := time.Now()
+
+	err := t.conn.QueryRowContext(
+		ctx,
+		createAccountTokenQuery,
+		token.AccountId,
+		token.Purpose,
+		token.TokenHash,
+		token.Payload,
+		token.ExpiresAt,
+		now,
+	).Scan(&token.Id)
+
+	if err != nil {
+		t.logger.Error("failed to create account token", "error", err)
+		return err
+	}
+
+	token.CreatedAt = now
+	return nil
+}
+
+func (t *accountTokenRepository) GetAccountTokenByHash(ctx context.Context, purpose, tokenHash string) (*domain.AccountToken, error) {
+	ctx, span := t.tracer.Start(ctx, "AccountTokenRepository.GetAccountTokenByHash")
+	defer span.End()
+
+	token := &domain.AccountToken{}
+	var usedAt sql.NullTime
+
+	err := t.conn.QueryRowContext(ctx, getAccountTokenByHashQuery, purpose, tokenHash).Scan(
+		&token.Id,
+		&token.AccountId,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.Payload,
+		&token.ExpiresAt,
+		&usedAt,
+		&token.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAccountTokenNotFound
+	}
+
+	if err != nil {
+		t.logger.Error("failed to get account token", "error", err)
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return token, nil
+}
+
+func (t *accountTokenRepository) MarkAccountTokenUsed(ctx context.Context, id int) error {
+	ctx, span := t.tracer.Start(ctx, "AccountTokenRepository.MarkAccountTokenUsed")
+	defer span.End()
+
+	res, err := t.conn.ExecContext(ctx, markAccountTokenUsedQuery, time.Now(), id)
+	if err != nil {
+		t.logger.Error("failed to mark account token used", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAccountTokenUsed
+	}
+
+	return nil
+}
+
+func (t *accountTokenRepository) InvalidateAccountTokens(ctx context.Context, accountId int, purpose string) error {
+	ctx, span := t.tracer.Start(ctx, "AccountTokenRepository.InvalidateAccountTokens")
+	defer span.End()
+
+	_, err := t.conn.ExecContext(ctx, invalidateAccountTokensQuery, time.Now(), accountId, purpose)
+	if err != nil {
+		t.logger.Error("failed to invalidate account tokens", "error", err)
+		return err
+	}
+
+	return nil
+}
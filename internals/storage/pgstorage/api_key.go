@@ -0,0 +1,189 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type apiKeyRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewAPIKeyRepository(container *infra.Container) domain.APIKeyRepository {
+	return &apiKeyRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+// Scopes are stored space separated, role names cannot contain a space
+const (
+	createAPIKeyQuery = `
+		INSERT INTO gostarter_api_key (account_id, name, prefix, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	getAPIKeyByHashQuery = `
+		SELECT id, account_id, name, prefix, token_hash, scopes, expires_at, last_used_at, created_at
+		FROM gostarter_api_key
+		WHERE token_hash = $1`
+
+	listAPIKeysQuery = `
+		SELECT id, account_id, name, prefix, token_hash, scopes, expires_at, last_used_at, created_at
+		FROM gostarter_api_key
+		WHERE account_id = $1
+		ORDER BY created_at`
+
+	deleteAPIKeyQuery = `
+		DELETE FROM gostarter_api_key
+		WHERE id = $1 AND account_id = $2`
+
+	touchAPIKeyQuery = `
+		UPDATE gostarter_api_key
+		SET last_used_at = $1
+		WHERE id = $2`
+)
+
+func (a *apiKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	ctx, span := a.tracer.Start(ctx, "APIKeyRepository.CreateAPIKey")
+	defer span.End()
+
+	now := time.Now()
+
+	err := a.conn.QueryRowContext(
+		ctx,
+		createAPIKeyQuery,
+		key.AccountId,
+		key.Name,
+		key.Prefix,
+		key.TokenHash,
+		strings.Join(key.Scopes, " "),
+		key.ExpiresAt,
+		now,
+	).Scan(&key.Id)
+
+	if err != nil {
+		a.logger.Error("failed to create api key", "error", err)
+		return err
+	}
+
+	key.CreatedAt = now
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	key := &domain.APIKey{}
+	var scopes string
+	var expiresAt, lastUsedAt sql.NullTime
+
+	err := row.Scan(
+		&key.Id,
+		&key.AccountId,
+		&key.Name,
+		&key.Prefix,
+		&key.TokenHash,
+		&scopes,
+		&expiresAt,
+		&lastUsedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Scopes = strings.Fields(scopes)
+	if expiresAt.Valid {
+		key.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return key, nil
+}
+
+func (a *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, tokenHash string) (*domain.APIKey, error) {
+	ctx, span := a.tracer.Start(ctx, "APIKeyRepository.GetAPIKeyByHash")
+	defer span.End()
+
+	key, err := scanAPIKey(a.conn.QueryRowContext(ctx, getAPIKeyByHashQuery, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		a.logger.Error("failed to get api key", "error", err)
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (a *apiKeyRepository) ListAPIKeys(ctx context.Context, accountId int) ([]*domain.APIKey, error) {
+	ctx, span := a.tracer.Start(ctx, "APIKeyRepository.ListAPIKeys")
+	defer span.End()
+
+	rows, err := a.conn.QueryContext(ctx, listAPIKeysQuery, accountId)
+	if err != nil {
+		a.logger.Error("failed to list api keys", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			a.logger.Error("failed to scan api key", "error", err)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (a *apiKeyRepository) DeleteAPIKey(ctx context.Context, accountId, id int) error {
+	ctx, span := a.tracer.Start(ctx, "APIKeyRepository.DeleteAPIKey")
+	defer span.End()
+
+	res, err := a.conn.ExecContext(ctx, deleteAPIKeyQuery, id, accountId)
+	if err != nil {
+		a.logger.Error("failed to delete api key", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+func (a *apiKeyRepository) TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	ctx, span := a.tracer.Start(ctx, "APIKeyRepository.TouchAPIKey")
+	defer span.End()
+
+	_, err := a.conn.ExecContext(ctx, touchAPIKeyQuery, usedAt, id)
+	if err != nil {
+		a.logger.Error("failed to update api key last use", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type auditRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewAuditRepository(container *infra.Container) domain.AuditRepository {
+	return &auditRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+// Filtered queries take the filter as $1 to $7, a zero value disables its condition
+const (
+	createAuditEventQuery = `
+		INSERT INTO gostarter_audit_event
+		    (action, actor_id, subject_id, impersonated_id, organization_id, ip, user_agent, request_id, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`
+
+	auditFilterCondition = `
+		WHERE ($1 = '' OR action = $1 OR starts_with(action, $1 || '.'))
+		  AND ($2 = 0 OR actor_id = $2)
+		  AND ($3 = 0 OR subject_id = $3)
+		  AND ($4 = 0 OR organization_id = $4)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
+		  AND ($7 = 0 OR actor_id = $7 OR subject_id = $7 OR impersonated_id = $7)`
+
+	totalAuditEventsQuery = `
+		SELECT COUNT(*)
+		FROM gostarter_audit_event` + auditFilterCondition
+
+	listAuditEventsQuery = `
+		SELECT id, action, actor_id, subject_id, impersonated_id, organization_id, ip, user_agent, request_id, metadata, created_at
+		FROM gostarter_audit_event` + auditFilterCondition + `
+		ORDER BY created_at DESC, id DESC
+		LIMIT $8 OFFSET $9`
+
+	streamAuditEventsQuery = `
+		SELECT id, action, actor_id, subject_id, impersonated_id, organization_id, ip, user_agent, request_id, metadata, created_at
+		FROM gostarter_audit_event` + auditFilterCondition + `
+		ORDER BY created_at, id`
+
+	deleteAuditEventsBeforeQuery = `
+		DELETE FROM gostarter_audit_event
+		WHERE created_at < $1`
+)
+
+func nullableId(id int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id > 0}
+}
+
+func nullableTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func auditFilterArgs(filter *domain.AuditFilter, args ...any) []any {
+	return append([]any{
+		filter.Action,
+		filter.ActorId,
+		filter.SubjectId,
+		filter.OrganizationId,
+		nullableTime(filter.From),
+		nullableTime(filter.To),
+		filter.AccountId,
+	}, args...)
+}
+
+func scanAuditEvent(row rowScanner) (*domain.AuditEvent, error) {
+	event := &domain.AuditEvent{}
+	var actorId, subjectId, impersonatedId, organizationId sql.NullInt64
+	var metadata []byte
+
+	err := row.Scan(
+		&event.Id,
+		&event.Action,
+		&actorId,
+		&subjectId,
+		&impersonatedId,
+		&organizationId,
+		&event.IP,
+		&event.UserAgent,
+		&event.RequestId,
+		&metadata,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.ActorId = int(actorId.Int64)
+	event.SubjectId = int(subjectId.Int64)
+	event.ImpersonatedId = int(impersonatedId.Int64)
+	event.OrganizationId = int(organizationId.Int64)
+
+	err = json.Unmarshal(metadata, &event.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (a *auditRepository) CreateEvent(ctx context.Context, event *domain.AuditEvent) error {
+	ctx, span := a.tracer.Start(ctx, "AuditRepository.CreateEvent")
+	defer span.End()
+
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	err = a.conn.QueryRowContext(ctx, createAuditEventQuery,
+		event.Action,
+		nullableId(event.ActorId),
+		nullableId(event.SubjectId),
+		nullableId(event.ImpersonatedId),
+		nullableId(event.OrganizationId),
+		event.IP,
+		event.UserAgent,
+		event.RequestId,
+		string(metadata),
+		event.CreatedAt,
+	).Scan(&event.Id)
+	if err != nil {
+		a.logger.Error("failed to create audit event", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (a *auditRepository) ListEvents(ctx context.Context, filter *domain.AuditFilter, pagination *domain.Pagination) ([]*domain.AuditEvent, error) {
+	ctx, span := a.tracer.Start(ctx, "AuditRepository.ListEvents")
+	defer span.End()
+
+	var total int
+
+	err := a.conn.QueryRowContext(ctx, totalAuditEventsQuery, auditFilterArgs(filter)...).Scan(&total)
+	if err != nil {
+		a.logger.Error("failed to get total audit events", "error", err)
+		return nil, err
+	}
+
+	pagination.SetTotal(total)
+
+	events := []*domain.AuditEvent{}
+	err = a.queryEvents(ctx, listAuditEventsQuery, auditFilterArgs(filter, pagination.Size, pagination.GetOffset()), func(event *domain.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (a *auditRepository) StreamEvents(ctx context.Context, filter *domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	ctx, span := a.tracer.Start(ctx, "AuditRepository.StreamEvents")
+	defer span.End()
+
+	return a.queryEvents(ctx, streamAuditEventsQuery, auditFilterArgs(filter), fn)
+}
+
+func (a *auditRepository) queryEvents(ctx context.Context, query string, args []any, fn func(*domain.AuditEvent) error) error {
+	rows, err := a.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		a.logger.Error("failed to list audit events", "error", err)
+		return err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			a.logger.Error("failed to close rows", slog.String("error", err.Error()))
+		}
+	}(rows)
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			a.logger.Error("failed to scan audit event row", "error", err)
+			return err
+		}
+
+		err = fn(event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (a *auditRepository) DeleteEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := a.tracer.Start(ctx, "AuditRepository.DeleteEventsBefore")
+	defer span.End()
+
+	result, err := a.conn.ExecContext(ctx, deleteAuditEventsBeforeQuery, before)
+	if err != nil {
+		a.logger.Error("failed to delete audit events", "error", err)
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
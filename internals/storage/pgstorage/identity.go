@@ -0,0 +1,93 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type identityRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewIdentityRepository(container *infra.Container) domain.IdentityRepository {
+	return &identityRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	createIdentityQuery = `
+		INSERT INTO gostarter_account_identity (account_id, provider, subject, email, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`
+
+	getIdentityQuery = `
+		SELECT id, account_id, provider, subject, COALESCE(email, ''), created_at, updated_at
+		FROM gostarter_account_identity
+		WHERE provider = $1 AND subject = $2`
+)
+
+func (i *identityRepository) CreateIdentity(ctx context.Context, identity *domain.Identity) error {
+	ctx, span := i.tracer.Start(ctx, "IdentityRepository.CreateIdentity")
+	defer span.End()
+
+	now := time.Now()
+
+	err := i.conn.QueryRowContext(
+		ctx,
+		createIdentityQuery,
+		identity.AccountId,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		now,
+		now,
+	).Scan(&identity.Id)
+
+	if err != nil {
+		i.logger.Error("failed to create identity", "error", err)
+		return err
+	}
+
+	identity.CreatedAt = now
+	identity.UpdatedAt = now
+	return nil
+}
+
+func (i *identityRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.Identity, error) {
+	ctx, span := i.tracer.Start(ctx, "IdentityRepository.GetIdentity")
+	defer span.End()
+
+	identity := &domain.Identity{}
+
+	err := i.conn.QueryRowContext(ctx, getIdentityQuery, provider, subject).Scan(
+		&identity.Id,
+		&identity.AccountId,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrIdentityNotFound
+	}
+
+	if err != nil {
+		i.logger.Error("failed to get identity", "error", err)
+		return nil, err
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,247 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type invitationRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewInvitationRepository(container *infra.Container) domain.InvitationRepository {
+	return &invitationRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+// Tenant scoped queries take the organization of the context as $1
+const (
+	deletePendingInvitationsQuery = `
+		DELETE FROM gostarter_organization_invitation
+		WHERE organization_id = $1 AND email = $2 AND accepted_at IS NULL`
+
+	createInvitationQuery = `
+		INSERT INTO gostarter_organization_invitation
+		    (organization_id, email, role, token_hash, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	getInvitationByHashQuery = `
+		SELECT id, organization_id, email, role, token_hash, invited_by, expires_at, accepted_at, created_at
+		FROM gostarter_organization_invitation
+		WHERE token_hash = $1`
+
+	listInvitationsQuery = `
+		SELECT id, organization_id, email, role, token_hash, invited_by, expires_at, accepted_at, created_at
+		FROM gostarter_organization_invitation
+		WHERE organization_id = $1
+		ORDER BY created_at DESC`
+
+	deleteInvitationQuery = `
+		DELETE FROM gostarter_organization_invitation
+		WHERE organization_id = $1 AND id = $2`
+
+	markInvitationAcceptedQuery = `
+		UPDATE gostarter_organization_invitation
+		SET accepted_at = $2, accepted_by = $3
+		WHERE id = $1 AND accepted_at IS NULL`
+)
+
+func (i *invitationRepository) CreateInvitation(ctx context.Context, invitation *domain.Invitation) error {
+	ctx, span := i.tracer.Start(ctx, "InvitationRepository.CreateInvitation")
+	defer span.End()
+
+	now := time.Now()
+
+	deleteArgs, err := tenantArgs(ctx, invitation.Email)
+	if err != nil {
+		return err
+	}
+
+	var invitedBy sql.NullInt64
+	if invitation.InvitedBy > 0 {
+		invitedBy = sql.NullInt64{Int64: int64(invitation.InvitedBy), Valid: true}
+	}
+	createArgs, err := tenantArgs(ctx,
+		invitation.Email,
+		invitation.Role,
+		invitation.TokenHash,
+		invitedBy,
+		invitation.ExpiresAt,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+
+	tx, err := i.conn.BeginTx(ctx, nil)
+	if err != nil {
+		i.logger.Error("failed to begin transaction", "error", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				i.logger.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	// A new invitation replaces the pending one, so only the latest link works
+	_, err = tx.ExecContext(ctx, deletePendingInvitationsQuery, deleteArgs...)
+	if err != nil {
+		i.logger.Error("failed to delete pending invitations", "error", err)
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, createInvitationQuery, createArgs...).Scan(&invitation.Id)
+	if err != nil {
+		i.logger.Error("failed to create invitation", "error", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		i.logger.Error("failed to commit transaction", "error", err)
+		return err
+	}
+
+	invitation.OrganizationId = createArgs[0].(int)
+	invitation.CreatedAt = now
+	return nil
+}
+
+func (i *invitationRepository) GetInvitationByHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "InvitationRepository.GetInvitationByHash")
+	defer span.End()
+
+	invitation, err := scanInvitation(i.conn.QueryRowContext(ctx, getInvitationByHashQuery, tokenHash))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvitationNotFound
+	}
+	if err != nil {
+		i.logger.Error("failed to get invitation by hash", "error", err)
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+func (i *invitationRepository) ListInvitations(ctx context.Context) ([]*domain.Invitation, error) {
+	ctx, span := i.tracer.Start(ctx, "InvitationRepository.ListInvitations")
+	defer span.End()
+
+	args, err := tenantArgs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := i.conn.QueryContext(ctx, listInvitationsQuery, args...)
+	if err != nil {
+		i.logger.Error("failed to list invitations", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []*domain.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+func (i *invitationRepository) DeleteInvitation(ctx context.Context, id int) error {
+	ctx, span := i.tracer.Start(ctx, "InvitationRepository.DeleteInvitation")
+	defer span.End()
+
+	args, err := tenantArgs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	res, err := i.conn.ExecContext(ctx, deleteInvitationQuery, args...)
+	if err != nil {
+		i.logger.Error("failed to delete invitation", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvitationNotFound
+	}
+
+	return nil
+}
+
+func (i *invitationRepository) MarkInvitationAccepted(ctx context.Context, id, accountId int) error {
+	ctx, span := i.tracer.Start(ctx, "InvitationRepository.MarkInvitationAccepted")
+	defer span.End()
+
+	res, err := i.conn.ExecContext(ctx, markInvitationAcceptedQuery, id, time.Now(), accountId)
+	if err != nil {
+		i.logger.Error("failed to mark invitation accepted", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvitationUsed
+	}
+
+	return nil
+}
+
+// scanInvitation scans the invitation columns in the order the invitation queries select them
+func scanInvitation(row rowScanner) (*domain.Invitation, error) {
+	invitation := &domain.Invitation{}
+	var invitedBy sql.NullInt64
+	var acceptedAt sql.NullTime
+
+	err := row.Scan(
+		&invitation.Id,
+		&invitation.OrganizationId,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitedBy,
+		&invitation.ExpiresAt,
+		&acceptedAt,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitedBy.Valid {
+		invitation.InvitedBy = int(invitedBy.Int64)
+	}
+	if acceptedAt.Valid {
+		invitation.AcceptedAt = &acceptedAt.Time
+	}
+
+	return invitation, nil
+}
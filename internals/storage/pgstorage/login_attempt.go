@@ -0,0 +1,157 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type lockoutRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewLockoutRepository(container *infra.Container) domain.LockoutRepository {
+	return &lockoutRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+// Failures are incremented in a single statement, so concurrent attempts are all counted
+const (
+	getLoginAttemptQuery = `
+		SELECT scope, key, failures, last_failure_at, locked_until
+		FROM gostarter_login_attempt
+		WHERE scope = $1 AND key = $2`
+
+	recordLoginFailureQuery = `
+		INSERT INTO gostarter_login_attempt (scope, key, failures, last_failure_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET failures = CASE
+				WHEN gostarter_login_attempt.last_failure_at < $4 THEN 1
+				ELSE gostarter_login_attempt.failures + 1
+			END,
+			last_failure_at = $3
+		RETURNING scope, key, failures, last_failure_at, locked_until`
+
+	lockLoginAttemptQuery = `
+		UPDATE gostarter_login_attempt
+		SET locked_until = $1
+		WHERE scope = $2 AND key = $3`
+
+	deleteLoginAttemptQuery = `
+		DELETE FROM gostarter_login_attempt
+		WHERE scope = $1 AND key = $2`
+
+	deleteStaleLoginAttemptsQuery = `
+		DELETE FROM gostarter_login_attempt
+		WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $1)`
+)
+
+func scanLoginAttempt(row rowScanner) (*domain.LoginAttempt, error) {
+	attempt := &domain.LoginAttempt{}
+	var lockedUntil sql.NullTime
+
+	err := row.Scan(
+		&attempt.Scope,
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&lockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		attempt.LockedUntil = &lockedUntil.Time
+	}
+
+	return attempt, nil
+}
+
+func (l *lockoutRepository) GetLoginAttempt(ctx context.Context, key domain.LockoutKey) (*domain.LoginAttempt, error) {
+	ctx, span := l.tracer.Start(ctx, "LockoutRepository.GetLoginAttempt")
+	defer span.End()
+
+	attempt, err := scanLoginAttempt(l.conn.QueryRowContext(ctx, getLoginAttemptQuery, key.Scope, key.Key))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrLoginAttemptNotFound
+	}
+	if err != nil {
+		l.logger.Error("failed to get login attempt", "error", err)
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (l *lockoutRepository) RecordLoginFailure(ctx context.Context, key domain.LockoutKey, now, windowStart time.Time) (*domain.LoginAttempt, error) {
+	ctx, span := l.tracer.Start(ctx, "LockoutRepository.RecordLoginFailure")
+	defer span.End()
+
+	attempt, err := scanLoginAttempt(l.conn.QueryRowContext(ctx, recordLoginFailureQuery, key.Scope, key.Key, now, windowStart))
+	if err != nil {
+		l.logger.Error("failed to record login failure", "error", err)
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (l *lockoutRepository) LockLoginAttempt(ctx context.Context, key domain.LockoutKey, until time.Time) error {
+	ctx, span := l.tracer.Start(ctx, "LockoutRepository.LockLoginAttempt")
+	defer span.End()
+
+	res, err := l.conn.ExecContext(ctx, lockLoginAttemptQuery, until, key.Scope, key.Key)
+	if err != nil {
+		l.logger.Error("failed to lock login attempt", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrLoginAttemptNotFound
+	}
+
+	return nil
+}
+
+func (l *lockoutRepository) DeleteLoginAttempt(ctx context.Context, key domain.LockoutKey) error {
+	ctx, span := l.tracer.Start(ctx, "LockoutRepository.DeleteLoginAttempt")
+	defer span.End()
+
+	_, err := l.conn.ExecContext(ctx, deleteLoginAttemptQuery, key.Scope, key.Key)
+	if err != nil {
+		l.logger.Error("failed to delete login attempt", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (l *lockoutRepository) DeleteStaleLoginAttempts(ctx context.Context, before time.Time) (int64, error) {
+	ctx, span := l.tracer.Start(ctx, "LockoutRepository.DeleteStaleLoginAttempts")
+	defer span.End()
+
+	res, err := l.conn.ExecContext(ctx, deleteStaleLoginAttemptsQuery, before)
+	if err != nil {
+		l.logger.Error("failed to delete stale login attempts", "error", err)
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
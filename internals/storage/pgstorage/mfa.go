@@ -0,0 +1,267 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type mfaRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewMFARepository(container *infra.Container) domain.MFARepository {
+	return &mfaRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	saveMFAQuery = `
+		INSERT INTO gostarter_account_mfa (account_id, secret, confirmed_at, created_at, updated_at)
+		VALUES ($1, $2, NULL, $3, $3)
+		ON CONFLICT (account_id) DO UPDATE
+		SET secret = EXCLUDED.secret, confirmed_at = NULL, updated_at = EXCLUDED.updated_at`
+
+	getMFAQuery = `
+		SELECT account_id, secret, last_used_step, confirmed_at, created_at, updated_at
+		FROM gostarter_account_mfa
+		WHERE account_id = $1`
+
+	confirmMFAQuery = `
+		UPDATE gostarter_account_mfa
+		SET confirmed_at = $1, updated_at = $1
+		WHERE account_id = $2 AND confirmed_at IS NULL`
+
+	useTOTPStepQuery = `
+		UPDATE gostarter_account_mfa
+		SET last_used_step = $1
+		WHERE account_id = $2 AND last_used_step < $1`
+
+	deleteMFAQuery = `
+		DELETE FROM gostarter_account_mfa
+		WHERE account_id = $1`
+
+	deleteRecoveryCodesQuery = `
+		DELETE FROM gostarter_account_recovery_code
+		WHERE account_id = $1`
+
+	createRecoveryCodeQuery = `
+		INSERT INTO gostarter_account_recovery_code (account_id, code_hash, created_at)
+		VALUES ($1, $2, $3)`
+
+	useRecoveryCodeQuery = `
+		UPDATE gostarter_account_recovery_code
+		SET used_at = $1
+		WHERE id = (
+			SELECT id FROM gostarter_account_recovery_code
+			WHERE account_id = $2 AND code_hash = $3 AND used_at IS NULL
+			LIMIT 1
+		) AND used_at IS NULL`
+)
+
+// SaveMFA stores a new unconfirmed enrollment, replacing the previous one of the account
+func (m *mfaRepository) SaveMFA(ctx context.Context, mfa *domain.AccountMFA) error {
+	ctx, span := m.tracer.Start(ctx, "MFARepository.SaveMFA")
+	defer span.End()
+
+	now := time.Now()
+
+	_, err := m.conn.ExecContext(ctx, saveMFAQuery, mfa.AccountId, mfa.Secret, now)
+	if err != nil {
+		m.logger.Error("failed to save mfa", "error", err)
+		return err
+	}
+
+	mfa.ConfirmedAt = nil
+	mfa.CreatedAt = now
+	mfa.UpdatedAt = now
+	return nil
+}
+
+func (m *mfaRepository) GetMFA(ctx context.Context, accountId int) (*domain.AccountMFA, error) {
+	ctx, span := m.tracer.Start(ctx, "MFARepository.GetMFA")
+	defer span.End()
+
+	mfa := &domain.AccountMFA{}
+	var confirmedAt sql.NullTime
+
+	err := m.conn.QueryRowContext(ctx, getMFAQuery, accountId).Scan(
+		&mfa.AccountId,
+		&mfa.Secret,
+		&mfa.LastUsedStep,
+		&confirmedAt,
+		&mfa.CreatedAt,
+		&mfa.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrMFANotEnrolled
+	}
+
+	if err != nil {
+		m.logger.Error("failed to get mfa", "error", err)
+		return nil, err
+	}
+
+	if confirmedAt.Valid {
+		mfa.ConfirmedAt = &confirmedAt.Time
+	}
+
+	return mfa, nil
+}
+
+func (m *mfaRepository) ConfirmMFA(ctx context.Context, accountId int, confirmedAt time.Time) error {
+	ctx, span := m.tracer.Start(ctx, "MFARepository.ConfirmMFA")
+	defer span.End()
+
+	res, err := m.conn.ExecContext(ctx, confirmMFAQuery, confirmedAt, accountId)
+	if err != nil {
+		m.logger.Error("failed to confirm mfa", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (m *mfaRepository) UseTOTPStep(ctx context.Context, accountId int, step int64) error {
+	ctx, span := m.tracer.Start(ctx, "MFARepository.UseTOTPStep")
+	defer span.End()
+
+	res, err := m.conn.ExecContext(ctx, useTOTPStepQuery, step, accountId)
+	if err != nil {
+		m.logger.Error("failed to use totp step", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (m *mfaRepository) DeleteMFA(ctx context.Context, accountId int) error {
+	ctx, span := m.tracer.Start(ctx, "MFARepository.DeleteMFA")
+	defer span.End()
+
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		m.logger.Error("failed to begin transaction", "error", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				m.logger.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, deleteRecoveryCodesQuery, accountId)
+	if err != nil {
+		m.logger.Error("failed to delete recovery codes", "error", err)
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, deleteMFAQuery, accountId)
+	if err != nil {
+		m.logger.Error("failed to delete mfa", "error", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		m.logger.Error("failed to commit transaction", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes invalidates every previous recovery code of the account
+func (m *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, accountId int, codeHashes []string) error {
+	ctx, span := m.tracer.Start(ctx, "MFARepository.ReplaceRecoveryCodes")
+	defer span.End()
+
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		m.logger.Error("failed to begin transaction", "error", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				m.logger.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, deleteRecoveryCodesQuery, accountId)
+	if err != nil {
+		m.logger.Error("failed to delete recovery codes", "error", err)
+		return err
+	}
+
+	now := time.Now()
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, createRecoveryCodeQuery, accountId, codeHash, now)
+		if err != nil {
+			m.logger.Error("failed to create recovery code", "error", err)
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		m.logger.Error("failed to commit transaction", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (m *mfaRepository) UseRecoveryCode(ctx context.Context, accountId int, codeHash string) error {
+	ctx, span := m.tracer.Start(ctx, "MFARepository.UseRecoveryCode")
+	defer span.End()
+
+	res, err := m.conn.ExecContext(ctx, useRecoveryCodeQuery, time.Now(), accountId, codeHash)
+	if err != nil {
+		m.logger.Error("failed to use recovery code", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
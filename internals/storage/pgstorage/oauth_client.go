@@ -0,0 +1,160 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type oauthClientRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewOAuthClientRepository(container *infra.Container) domain.OAuthClientRepository {
+	return &oauthClientRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+// Redirect uris and scopes are stored space separated, neither can contain a space
+const (
+	createOAuthClientQuery = `
+		INSERT INTO gostarter_oauth_client (client_id, secret_hash, name, redirect_uris, scopes, created_at, updated_at)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7)`
+
+	getOAuthClientQuery = `
+		SELECT client_id, COALESCE(secret_hash, ''), name, redirect_uris, scopes, created_at, updated_at
+		FROM gostarter_oauth_client
+		WHERE client_id = $1`
+
+	listOAuthClientsQuery = `
+		SELECT client_id, COALESCE(secret_hash, ''), name, redirect_uris, scopes, created_at, updated_at
+		FROM gostarter_oauth_client
+		ORDER BY created_at`
+
+	deleteOAuthClientQuery = `
+		DELETE FROM gostarter_oauth_client
+		WHERE client_id = $1`
+)
+
+func (o *oauthClientRepository) CreateClient(ctx context.Context, client *domain.OAuthClient) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthClientRepository.CreateClient")
+	defer span.End()
+
+	now := time.Now()
+
+	_, err := o.conn.ExecContext(
+		ctx,
+		createOAuthClientQuery,
+		client.ClientId,
+		client.SecretHash,
+		client.Name,
+		strings.Join(client.RedirectURIs, " "),
+		strings.Join(client.Scopes, " "),
+		now,
+		now,
+	)
+	if err != nil {
+		o.logger.Error("failed to create oauth client", "error", err)
+		return err
+	}
+
+	client.CreatedAt = now
+	client.UpdatedAt = now
+	return nil
+}
+
+func scanOAuthClient(row rowScanner) (*domain.OAuthClient, error) {
+	client := &domain.OAuthClient{}
+	var redirectURIs, scopes string
+
+	err := row.Scan(
+		&client.ClientId,
+		&client.SecretHash,
+		&client.Name,
+		&redirectURIs,
+		&scopes,
+		&client.CreatedAt,
+		&client.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client.RedirectURIs = strings.Fields(redirectURIs)
+	client.Scopes = strings.Fields(scopes)
+	return client, nil
+}
+
+func (o *oauthClientRepository) GetClient(ctx context.Context, clientId string) (*domain.OAuthClient, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthClientRepository.GetClient")
+	defer span.End()
+
+	client, err := scanOAuthClient(o.conn.QueryRowContext(ctx, getOAuthClientQuery, clientId))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOAuthClientNotFound
+	}
+
+	if err != nil {
+		o.logger.Error("failed to get oauth client", "error", err)
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (o *oauthClientRepository) ListClients(ctx context.Context) ([]*domain.OAuthClient, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthClientRepository.ListClients")
+	defer span.End()
+
+	rows, err := o.conn.QueryContext(ctx, listOAuthClientsQuery)
+	if err != nil {
+		o.logger.Error("failed to list oauth clients", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []*domain.OAuthClient
+	for rows.Next() {
+		client, err := scanOAuthClient(rows)
+		if err != nil {
+			o.logger.Error("failed to scan oauth client", "error", err)
+			return nil, err
+		}
+		clients = append(clients, client)
+	}
+
+	return clients, rows.Err()
+}
+
+func (o *oauthClientRepository) DeleteClient(ctx context.Context, clientId string) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthClientRepository.DeleteClient")
+	defer span.End()
+
+	res, err := o.conn.ExecContext(ctx, deleteOAuthClientQuery, clientId)
+	if err != nil {
+		o.logger.Error("failed to delete oauth client", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrOAuthClientNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,297 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type oauthGrantRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewOAuthGrantRepository(container *infra.Container) domain.OAuthGrantRepository {
+	return &oauthGrantRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	createOAuthCodeQuery = `
+		INSERT INTO gostarter_oauth_code (
+			code_hash, client_id, account_id, redirect_uri, scope, nonce,
+			code_challenge, code_challenge_method, expires_at, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`
+
+	getOAuthCodeByHashQuery = `
+		SELECT id, code_hash, client_id, account_id, redirect_uri, scope, COALESCE(nonce, ''),
+			code_challenge, code_challenge_method, expires_at, used_at, created_at
+		FROM gostarter_oauth_code
+		WHERE code_hash = $1`
+
+	markOAuthCodeUsedQuery = `
+		UPDATE gostarter_oauth_code
+		SET used_at = $1
+		WHERE id = $2 AND used_at IS NULL`
+
+	createOAuthTokenQuery = `
+		INSERT INTO gostarter_oauth_token (token_hash, kind, client_id, account_id, scope, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`
+
+	getOAuthTokenByHashQuery = `
+		SELECT id, token_hash, kind, client_id, account_id, scope, expires_at, revoked_at, created_at
+		FROM gostarter_oauth_token
+		WHERE token_hash = $1`
+
+	revokeOAuthTokenQuery = `
+		UPDATE gostarter_oauth_token
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL`
+
+	revokeOAuthTokensByGrantQuery = `
+		UPDATE gostarter_oauth_token
+		SET revoked_at = $1
+		WHERE client_id = $2 AND account_id = $3 AND revoked_at IS NULL`
+
+	getOAuthConsentScopeQuery = `
+		SELECT scope
+		FROM gostarter_oauth_consent
+		WHERE account_id = $1 AND client_id = $2`
+
+	saveOAuthConsentQuery = `
+		INSERT INTO gostarter_oauth_consent (account_id, client_id, scope, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (account_id, client_id) DO UPDATE
+		SET scope = EXCLUDED.scope, updated_at = EXCLUDED.updated_at`
+)
+
+func (o *oauthGrantRepository) CreateAuthorizationCode(ctx context.Context, code *domain.OAuthAuthorizationCode) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.CreateAuthorizationCode")
+	defer span.End()
+
+	now := time.Now()
+
+	err := o.conn.QueryRowContext(
+		ctx,
+		createOAuthCodeQuery,
+		code.CodeHash,
+		code.ClientId,
+		code.AccountId,
+		code.RedirectURI,
+		code.Scope,
+		code.Nonce,
+		code.CodeChallenge,
+		code.CodeChallengeMethod,
+		code.ExpiresAt,
+		now,
+	).Scan(&code.Id)
+
+	if err != nil {
+		o.logger.Error("failed to create authorization code", "error", err)
+		return err
+	}
+
+	code.CreatedAt = now
+	return nil
+}
+
+func (o *oauthGrantRepository) GetAuthorizationCodeByHash(ctx context.Context, codeHash string) (*domain.OAuthAuthorizationCode, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.GetAuthorizationCodeByHash")
+	defer span.End()
+
+	code := &domain.OAuthAuthorizationCode{}
+	var usedAt sql.NullTime
+
+	err := o.conn.QueryRowContext(ctx, getOAuthCodeByHashQuery, codeHash).Scan(
+		&code.Id,
+		&code.CodeHash,
+		&code.ClientId,
+		&code.AccountId,
+		&code.RedirectURI,
+		&code.Scope,
+		&code.Nonce,
+		&code.CodeChallenge,
+		&code.CodeChallengeMethod,
+		&code.ExpiresAt,
+		&usedAt,
+		&code.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOAuthCodeNotFound
+	}
+
+	if err != nil {
+		o.logger.Error("failed to get authorization code", "error", err)
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		code.UsedAt = &usedAt.Time
+	}
+
+	return code, nil
+}
+
+func (o *oauthGrantRepository) MarkAuthorizationCodeUsed(ctx context.Context, id int) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.MarkAuthorizationCodeUsed")
+	defer span.End()
+
+	res, err := o.conn.ExecContext(ctx, markOAuthCodeUsedQuery, time.Now(), id)
+	if err != nil {
+		o.logger.Error("failed to mark authorization code used", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrOAuthCodeUsed
+	}
+
+	return nil
+}
+
+func (o *oauthGrantRepository) CreateToken(ctx context.Context, token *domain.OAuthToken) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.CreateToken")
+	defer span.End()
+
+	now := time.Now()
+
+	err := o.conn.QueryRowContext(
+		ctx,
+		createOAuthTokenQuery,
+		token.TokenHash,
+		token.Kind,
+		token.ClientId,
+		token.AccountId,
+		token.Scope,
+		token.ExpiresAt,
+		now,
+	).Scan(&token.Id)
+
+	if err != nil {
+		o.logger.Error("failed to create oauth token", "error", err)
+		return err
+	}
+
+	token.CreatedAt = now
+	return nil
+}
+
+func (o *oauthGrantRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*domain.OAuthToken, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.GetTokenByHash")
+	defer span.End()
+
+	token := &domain.OAuthToken{}
+	var revokedAt sql.NullTime
+
+	err := o.conn.QueryRowContext(ctx, getOAuthTokenByHashQuery, tokenHash).Scan(
+		&token.Id,
+		&token.TokenHash,
+		&token.Kind,
+		&token.ClientId,
+		&token.AccountId,
+		&token.Scope,
+		&token.ExpiresAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOAuthTokenNotFound
+	}
+
+	if err != nil {
+		o.logger.Error("failed to get oauth token", "error", err)
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return token, nil
+}
+
+func (o *oauthGrantRepository) RevokeToken(ctx context.Context, id int) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.RevokeToken")
+	defer span.End()
+
+	res, err := o.conn.ExecContext(ctx, revokeOAuthTokenQuery, time.Now(), id)
+	if err != nil {
+		o.logger.Error("failed to revoke oauth token", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrOAuthTokenRevoked
+	}
+
+	return nil
+}
+
+func (o *oauthGrantRepository) RevokeTokensByGrant(ctx context.Context, clientId string, accountId int) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.RevokeTokensByGrant")
+	defer span.End()
+
+	_, err := o.conn.ExecContext(ctx, revokeOAuthTokensByGrantQuery, time.Now(), clientId, accountId)
+	if err != nil {
+		o.logger.Error("failed to revoke oauth tokens", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetConsentScope returns the scope the account already granted to the client, empty if none
+func (o *oauthGrantRepository) GetConsentScope(ctx context.Context, accountId int, clientId string) (string, error) {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.GetConsentScope")
+	defer span.End()
+
+	var scope string
+	err := o.conn.QueryRowContext(ctx, getOAuthConsentScopeQuery, accountId, clientId).Scan(&scope)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	if err != nil {
+		o.logger.Error("failed to get oauth consent", "error", err)
+		return "", err
+	}
+
+	return scope, nil
+}
+
+func (o *oauthGrantRepository) SaveConsent(ctx context.Context, accountId int, clientId, scope string) error {
+	ctx, span := o.tracer.Start(ctx, "OAuthGrantRepository.SaveConsent")
+	defer span.End()
+
+	_, err := o.conn.ExecContext(ctx, saveOAuthConsentQuery, accountId, clientId, scope, time.Now())
+	if err != nil {
+		o.logger.Error("failed to save oauth consent", "error", err)
+		return err
+	}
+
+	return nil
+}
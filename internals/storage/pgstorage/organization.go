@@ -0,0 +1,329 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type organizationRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewOrganizationRepository(container *infra.Container) domain.OrganizationRepository {
+	return &organizationRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+// Member queries are tenant scoped, $1 is the organization of the context
+const (
+	createOrganizationQuery = `
+		INSERT INTO gostarter_organization (name, slug, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (slug) DO NOTHING
+		RETURNING id`
+
+	getOrganizationByIDQuery = `
+		SELECT id, name, slug, created_at, updated_at
+		FROM gostarter_organization
+		WHERE id = $1`
+
+	listOrganizationsByAccountQuery = `
+		SELECT o.id, o.name, o.slug, o.created_at, o.updated_at
+		FROM gostarter_organization o
+		JOIN gostarter_organization_member m ON m.organization_id = o.id
+		WHERE m.account_id = $1
+		ORDER BY o.name`
+
+	addOrganizationMemberQuery = `
+		INSERT INTO gostarter_organization_member (organization_id, account_id, role, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`
+
+	getOrganizationMemberQuery = `
+		SELECT organization_id, account_id, role, created_at
+		FROM gostarter_organization_member
+		WHERE organization_id = $1 AND account_id = $2`
+
+	listOrganizationMembersQuery = `
+		SELECT organization_id, account_id, role, created_at
+		FROM gostarter_organization_member
+		WHERE organization_id = $1
+		ORDER BY created_at, account_id`
+
+	// An owner is only demoted or removed while another owner remains
+	updateOrganizationMemberRoleQuery = `
+		UPDATE gostarter_organization_member
+		SET role = $3
+		WHERE organization_id = $1 AND account_id = $2
+		  AND (role <> 'owner' OR $3 = 'owner' OR (
+			SELECT COUNT(*) FROM gostarter_organization_member
+			WHERE organization_id = $1 AND role = 'owner') > 1)`
+
+	removeOrganizationMemberQuery = `
+		DELETE FROM gostarter_organization_member
+		WHERE organization_id = $1 AND account_id = $2
+		  AND (role <> 'owner' OR (
+			SELECT COUNT(*) FROM gostarter_organization_member
+			WHERE organization_id = $1 AND role = 'owner') > 1)`
+)
+
+func (o *organizationRepository) CreateOrganization(ctx context.Context, organization *domain.Organization, ownerId int) error {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.CreateOrganization")
+	defer span.End()
+
+	tx, err := o.conn.BeginTx(ctx, nil)
+	if err != nil {
+		o.logger.Error("failed to begin transaction", "error", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				o.logger.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	now := time.Now()
+
+	err = tx.QueryRowContext(ctx, createOrganizationQuery, organization.Name, organization.Slug, now, now).
+		Scan(&organization.Id)
+	if err == sql.ErrNoRows {
+		err = domain.ErrOrganizationExists
+		return err
+	}
+	if err != nil {
+		o.logger.Error("failed to create organization", "error", err)
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, addOrganizationMemberQuery, organization.Id, ownerId, domain.ORG_ROLE_OWNER, now)
+	if err != nil {
+		o.logger.Error("failed to add organization owner", "error", err)
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		o.logger.Error("failed to commit transaction", "error", err)
+		return err
+	}
+
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+	return nil
+}
+
+func (o *organizationRepository) GetOrganizationByID(ctx context.Context, id int) (*domain.Organization, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.GetOrganizationByID")
+	defer span.End()
+
+	organization, err := scanOrganization(o.conn.QueryRowContext(ctx, getOrganizationByIDQuery, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrOrganizationNotFound
+	}
+	if err != nil {
+		o.logger.Error("failed to get organization by id", "error", err)
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+func (o *organizationRepository) ListOrganizationsByAccount(ctx context.Context, accountId int) ([]*domain.Organization, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.ListOrganizationsByAccount")
+	defer span.End()
+
+	rows, err := o.conn.QueryContext(ctx, listOrganizationsByAccountQuery, accountId)
+	if err != nil {
+		o.logger.Error("failed to list organizations", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []*domain.Organization{}
+	for rows.Next() {
+		organization, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, organization)
+	}
+
+	return organizations, rows.Err()
+}
+
+func (o *organizationRepository) GetMember(ctx context.Context, accountId int) (*domain.OrganizationMember, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.GetMember")
+	defer span.End()
+
+	args, err := tenantArgs(ctx, accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := scanOrganizationMember(o.conn.QueryRowContext(ctx, getOrganizationMemberQuery, args...))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotOrganizationMember
+	}
+	if err != nil {
+		o.logger.Error("failed to get organization member", "error", err)
+		return nil, err
+	}
+
+	return member, nil
+}
+
+func (o *organizationRepository) ListMembers(ctx context.Context) ([]*domain.OrganizationMember, error) {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.ListMembers")
+	defer span.End()
+
+	args, err := tenantArgs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := o.conn.QueryContext(ctx, listOrganizationMembersQuery, args...)
+	if err != nil {
+		o.logger.Error("failed to list organization members", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*domain.OrganizationMember{}
+	for rows.Next() {
+		member, err := scanOrganizationMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (o *organizationRepository) AddMember(ctx context.Context, member *domain.OrganizationMember) error {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.AddMember")
+	defer span.End()
+
+	now := time.Now()
+
+	args, err := tenantArgs(ctx, member.AccountId, member.Role, now)
+	if err != nil {
+		return err
+	}
+
+	res, err := o.conn.ExecContext(ctx, addOrganizationMemberQuery, args...)
+	if err != nil {
+		o.logger.Error("failed to add organization member", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAlreadyOrganizationMember
+	}
+
+	member.OrganizationId = args[0].(int)
+	member.CreatedAt = now
+	return nil
+}
+
+func (o *organizationRepository) UpdateMemberRole(ctx context.Context, accountId int, role string) error {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.UpdateMemberRole")
+	defer span.End()
+
+	args, err := tenantArgs(ctx, accountId, role)
+	if err != nil {
+		return err
+	}
+
+	res, err := o.conn.ExecContext(ctx, updateOrganizationMemberRoleQuery, args...)
+	if err != nil {
+		o.logger.Error("failed to update organization member", "error", err)
+		return err
+	}
+
+	return o.ownerGuardResult(ctx, res, accountId)
+}
+
+func (o *organizationRepository) RemoveMember(ctx context.Context, accountId int) error {
+	ctx, span := o.tracer.Start(ctx, "OrganizationRepository.RemoveMember")
+	defer span.End()
+
+	args, err := tenantArgs(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	res, err := o.conn.ExecContext(ctx, removeOrganizationMemberQuery, args...)
+	if err != nil {
+		o.logger.Error("failed to remove organization member", "error", err)
+		return err
+	}
+
+	return o.ownerGuardResult(ctx, res, accountId)
+}
+
+// ownerGuardResult tells a missing member from the last owner when a guarded change matched no row
+func (o *organizationRepository) ownerGuardResult(ctx context.Context, res sql.Result, accountId int) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	_, err = o.GetMember(ctx, accountId)
+	if err != nil {
+		return err
+	}
+
+	return domain.ErrLastOwner
+}
+
+// scanOrganization scans the organization columns in the order the organization queries select them
+func scanOrganization(row rowScanner) (*domain.Organization, error) {
+	organization := &domain.Organization{}
+
+	err := row.Scan(
+		&organization.Id,
+		&organization.Name,
+		&organization.Slug,
+		&organization.CreatedAt,
+		&organization.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+func scanOrganizationMember(row rowScanner) (*domain.OrganizationMember, error) {
+	member := &domain.OrganizationMember{}
+
+	err := row.Scan(&member.OrganizationId, &member.AccountId, &member.Role, &member.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
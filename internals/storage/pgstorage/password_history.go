@@ -0,0 +1,98 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type passwordHistoryRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewPasswordHistoryRepository(container *infra.Container) domain.PasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	addPasswordHistoryQuery = `
+		INSERT INTO gostarter_password_history (account_id, password_hash)
+		VALUES ($1, $2)`
+
+	listPasswordHistoryQuery = `
+		SELECT password_hash
+		FROM gostarter_password_history
+		WHERE account_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2`
+
+	prunePasswordHistoryQuery = `
+		DELETE FROM gostarter_password_history
+		WHERE account_id = $1 AND id NOT IN (
+			SELECT id
+			FROM gostarter_password_history
+			WHERE account_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		)`
+)
+
+func (p *passwordHistoryRepository) AddPasswordHistory(ctx context.Context, accountId int, passwordHash string) error {
+	ctx, span := p.tracer.Start(ctx, "PasswordHistoryRepository.AddPasswordHistory")
+	defer span.End()
+
+	_, err := p.conn.ExecContext(ctx, addPasswordHistoryQuery, accountId, passwordHash)
+	if err != nil {
+		p.logger.Error("failed to add password history", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (p *passwordHistoryRepository) ListPasswordHistory(ctx context.Context, accountId int, limit int) ([]string, error) {
+	ctx, span := p.tracer.Start(ctx, "PasswordHistoryRepository.ListPasswordHistory")
+	defer span.End()
+
+	rows, err := p.conn.QueryContext(ctx, listPasswordHistoryQuery, accountId, limit)
+	if err != nil {
+		p.logger.Error("failed to list password history", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		err = rows.Scan(&hash)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
+
+func (p *passwordHistoryRepository) PrunePasswordHistory(ctx context.Context, accountId int, keep int) error {
+	ctx, span := p.tracer.Start(ctx, "PasswordHistoryRepository.PrunePasswordHistory")
+	defer span.End()
+
+	_, err := p.conn.ExecContext(ctx, prunePasswordHistoryQuery, accountId, keep)
+	if err != nil {
+		p.logger.Error("failed to prune password history", "error", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type permissionRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewPermissionRepository(container *infra.Container) domain.PermissionRepository {
+	return &permissionRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+// The pgx driver encodes a string slice as a text array
+const (
+	listPermissionsQuery = `
+		SELECT id, name, description
+		FROM gostarter_permission
+		ORDER BY name`
+
+	getRolePermissionsQuery = `
+		SELECT r.name, p.name
+		FROM gostarter_role r
+		JOIN gostarter_role_permission rp ON rp.role_id = r.id
+		JOIN gostarter_permission p ON p.id = rp.permission_id
+		WHERE r.name = ANY($1)`
+)
+
+func (p *permissionRepository) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	ctx, span := p.tracer.Start(ctx, "PermissionRepository.ListPermissions")
+	defer span.End()
+
+	rows, err := p.conn.QueryContext(ctx, listPermissionsQuery)
+	if err != nil {
+		p.logger.Error("failed to list permissions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var permissions []*domain.Permission
+	for rows.Next() {
+		permission := &domain.Permission{}
+		err = rows.Scan(&permission.Id, &permission.Name, &permission.Description)
+		if err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+func (p *permissionRepository) GetRolePermissions(ctx context.Context, roles []string) (map[string][]string, error) {
+	ctx, span := p.tracer.Start(ctx, "PermissionRepository.GetRolePermissions")
+	defer span.End()
+
+	rows, err := p.conn.QueryContext(ctx, getRolePermissionsQuery, roles)
+	if err != nil {
+		p.logger.Error("failed to get role permissions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := make(map[string][]string, len(roles))
+	for rows.Next() {
+		var role, permission string
+		err = rows.Scan(&role, &permission)
+		if err != nil {
+			return nil, err
+		}
+		permissions[role] = append(permissions[role], permission)
+	}
+
+	return permissions, rows.Err()
+}
@@ -0,0 +1,204 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type refreshTokenRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewRefreshTokenRepository(container *infra.Container) domain.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	createRefreshTokenQuery = `
+		INSERT INTO gostarter_refresh_token (account_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	getRefreshTokenByHashQuery = `
+		SELECT id, account_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM gostarter_refresh_token
+		WHERE token_hash = $1`
+
+	revokeRefreshTokenQuery = `
+		UPDATE gostarter_refresh_token
+		SET revoked_at = $1
+		WHERE id = $2 AND revoked_at IS NULL`
+
+	revokeRefreshTokenFamilyQuery = `
+		UPDATE gostarter_refresh_token
+		SET revoked_at = $1
+		WHERE family_id = $2 AND revoked_at IS NULL`
+
+	revokeRefreshTokensByAccountQuery = `
+		UPDATE gostarter_refresh_token
+		SET revoked_at = $1
+		WHERE account_id = $2 AND revoked_at IS NULL`
+
+	listRefreshTokensByAccountQuery = `
+		SELECT id, account_id, family_id, token_hash, expires_at, revoked_at, created_at
+		FROM gostarter_refresh_token
+		WHERE account_id = $1
+		ORDER BY created_at DESC, id DESC`
+)
+
+func (t *refreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	ctx, span := t.tracer.Start(ctx, "RefreshTokenRepository.CreateRefreshToken")
+	defer span.End()
+
+	now := time.Now()
+
+	err := t.conn.QueryRowContext(
+		ctx,
+		createRefreshTokenQuery,
+		token.AccountId,
+		token.FamilyId,
+		token.TokenHash,
+		token.ExpiresAt,
+		now,
+	).Scan(&token.Id)
+
+	if err != nil {
+		t.logger.Error("failed to create refresh token", "error", err)
+		return err
+	}
+
+	token.CreatedAt = now
+	return nil
+}
+
+func (t *refreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	ctx, span := t.tracer.Start(ctx, "RefreshTokenRepository.GetRefreshTokenByHash")
+	defer span.End()
+
+	token, err := scanRefreshToken(t.conn.QueryRowContext(ctx, getRefreshTokenByHashQuery, tokenHash))
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefreshTokenNotFound
+	}
+
+	if err != nil {
+		t.logger.Error("failed to get refresh token", "error", err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func scanRefreshToken(row rowScanner) (*domain.RefreshToken, error) {
+	token := &domain.RefreshToken{}
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&token.Id,
+		&token.AccountId,
+		&token.FamilyId,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&revokedAt,
+		&token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return token, nil
+}
+
+func (t *refreshTokenRepository) RevokeRefreshToken(ctx context.Context, id int) error {
+	ctx, span := t.tracer.Start(ctx, "RefreshTokenRepository.RevokeRefreshToken")
+	defer span.End()
+
+	res, err := t.conn.ExecContext(ctx, revokeRefreshTokenQuery, time.Now(), id)
+	if err != nil {
+		t.logger.Error("failed to revoke refresh token", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// Another request rotated this token first
+	if rowsAffected == 0 {
+		return domain.ErrRefreshTokenReused
+	}
+
+	return nil
+}
+
+func (t *refreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyId string) error {
+	ctx, span := t.tracer.Start(ctx, "RefreshTokenRepository.RevokeRefreshTokenFamily")
+	defer span.End()
+
+	_, err := t.conn.ExecContext(ctx, revokeRefreshTokenFamilyQuery, time.Now(), familyId)
+	if err != nil {
+		t.logger.Error("failed to revoke refresh token family", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *refreshTokenRepository) RevokeRefreshTokensByAccount(ctx context.Context, accountId int) error {
+	ctx, span := t.tracer.Start(ctx, "RefreshTokenRepository.RevokeRefreshTokensByAccount")
+	defer span.End()
+
+	_, err := t.conn.ExecContext(ctx, revokeRefreshTokensByAccountQuery, time.Now(), accountId)
+	if err != nil {
+		t.logger.Error("failed to revoke account refresh tokens", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *refreshTokenRepository) ListRefreshTokensByAccount(ctx context.Context, accountId int) ([]*domain.RefreshToken, error) {
+	ctx, span := t.tracer.Start(ctx, "RefreshTokenRepository.ListRefreshTokensByAccount")
+	defer span.End()
+
+	rows, err := t.conn.QueryContext(ctx, listRefreshTokensByAccountQuery, accountId)
+	if err != nil {
+		t.logger.Error("failed to list account refresh tokens", "error", err)
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			t.logger.Error("failed to close rows", slog.String("error", err.Error()))
+		}
+	}(rows)
+
+	tokens := []*domain.RefreshToken{}
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			t.logger.Error("failed to scan refresh token row", "error", err)
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
@@ -0,0 +1,316 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type roleRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewRoleRepository(container *infra.Container) domain.RoleRepository {
+	return &roleRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	createRoleQuery = `
+		INSERT INTO gostarter_role (name, created_at, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO NOTHING
+		RETURNING id`
+
+	listRolesQuery = `
+		SELECT id, name, created_at, updated_at
+		FROM gostarter_role
+		ORDER BY name`
+
+	getRoleByNameQuery = `
+		SELECT id, name, created_at, updated_at
+		FROM gostarter_role
+		WHERE name = $1`
+
+	listRolePermissionsQuery = `
+		SELECT rp.role_id, p.name
+		FROM gostarter_role_permission rp
+		JOIN gostarter_permission p ON p.id = rp.permission_id
+		ORDER BY p.name`
+
+	getPermissionIDByNameQuery = `
+		SELECT id FROM gostarter_permission WHERE name = $1`
+
+	grantRolePermissionQuery = `
+		INSERT INTO gostarter_role_permission (role_id, permission_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+
+	revokeRolePermissionQuery = `
+		DELETE FROM gostarter_role_permission rp
+		USING gostarter_role r, gostarter_permission p
+		WHERE rp.role_id = r.id AND rp.permission_id = p.id
+		  AND r.name = $1 AND p.name = $2`
+
+	assignAccountRoleQuery = `
+		INSERT INTO gostarter_account_role (account_id, role_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+
+	unassignAccountRoleQuery = `
+		DELETE FROM gostarter_account_role ar
+		USING gostarter_role r
+		WHERE ar.role_id = r.id AND ar.account_id = $1 AND r.name = $2`
+)
+
+func (r *roleRepository) CreateRole(ctx context.Context, role *domain.Role) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.CreateRole")
+	defer span.End()
+
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.Error("failed to begin transaction", "error", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				r.logger.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	now := time.Now()
+
+	err = tx.QueryRowContext(ctx, createRoleQuery, role.Name, now, now).Scan(&role.Id)
+	if err == sql.ErrNoRows {
+		err = domain.ErrRoleExists
+		return err
+	}
+	if err != nil {
+		r.logger.Error("failed to create role", "error", err)
+		return err
+	}
+
+	for _, permission := range role.Permissions {
+		var permissionID int
+		err = tx.QueryRowContext(ctx, getPermissionIDByNameQuery, permission).Scan(&permissionID)
+		if err == sql.ErrNoRows {
+			err = domain.ErrPermissionNotFound
+			return err
+		}
+		if err != nil {
+			r.logger.Error("failed to get permission id", "error", err, "permission", permission)
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, grantRolePermissionQuery, role.Id, permissionID, now)
+		if err != nil {
+			r.logger.Error("failed to grant permission to role", "error", err, "permission", permission)
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		r.logger.Error("failed to commit transaction", "error", err)
+		return err
+	}
+
+	role.CreatedAt = now
+	role.UpdatedAt = now
+	return nil
+}
+
+func (r *roleRepository) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.ListRoles")
+	defer span.End()
+
+	rows, err := r.conn.QueryContext(ctx, listRolesQuery)
+	if err != nil {
+		r.logger.Error("failed to list roles", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []*domain.Role
+	byID := make(map[int]*domain.Role)
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+		byID[role.Id] = role
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	permissionRows, err := r.conn.QueryContext(ctx, listRolePermissionsQuery)
+	if err != nil {
+		r.logger.Error("failed to list role permissions", "error", err)
+		return nil, err
+	}
+	defer permissionRows.Close()
+
+	for permissionRows.Next() {
+		var roleID int
+		var permission string
+		err = permissionRows.Scan(&roleID, &permission)
+		if err != nil {
+			return nil, err
+		}
+		if role, ok := byID[roleID]; ok {
+			role.Permissions = append(role.Permissions, permission)
+		}
+	}
+
+	return roles, permissionRows.Err()
+}
+
+func (r *roleRepository) GetRoleByName(ctx context.Context, name string) (*domain.Role, error) {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.GetRoleByName")
+	defer span.End()
+
+	role, err := scanRole(r.conn.QueryRowContext(ctx, getRoleByNameQuery, name))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRoleNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get role by name", "error", err)
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (r *roleRepository) GrantPermission(ctx context.Context, role, permission string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.GrantPermission")
+	defer span.End()
+
+	stored, err := r.GetRoleByName(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	var permissionID int
+	err = r.conn.QueryRowContext(ctx, getPermissionIDByNameQuery, permission).Scan(&permissionID)
+	if err == sql.ErrNoRows {
+		return domain.ErrPermissionNotFound
+	}
+	if err != nil {
+		r.logger.Error("failed to get permission id", "error", err)
+		return err
+	}
+
+	res, err := r.conn.ExecContext(ctx, grantRolePermissionQuery, stored.Id, permissionID, time.Now())
+	if err != nil {
+		r.logger.Error("failed to grant permission to role", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrPermissionAlreadyGranted
+	}
+
+	return nil
+}
+
+func (r *roleRepository) RevokePermission(ctx context.Context, role, permission string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.RevokePermission")
+	defer span.End()
+
+	res, err := r.conn.ExecContext(ctx, revokeRolePermissionQuery, role, permission)
+	if err != nil {
+		r.logger.Error("failed to revoke permission from role", "error", err)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrPermissionNotGranted
+	}
+
+	return nil
+}
+
+func (r *roleRepository) AssignRole(ctx context.Context, accountId int, role string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.AssignRole")
+	defer span.End()
+
+	stored, err := r.GetRoleByName(ctx, role)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.conn.ExecContext(ctx, assignAccountRoleQuery, accountId, stored.Id, time.Now())
+	if err != nil {
+		r.logger.Error("failed to assign role to account", "error", err, "accountId", accountId)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRoleAlreadyAssigned
+	}
+
+	return nil
+}
+
+func (r *roleRepository) UnassignRole(ctx context.Context, accountId int, role string) error {
+	ctx, span := r.tracer.Start(ctx, "RoleRepository.UnassignRole")
+	defer span.End()
+
+	res, err := r.conn.ExecContext(ctx, unassignAccountRoleQuery, accountId, role)
+	if err != nil {
+		r.logger.Error("failed to unassign role from account", "error", err, "accountId", accountId)
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrRoleNotAssigned
+	}
+
+	return nil
+}
+
+// scanRole scans the role columns in the order the role queries select them
+func scanRole(row rowScanner) (*domain.Role, error) {
+	role := &domain.Role{Permissions: []string{}}
+
+	err := row.Scan(&role.Id, &role.Name, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
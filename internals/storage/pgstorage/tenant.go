@@ -0,0 +1,17 @@
+package pgstorage
+
+import (
+	"context"
+	"gostarter/internals/domain"
+)
+
+// tenantArgs prepends the organization the context is scoped to, tenant scoped queries
+// filter on organization_id = $1 so they cannot read or write across organizations
+func tenantArgs(ctx context.Context, args ...any) ([]any, error) {
+	organizationId, ok := domain.TenantFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrNoTenant
+	}
+
+	return append([]any{organizationId}, args...), nil
+}
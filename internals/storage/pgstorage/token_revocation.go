@@ -0,0 +1,119 @@
+package pgstorage
+
+import (
+	"context"
+	"database/sql"
+	"gostarter/infra"
+	"gostarter/internals/domain"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tokenRevocationRepository struct {
+	conn   *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+func NewTokenRevocationRepository(container *infra.Container) domain.TokenRevocationRepository {
+	return &tokenRevocationRepository{
+		conn:   container.DbConn,
+		logger: container.Logger,
+		tracer: container.Tracer,
+	}
+}
+
+const (
+	revokeTokenQuery = `
+		INSERT INTO gostarter_revoked_token (jti, account_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (jti) DO NOTHING`
+
+	revokeAccountTokensQuery = `
+		INSERT INTO gostarter_account_revocation (account_id, revoked_before, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (account_id) DO UPDATE
+		SET revoked_before = EXCLUDED.revoked_before, expires_at = EXCLUDED.expires_at`
+
+	isTokenRevokedQuery = `
+		SELECT EXISTS (
+			SELECT 1 FROM gostarter_revoked_token WHERE jti = $1
+		) OR EXISTS (
+			SELECT 1 FROM gostarter_account_revocation WHERE account_id = $2 AND revoked_before > $3
+		)`
+
+	pruneRevokedTokensQuery = `
+		DELETE FROM gostarter_revoked_token WHERE expires_at < $1`
+
+	pruneAccountRevocationsQuery = `
+		DELETE FROM gostarter_account_revocation WHERE expires_at < $1`
+)
+
+func (t *tokenRevocationRepository) RevokeToken(ctx context.Context, token *domain.RevokedToken) error {
+	ctx, span := t.tracer.Start(ctx, "TokenRevocationRepository.RevokeToken")
+	defer span.End()
+
+	_, err := t.conn.ExecContext(ctx, revokeTokenQuery,
+		token.Jti,
+		token.AccountId,
+		token.ExpiresAt,
+		time.Now(),
+	)
+	if err != nil {
+		t.logger.Error("failed to revoke token", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *tokenRevocationRepository) RevokeAccountTokens(ctx context.Context, accountId int, revokedBefore, expiresAt time.Time) error {
+	ctx, span := t.tracer.Start(ctx, "TokenRevocationRepository.RevokeAccountTokens")
+	defer span.End()
+
+	_, err := t.conn.ExecContext(ctx, revokeAccountTokensQuery, accountId, revokedBefore, expiresAt)
+	if err != nil {
+		t.logger.Error("failed to revoke account tokens", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (t *tokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string, accountId int, issuedAt time.Time) (bool, error) {
+	ctx, span := t.tracer.Start(ctx, "TokenRevocationRepository.IsTokenRevoked")
+	defer span.End()
+
+	var revoked bool
+	err := t.conn.QueryRowContext(ctx, isTokenRevokedQuery, jti, accountId, issuedAt).Scan(&revoked)
+	if err != nil {
+		t.logger.Error("failed to check token revocation", "error", err)
+		return false, err
+	}
+
+	return revoked, nil
+}
+
+func (t *tokenRevocationRepository) PruneExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := t.tracer.Start(ctx, "TokenRevocationRepository.PruneExpired")
+	defer span.End()
+
+	var pruned int64
+	for _, query := range []string{pruneRevokedTokensQuery, pruneAccountRevocationsQuery} {
+		res, err := t.conn.ExecContext(ctx, query, now)
+		if err != nil {
+			t.logger.Error("failed to prune revoked tokens", "error", err)
+			return pruned, err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return pruned, err
+		}
+		pruned += rowsAffected
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,162 @@
+// Package breach checks passwords against a local corpus of SHA-1 hashes of breached passwords.
+//
+// The corpus is either a directory of range files, named by the first five hex characters of
+// the hash and holding `SUFFIX:COUNT` lines, or a single file of `HASH:COUNT` lines ordered by
+// hash. Both are produced by the Pwned Passwords downloader, the ordered file is searched
+// without reading it into memory.
+package breach
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	prefixLength = 5
+	// scanThreshold is the size of the region of the ordered file that is read line by line
+	scanThreshold = 4096
+)
+
+type Corpus struct {
+	path string
+	dir  bool
+}
+
+// Open returns the corpus at path, a directory of range files or an ordered hash file
+func Open(path string) (*Corpus, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Corpus{path: path, dir: info.IsDir()}, nil
+}
+
+// Hash returns the uppercase hex SHA-1 of the password, as listed in the corpus
+func Hash(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// Contains reports whether the password appears in the corpus
+func (c *Corpus) Contains(password string) (bool, error) {
+	hash := Hash(password)
+	if c.dir {
+		return c.containsInRange(hash)
+	}
+	return c.containsInFile(hash)
+}
+
+func (c *Corpus) containsInRange(hash string) (bool, error) {
+	file, err := os.Open(filepath.Join(c.path, hash[:prefixLength]+".txt"))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	suffix := hash[prefixLength:]
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if strings.EqualFold(lineHash(scanner.Text()), suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// containsInFile binary searches the ordered file by byte offset, then scans the remaining region
+func (c *Corpus) containsInFile(hash string) (bool, error) {
+	file, err := os.Open(c.path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	// The first full line after lo is before the hash, the first full line after hi is not
+	lo, hi := int64(0), info.Size()
+	for hi-lo > scanThreshold {
+		mid := lo + (hi-lo)/2
+
+		line, err := firstLineAfter(file, mid)
+		if err != nil {
+			return false, err
+		}
+		if line != "" && strings.ToUpper(lineHash(line)) < hash {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, lo, info.Size()-lo))
+	if lo > 0 {
+		// The partial line at lo sorts before the first full line after it
+		_, err = reader.ReadString('\n')
+		if err != nil {
+			return false, ignoreEOF(err)
+		}
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			current := strings.ToUpper(lineHash(line))
+			if current == hash {
+				return true, nil
+			}
+			if current > hash {
+				return false, nil
+			}
+		}
+		if err != nil {
+			return false, ignoreEOF(err)
+		}
+	}
+}
+
+// firstLineAfter returns the first line that starts after offset, empty at the end of the file
+func firstLineAfter(file *os.File, offset int64) (string, error) {
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, 1<<62))
+
+	_, err := reader.ReadString('\n')
+	if err != nil {
+		return "", ignoreEOF(err)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return line, nil
+}
+
+// lineHash returns the hash of a `HASH:COUNT` line
+func lineHash(line string) string {
+	line = strings.TrimSpace(line)
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
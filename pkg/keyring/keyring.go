@@ -0,0 +1,336 @@
+// Package keyring keeps the ECDSA keys that sign and verify JWTs.
+//
+// A keyring directory holds one PEM file per key and a keyring.json manifest naming the
+// active signing key. Every other key in the manifest only verifies tokens, until its
+// retire_at time passes. A rotated key is named next and only verifies tokens until its
+// active_from time, so every server trusts it before any server signs with it. Verification only keys may be public key PEM files, so keys of
+// another deployment can be trusted without sharing the private key.
+package keyring
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adharshmk96/goutils/token"
+	"gostarter/pkg/utils"
+)
+
+const ManifestFile = "keyring.json"
+
+var (
+	ErrNoActiveKey    = errors.New("keyring has no active signing key")
+	ErrKeyringExists  = errors.New("keyring already exists")
+	ErrUnsupportedKey = errors.New("unsupported key file")
+	ErrRotationDue    = errors.New("the previous rotation is not active yet")
+)
+
+type Key struct {
+	Kid        string
+	PrivateKey *ecdsa.PrivateKey // nil for verification only keys loaded from a public key
+	PublicKey  *ecdsa.PublicKey
+	CreatedAt  time.Time
+	ActiveFrom *time.Time // set on the next key, which signs from then on
+	RetireAt   *time.Time
+}
+
+type Keyring struct {
+	active *Key
+	keys   []*Key
+}
+
+type manifest struct {
+	Active string        `json:"active"`
+	Next   string        `json:"next,omitempty"`
+	Keys   []manifestKey `json:"keys"`
+}
+
+type manifestKey struct {
+	Kid        string     `json:"kid"`
+	File       string     `json:"file"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ActiveFrom *time.Time `json:"active_from,omitempty"`
+	RetireAt   *time.Time `json:"retire_at,omitempty"`
+}
+
+// activeKid returns the key id signing at now, the next key once its active_from time passed
+func (m *manifest) activeKid(now time.Time) string {
+	if m.Next == "" {
+		return m.Active
+	}
+
+	for _, entry := range m.Keys {
+		if entry.Kid == m.Next && entry.ActiveFrom != nil && !now.Before(*entry.ActiveFrom) {
+			return m.Next
+		}
+	}
+	return m.Active
+}
+
+// FromKeyPair returns a keyring with the private key as its only, active key
+func FromKeyPair(privateKey *ecdsa.PrivateKey) (*Keyring, error) {
+	key, err := newKey(privateKey, &privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keyring{active: key, keys: []*Key{key}}, nil
+}
+
+// Exists reports whether dir holds a keyring manifest
+func Exists(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ManifestFile))
+	return err == nil
+}
+
+// Load reads the keyring in dir, leaving out keys retired before now
+func Load(dir string, now time.Time) (*Keyring, error) {
+	m, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	active := m.activeKid(now)
+
+	ring := &Keyring{}
+	for _, entry := range m.Keys {
+		if entry.RetireAt != nil && !now.Before(*entry.RetireAt) {
+			continue
+		}
+
+		key, err := loadKey(filepath.Join(dir, entry.File))
+		if err != nil {
+			return nil, err
+		}
+		key.CreatedAt = entry.CreatedAt
+		key.ActiveFrom = entry.ActiveFrom
+		key.RetireAt = entry.RetireAt
+
+		if entry.Kid == active {
+			if key.PrivateKey == nil {
+				return nil, ErrNoActiveKey
+			}
+			ring.active = key
+		}
+		ring.keys = append(ring.keys, key)
+	}
+
+	if ring.active == nil {
+		return nil, ErrNoActiveKey
+	}
+
+	return ring, nil
+}
+
+// Active returns the key that signs new tokens
+func (k *Keyring) Active() *Key {
+	return k.active
+}
+
+// Get returns the key with the key id, it is found while the key is not retired
+func (k *Keyring) Get(kid string) (*Key, bool) {
+	for _, key := range k.keys {
+		if key.Kid == kid {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns every key that verifies tokens, including the active one
+func (k *Keyring) Keys() []*Key {
+	return k.keys
+}
+
+// Import starts a keyring in dir with an existing private key as the active key
+func Import(dir string, privateKey *ecdsa.PrivateKey, now time.Time) (*Key, error) {
+	if Exists(dir) {
+		return nil, ErrKeyringExists
+	}
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	key, entry, err := writeKey(dir, privateKey, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, writeManifest(dir, &manifest{Active: key.Kid, Keys: []manifestKey{entry}})
+}
+
+// Rotate generates a new signing key that becomes active after the delay, until then it
+// only verifies tokens. The previous active key keeps verifying tokens for the grace period
+// after that, keys whose grace period ended are removed.
+func Rotate(dir string, grace, delay time.Duration, now time.Time) (*Key, error) {
+	m := &manifest{}
+	if Exists(dir) {
+		var err error
+		m, err = readManifest(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A next key that is not active yet may already sign on some servers
+	if m.Next != "" && m.activeKid(now) != m.Next {
+		return nil, ErrRotationDue
+	}
+	m.Active = m.activeKid(now)
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key, entry, err := writeKey(dir, privateKey, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without an active key nothing signs, the first key is active at once
+	if m.Active == "" {
+		delay = 0
+	}
+	activeFrom := now.Add(delay)
+	key.ActiveFrom = &activeFrom
+	entry.ActiveFrom = &activeFrom
+
+	retireAt := activeFrom.Add(grace)
+	var removed []string
+	var keys []manifestKey
+	for _, existing := range m.Keys {
+		if existing.RetireAt != nil && !now.Before(*existing.RetireAt) {
+			removed = append(removed, existing.File)
+			continue
+		}
+		if existing.Kid == m.Active {
+			existing.RetireAt = &retireAt
+		}
+		keys = append(keys, existing)
+	}
+
+	err = writeManifest(dir, &manifest{Active: m.Active, Next: key.Kid, Keys: append(keys, entry)})
+	if err != nil {
+		return nil, err
+	}
+
+	// Files are removed once the manifest no longer lists them
+	for _, file := range removed {
+		_ = os.Remove(filepath.Join(dir, file))
+	}
+
+	return key, nil
+}
+
+func newKey(privateKey *ecdsa.PrivateKey, publicKey *ecdsa.PublicKey) (*Key, error) {
+	kid, err := utils.ECDSAThumbprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		Kid:        kid,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
+// loadKey reads a private key, or a public key for verification only keys
+func loadKey(path string) (*Key, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, ErrUnsupportedKey
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		privateKey, err := token.LoadPrivateKey(content)
+		if err != nil {
+			return nil, err
+		}
+		return newKey(privateKey, &privateKey.PublicKey)
+	case "PUBLIC KEY":
+		publicKey, err := token.LoadPublicKey(content)
+		if err != nil {
+			return nil, err
+		}
+		if publicKey == nil {
+			return nil, ErrUnsupportedKey
+		}
+		return newKey(nil, publicKey)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+}
+
+func writeKey(dir string, privateKey *ecdsa.PrivateKey, now time.Time) (*Key, manifestKey, error) {
+	key, err := newKey(privateKey, &privateKey.PublicKey)
+	if err != nil {
+		return nil, manifestKey{}, err
+	}
+	key.CreatedAt = now
+
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return nil, manifestKey{}, err
+	}
+
+	file := key.Kid + ".pem"
+	err = os.WriteFile(filepath.Join(dir, file), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600)
+	if err != nil {
+		return nil, manifestKey{}, err
+	}
+
+	return key, manifestKey{Kid: key.Kid, File: file, CreatedAt: now}, nil
+}
+
+func readManifest(dir string) (*manifest, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return nil, err
+	}
+
+	m := &manifest{}
+	err = json.Unmarshal(content, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// writeManifest replaces the manifest atomically, so a reloading server never reads a partial file
+func writeManifest(dir string, m *manifest) error {
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(dir, ManifestFile+".tmp")
+	err = os.WriteFile(tmp, content, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(dir, ManifestFile))
+}
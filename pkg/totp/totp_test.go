@@ -0,0 +1,96 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors, "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCodeRFC6238Vectors(t *testing.T) {
+	// RFC 6238 appendix B lists 8 digit codes, the 6 digit codes are their last digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := GenerateCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateCode(%d): %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("GenerateCode(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestGenerateCodeLowercaseSecret(t *testing.T) {
+	upper, err := GenerateCode(rfcSecret, time.Unix(59, 0))
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	lower, err := GenerateCode("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", time.Unix(59, 0))
+	if err != nil {
+		t.Fatalf("GenerateCode with a lowercase secret: %v", err)
+	}
+	if upper != lower {
+		t.Fatalf("lowercase secret code = %s, want %s", lower, upper)
+	}
+}
+
+func TestValidateStepSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"current step", 0, true},
+		{"previous step", -Period, true},
+		{"next step", Period, true},
+		{"two steps behind", -2 * Period, false},
+		{"two steps ahead", 2 * Period, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := GenerateCode(rfcSecret, now.Add(tt.offset))
+			if err != nil {
+				t.Fatalf("GenerateCode: %v", err)
+			}
+
+			got, ok := ValidateStep(rfcSecret, code, now, 1)
+			if ok != tt.ok {
+				t.Fatalf("ValidateStep ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && got != step+int64(tt.offset/Period) {
+				t.Fatalf("ValidateStep step = %d, want %d", got, step+int64(tt.offset/Period))
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	for _, code := range []string{"", "28708", "2870820", "abcdef", "287083"} {
+		if Validate(rfcSecret, code, now, 1) {
+			t.Errorf("Validate(%q) = true, want false", code)
+		}
+	}
+
+	if !Validate(rfcSecret, " 287082 ", now, 1) {
+		t.Errorf("Validate with surrounding spaces = false, want true")
+	}
+}
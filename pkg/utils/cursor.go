@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrMalformedCursor = errors.New("malformed cursor")
+
+// EncodeCursor packs the keyset values of an item into an opaque URL safe cursor
+func EncodeCursor(values ...any) string {
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor unpacks a cursor made by EncodeCursor into pointers to the same number of values
+func DecodeCursor(cursor string, values ...any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrMalformedCursor
+	}
+
+	var raw []json.RawMessage
+	err = json.Unmarshal(data, &raw)
+	if err != nil || len(raw) != len(values) {
+		return ErrMalformedCursor
+	}
+
+	for i, value := range values {
+		err = json.Unmarshal(raw[i], value)
+		if err != nil {
+			return ErrMalformedCursor
+		}
+	}
+
+	return nil
+}
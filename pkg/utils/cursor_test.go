@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC)
+	cursor := EncodeCursor("email:asc,id:asc", "ada@example.com", createdAt, 42)
+
+	var key, email string
+	var gotCreatedAt time.Time
+	var id int
+	err := DecodeCursor(cursor, &key, &email, &gotCreatedAt, &id)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+
+	if key != "email:asc,id:asc" || email != "ada@example.com" || id != 42 || !gotCreatedAt.Equal(createdAt) {
+		t.Fatalf("DecodeCursor = %q, %q, %v, %d", key, email, gotCreatedAt, id)
+	}
+}
+
+func TestDecodeCursorMalformed(t *testing.T) {
+	valid := EncodeCursor("id:asc", 7)
+
+	tests := []struct {
+		name   string
+		cursor string
+		values int
+	}{
+		{"empty", "", 2},
+		{"not base64", "not a cursor!", 2},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("{id: 7")), 2},
+		{"not a list", base64.RawURLEncoding.EncodeToString([]byte(`{"id":7}`)), 2},
+		{"fewer values", valid, 3},
+		{"more values", valid, 1},
+		{"wrong type", EncodeCursor("id:asc", "seven"), 2},
+		{"tampered", valid[:len(valid)-2], 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key string
+			var id int
+			values := []any{&key, &id, new(string)}[:tt.values]
+
+			err := DecodeCursor(tt.cursor, values...)
+			if !errors.Is(err, ErrMalformedCursor) {
+				t.Fatalf("DecodeCursor error = %v, want %v", err, ErrMalformedCursor)
+			}
+		})
+	}
+}
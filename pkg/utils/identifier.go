@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeIdentifier returns the form of an email or username that lookups and uniqueness
+// compare, NFKC normalized so compatibility spellings match and lowercased like the
+// lower(normalize(value, NFKC)) of the migrations.
+func NormalizeIdentifier(s string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimSpace(s)))
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrUnsupportedCurve = errors.New("unsupported elliptic curve")
+
+// ECDSAJWKParams returns the curve name and base64url coordinates of the key as used in a JWK, RFC 7518
+func ECDSAJWKParams(publicKey *ecdsa.PublicKey) (crv, x, y string, err error) {
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+
+	switch publicKey.Curve {
+	case elliptic.P256():
+		crv = "P-256"
+	case elliptic.P384():
+		crv = "P-384"
+	case elliptic.P521():
+		crv = "P-521"
+	default:
+		return "", "", "", ErrUnsupportedCurve
+	}
+
+	// Coordinates are left padded to the curve size
+	xBytes := make([]byte, size)
+	yBytes := make([]byte, size)
+	publicKey.X.FillBytes(xBytes)
+	publicKey.Y.FillBytes(yBytes)
+
+	return crv, base64.RawURLEncoding.EncodeToString(xBytes), base64.RawURLEncoding.EncodeToString(yBytes), nil
+}
+
+// ECDSAThumbprint returns the RFC 7638 thumbprint of the key, used as its key id
+func ECDSAThumbprint(publicKey *ecdsa.PublicKey) (string, error) {
+	crv, x, y, err := ECDSAJWKParams(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	// Required members in lexicographic order
+	canonical, err := json.Marshal(struct {
+		Crv string `json:"crv"`
+		Kty string `json:"kty"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}{crv, "EC", x, y})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
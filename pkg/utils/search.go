@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minHighlightPrefix is the shortest prefix of a search term Highlight marks on its own
+const minHighlightPrefix = 3
+
+// SearchTerms returns the words of a search query in normalized form, letters and digits
+// separated by anything else
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(NormalizeIdentifier(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Highlight escapes text for HTML and wraps the matches of the terms in <mark> tags. Terms
+// match regardless of case, a term that does not occur matches by its longest prefix of at
+// least three runes so a misspelled term still marks the likely match.
+func Highlight(text string, terms []string) string {
+	marked := make([]bool, len(text))
+	for _, term := range terms {
+		for n := utf8.RuneCountInString(term); n > 0; n-- {
+			if markFold(text, prefixRunes(term, n), marked) || n <= minHighlightPrefix {
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	open := false
+	for i, r := range text {
+		if marked[i] != open {
+			if open {
+				b.WriteString("</mark>")
+			} else {
+				b.WriteString("<mark>")
+			}
+			open = marked[i]
+		}
+		b.WriteString(html.EscapeString(string(r)))
+	}
+	if open {
+		b.WriteString("</mark>")
+	}
+
+	return b.String()
+}
+
+// markFold marks every occurrence of substr in s regardless of case and reports whether there was one
+func markFold(s, substr string, marked []bool) bool {
+	n := utf8.RuneCountInString(substr)
+	found := false
+	for i := range s {
+		end := i
+		for k := 0; k < n && end < len(s); k++ {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+		if strings.EqualFold(s[i:end], substr) {
+			for j := i; j < end; j++ {
+				marked[j] = true
+			}
+			found = true
+		}
+	}
+	return found
+}
+
+// prefixRunes returns the first n runes of s
+func prefixRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
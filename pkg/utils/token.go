@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// GenerateRandomToken returns a url safe random string built from n random bytes.
+func GenerateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex encoded sha256 digest of a token, used for storing opaque tokens.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// SignToken appends an HMAC-SHA256 signature of the token, separated by a dot.
+func SignToken(secret, token string) string {
+	return token + "." + tokenSignature(secret, token)
+}
+
+// VerifySignedToken checks the signature added by SignToken and returns the unsigned token.
+func VerifySignedToken(secret, signed string) (string, bool) {
+	idx := strings.LastIndex(signed, ".")
+	if idx < 1 {
+		return "", false
+	}
+
+	token, signature := signed[:idx], signed[idx+1:]
+	if !hmac.Equal([]byte(signature), []byte(tokenSignature(secret, token))) {
+		return "", false
+	}
+
+	return token, true
+}
+
+func tokenSignature(secret, token string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
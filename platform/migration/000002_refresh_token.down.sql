@@ -0,0 +1,2 @@
+-- Down
+DROP TABLE gostarter_refresh_token CASCADE;
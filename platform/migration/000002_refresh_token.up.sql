@@ -0,0 +1,14 @@
+-- Up
+CREATE TABLE gostarter_refresh_token
+(
+    id         SERIAL PRIMARY KEY,
+    account_id INT                      NOT NULL,
+    family_id  VARCHAR(64)              NOT NULL,
+    token_hash VARCHAR(64) UNIQUE       NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+CREATE INDEX idx_gostarter_refresh_token_family ON gostarter_refresh_token (family_id);
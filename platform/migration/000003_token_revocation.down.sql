@@ -0,0 +1,3 @@
+-- Down
+DROP TABLE gostarter_revoked_token CASCADE;
+DROP TABLE gostarter_account_revocation CASCADE;
@@ -0,0 +1,21 @@
+-- Up
+CREATE TABLE gostarter_revoked_token
+(
+    jti        VARCHAR(64) PRIMARY KEY,
+    account_id INT                      NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_gostarter_revoked_token_expires_at ON gostarter_revoked_token (expires_at);
+
+-- Up
+CREATE TABLE gostarter_account_revocation
+(
+    account_id     INT PRIMARY KEY,
+    revoked_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+CREATE INDEX idx_gostarter_account_revocation_expires_at ON gostarter_account_revocation (expires_at);
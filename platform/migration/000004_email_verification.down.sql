@@ -0,0 +1,4 @@
+-- Down
+DROP TABLE gostarter_account_token CASCADE;
+ALTER TABLE gostarter_account
+    DROP COLUMN email_verified_at;
@@ -0,0 +1,18 @@
+-- Up
+ALTER TABLE gostarter_account
+    ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Up
+CREATE TABLE gostarter_account_token
+(
+    id         SERIAL PRIMARY KEY,
+    account_id INT                      NOT NULL,
+    purpose    VARCHAR(64)              NOT NULL,
+    token_hash VARCHAR(64) UNIQUE       NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+CREATE INDEX idx_gostarter_account_token_account ON gostarter_account_token (account_id, purpose);
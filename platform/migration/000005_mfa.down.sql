@@ -0,0 +1,3 @@
+-- Down
+DROP TABLE gostarter_account_recovery_code CASCADE;
+DROP TABLE gostarter_account_mfa CASCADE;
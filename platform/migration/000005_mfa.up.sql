@@ -0,0 +1,23 @@
+-- Up
+CREATE TABLE gostarter_account_mfa
+(
+    account_id   INT PRIMARY KEY,
+    secret       TEXT                     NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+-- Up
+CREATE TABLE gostarter_account_recovery_code
+(
+    id         SERIAL PRIMARY KEY,
+    account_id INT                      NOT NULL,
+    code_hash  VARCHAR(64)              NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+CREATE INDEX idx_gostarter_account_recovery_code_account ON gostarter_account_recovery_code (account_id, code_hash);
@@ -0,0 +1,15 @@
+-- Up
+CREATE TABLE gostarter_account_identity
+(
+    id         SERIAL PRIMARY KEY,
+    account_id INT                      NOT NULL,
+    provider   VARCHAR(64)              NOT NULL,
+    subject    VARCHAR(255)             NOT NULL,
+    email      VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject),
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+CREATE INDEX idx_gostarter_account_identity_account ON gostarter_account_identity (account_id);
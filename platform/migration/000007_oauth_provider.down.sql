@@ -0,0 +1,5 @@
+-- Down
+DROP TABLE gostarter_oauth_consent CASCADE;
+DROP TABLE gostarter_oauth_token CASCADE;
+DROP TABLE gostarter_oauth_code CASCADE;
+DROP TABLE gostarter_oauth_client CASCADE;
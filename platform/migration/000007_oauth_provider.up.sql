@@ -0,0 +1,61 @@
+-- Up
+CREATE TABLE gostarter_oauth_client
+(
+    client_id     VARCHAR(64) PRIMARY KEY,
+    secret_hash   VARCHAR(64),
+    name          VARCHAR(255)             NOT NULL,
+    redirect_uris TEXT                     NOT NULL,
+    scopes        TEXT                     NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Up
+CREATE TABLE gostarter_oauth_code
+(
+    id                    SERIAL PRIMARY KEY,
+    code_hash             VARCHAR(64) UNIQUE       NOT NULL,
+    client_id             VARCHAR(64)              NOT NULL,
+    account_id            INT                      NOT NULL,
+    redirect_uri          TEXT                     NOT NULL,
+    scope                 TEXT                     NOT NULL,
+    nonce                 VARCHAR(255),
+    code_challenge        VARCHAR(128)             NOT NULL,
+    code_challenge_method VARCHAR(16)              NOT NULL,
+    expires_at            TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at               TIMESTAMP WITH TIME ZONE,
+    created_at            TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (client_id) REFERENCES gostarter_oauth_client (client_id) ON DELETE CASCADE,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+-- Up
+CREATE TABLE gostarter_oauth_token
+(
+    id         SERIAL PRIMARY KEY,
+    token_hash VARCHAR(64) UNIQUE       NOT NULL,
+    kind       VARCHAR(32)              NOT NULL,
+    client_id  VARCHAR(64)              NOT NULL,
+    account_id INT                      NOT NULL,
+    scope      TEXT                     NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (client_id) REFERENCES gostarter_oauth_client (client_id) ON DELETE CASCADE,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+CREATE INDEX idx_gostarter_oauth_token_grant ON gostarter_oauth_token (client_id, account_id);
+
+-- Up
+CREATE TABLE gostarter_oauth_consent
+(
+    account_id INT                      NOT NULL,
+    client_id  VARCHAR(64)              NOT NULL,
+    scope      TEXT                     NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (account_id, client_id),
+    FOREIGN KEY (client_id) REFERENCES gostarter_oauth_client (client_id) ON DELETE CASCADE,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
@@ -0,0 +1,16 @@
+-- Up
+CREATE TABLE gostarter_api_key
+(
+    id           SERIAL PRIMARY KEY,
+    account_id   INT                      NOT NULL,
+    name         VARCHAR(255)             NOT NULL,
+    prefix       VARCHAR(32)              NOT NULL,
+    token_hash   VARCHAR(255)             NOT NULL UNIQUE,
+    scopes       TEXT                     NOT NULL,
+    expires_at   TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id)
+);
+
+CREATE INDEX idx_gostarter_api_key_account ON gostarter_api_key (account_id);
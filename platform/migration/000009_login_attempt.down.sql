@@ -0,0 +1,2 @@
+-- Down
+DROP TABLE gostarter_login_attempt CASCADE;
@@ -0,0 +1,12 @@
+-- Up
+CREATE TABLE gostarter_login_attempt
+(
+    scope           VARCHAR(16)              NOT NULL,
+    key             VARCHAR(255)             NOT NULL,
+    failures        INT                      NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until    TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idx_gostarter_login_attempt_last_failure ON gostarter_login_attempt (last_failure_at);
@@ -0,0 +1,2 @@
+-- Down
+DROP TABLE gostarter_password_history CASCADE;
@@ -0,0 +1,11 @@
+-- Up
+CREATE TABLE gostarter_password_history
+(
+    id            SERIAL PRIMARY KEY,
+    account_id    INT                      NOT NULL,
+    password_hash VARCHAR(255)             NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_gostarter_password_history_account ON gostarter_password_history (account_id, created_at);
@@ -0,0 +1,6 @@
+-- Down
+DROP TABLE gostarter_role_permission CASCADE;
+DROP TABLE gostarter_permission CASCADE;
+
+ALTER TABLE gostarter_role
+    DROP CONSTRAINT uq_gostarter_role_name;
@@ -0,0 +1,60 @@
+-- Up
+-- Roles used to be created on first use, duplicates are merged before role names become unique
+INSERT INTO gostarter_account_role (account_id, role_id, created_at)
+SELECT ar.account_id, keep.id, ar.created_at
+FROM gostarter_account_role ar
+         JOIN gostarter_role r ON r.id = ar.role_id
+         JOIN (SELECT name, MIN(id) AS id FROM gostarter_role GROUP BY name) keep ON keep.name = r.name
+WHERE r.id <> keep.id
+ON CONFLICT DO NOTHING;
+
+DELETE
+FROM gostarter_account_role ar
+    USING gostarter_role r
+WHERE ar.role_id = r.id
+  AND r.id <> (SELECT MIN(id) FROM gostarter_role WHERE name = r.name);
+
+DELETE
+FROM gostarter_role r
+WHERE r.id <> (SELECT MIN(id) FROM gostarter_role WHERE name = r.name);
+
+ALTER TABLE gostarter_role
+    ADD CONSTRAINT uq_gostarter_role_name UNIQUE (name);
+
+INSERT INTO gostarter_role (name, updated_at)
+VALUES ('user', CURRENT_TIMESTAMP),
+       ('admin', CURRENT_TIMESTAMP)
+ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE gostarter_permission
+(
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(255)             NOT NULL UNIQUE,
+    description TEXT                     NOT NULL DEFAULT '',
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE gostarter_role_permission
+(
+    role_id       INT                      NOT NULL,
+    permission_id INT                      NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES gostarter_role (id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES gostarter_permission (id) ON DELETE CASCADE
+);
+
+INSERT INTO gostarter_permission (name, description)
+VALUES ('*', 'Every permission'),
+       ('accounts:read', 'List and view accounts'),
+       ('accounts:write', 'Update and delete accounts'),
+       ('accounts:unlock', 'Unlock accounts locked after failed logins'),
+       ('roles:manage', 'Create roles and grant them permissions');
+
+-- Admins keep full access, the user role needs no permission for its own account
+INSERT INTO gostarter_role_permission (role_id, permission_id)
+SELECT r.id, p.id
+FROM gostarter_role r,
+     gostarter_permission p
+WHERE r.name = 'admin'
+  AND p.name = '*';
@@ -0,0 +1,3 @@
+-- Down
+DROP TABLE gostarter_organization_member CASCADE;
+DROP TABLE gostarter_organization CASCADE;
@@ -0,0 +1,29 @@
+-- Up
+CREATE TABLE gostarter_organization
+(
+    id         SERIAL PRIMARY KEY,
+    name       VARCHAR(255)             NOT NULL,
+    slug       VARCHAR(255)             NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TRIGGER update_gostarter_organization_timestamp
+    BEFORE UPDATE
+    ON gostarter_organization
+    FOR EACH ROW
+EXECUTE FUNCTION update_timestamp();
+
+-- Organization roles are scoped to the membership, unrelated to the global roles in gostarter_role
+CREATE TABLE gostarter_organization_member
+(
+    organization_id INT                      NOT NULL,
+    account_id      INT                      NOT NULL,
+    role            VARCHAR(32)              NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, account_id),
+    FOREIGN KEY (organization_id) REFERENCES gostarter_organization (id) ON DELETE CASCADE,
+    FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_gostarter_organization_member_account ON gostarter_organization_member (account_id);
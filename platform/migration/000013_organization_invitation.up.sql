@@ -0,0 +1,19 @@
+-- Up
+CREATE TABLE gostarter_organization_invitation
+(
+    id              SERIAL PRIMARY KEY,
+    organization_id INT                      NOT NULL,
+    email           VARCHAR(255)             NOT NULL,
+    role            VARCHAR(32)              NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    token_hash      VARCHAR(64)              NOT NULL UNIQUE,
+    invited_by      INT,
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at     TIMESTAMP WITH TIME ZONE,
+    accepted_by     INT,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES gostarter_organization (id) ON DELETE CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES gostarter_account (id) ON DELETE SET NULL,
+    FOREIGN KEY (accepted_by) REFERENCES gostarter_account (id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_gostarter_organization_invitation_email ON gostarter_organization_invitation (organization_id, email);
@@ -0,0 +1,4 @@
+-- Down
+DELETE
+FROM gostarter_permission
+WHERE name = 'accounts:impersonate';
@@ -0,0 +1,4 @@
+-- Up
+INSERT INTO gostarter_permission (name, description)
+VALUES ('accounts:impersonate', 'Act as another account, limited to accounts granted no more permissions')
+ON CONFLICT (name) DO NOTHING;
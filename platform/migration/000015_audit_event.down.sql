@@ -0,0 +1,7 @@
+-- Down
+DELETE
+FROM gostarter_permission
+WHERE name = 'audit:read';
+
+DROP TABLE IF EXISTS gostarter_audit_event;
+DROP FUNCTION IF EXISTS gostarter_audit_event_immutable();
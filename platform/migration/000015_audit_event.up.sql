@@ -0,0 +1,39 @@
+-- Up
+-- Events outlive the accounts and organizations they mention, so the ids are not foreign keys
+CREATE TABLE gostarter_audit_event
+(
+    id              BIGSERIAL PRIMARY KEY,
+    action          VARCHAR(64)              NOT NULL,
+    actor_id        INT,
+    subject_id      INT,
+    impersonated_id INT,
+    organization_id INT,
+    ip              VARCHAR(64)              NOT NULL DEFAULT '',
+    user_agent      TEXT                     NOT NULL DEFAULT '',
+    request_id      VARCHAR(128)             NOT NULL DEFAULT '',
+    metadata        JSONB                    NOT NULL DEFAULT '{}',
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_gostarter_audit_event_created_at ON gostarter_audit_event (created_at);
+CREATE INDEX idx_gostarter_audit_event_actor ON gostarter_audit_event (actor_id, created_at);
+CREATE INDEX idx_gostarter_audit_event_subject ON gostarter_audit_event (subject_id, created_at);
+CREATE INDEX idx_gostarter_audit_event_action ON gostarter_audit_event (action, created_at);
+
+-- Events are append only, rows are only ever removed by the retention pruning
+CREATE FUNCTION gostarter_audit_event_immutable() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'gostarter_audit_event is append only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_gostarter_audit_event_immutable
+    BEFORE UPDATE
+    ON gostarter_audit_event
+    FOR EACH ROW
+EXECUTE FUNCTION gostarter_audit_event_immutable();
+
+INSERT INTO gostarter_permission (name, description)
+VALUES ('audit:read', 'Search and export the security audit log')
+ON CONFLICT (name) DO NOTHING;
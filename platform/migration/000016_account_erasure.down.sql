@@ -0,0 +1,60 @@
+-- Down
+ALTER TABLE gostarter_account_role
+    DROP CONSTRAINT gostarter_account_role_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_role_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_refresh_token
+    DROP CONSTRAINT gostarter_refresh_token_account_id_fkey,
+    ADD CONSTRAINT gostarter_refresh_token_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_account_revocation
+    DROP CONSTRAINT gostarter_account_revocation_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_revocation_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_account_token
+    DROP CONSTRAINT gostarter_account_token_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_token_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_account_mfa
+    DROP CONSTRAINT gostarter_account_mfa_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_mfa_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_account_recovery_code
+    DROP CONSTRAINT gostarter_account_recovery_code_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_recovery_code_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_account_identity
+    DROP CONSTRAINT gostarter_account_identity_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_identity_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_oauth_code
+    DROP CONSTRAINT gostarter_oauth_code_account_id_fkey,
+    ADD CONSTRAINT gostarter_oauth_code_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_oauth_token
+    DROP CONSTRAINT gostarter_oauth_token_account_id_fkey,
+    ADD CONSTRAINT gostarter_oauth_token_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_oauth_consent
+    DROP CONSTRAINT gostarter_oauth_consent_account_id_fkey,
+    ADD CONSTRAINT gostarter_oauth_consent_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+ALTER TABLE gostarter_api_key
+    DROP CONSTRAINT gostarter_api_key_account_id_fkey,
+    ADD CONSTRAINT gostarter_api_key_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id);
+
+DROP INDEX IF EXISTS idx_gostarter_account_deleted_at;
+
+ALTER TABLE gostarter_account
+    DROP COLUMN deleted_at;
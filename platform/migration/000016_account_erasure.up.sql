@@ -0,0 +1,62 @@
+-- Up
+-- Deleted accounts are kept for a grace period before they are purged
+ALTER TABLE gostarter_account
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_gostarter_account_deleted_at ON gostarter_account (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Purging an account removes its dependent rows
+ALTER TABLE gostarter_account_role
+    DROP CONSTRAINT gostarter_account_role_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_role_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_refresh_token
+    DROP CONSTRAINT gostarter_refresh_token_account_id_fkey,
+    ADD CONSTRAINT gostarter_refresh_token_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_account_revocation
+    DROP CONSTRAINT gostarter_account_revocation_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_revocation_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_account_token
+    DROP CONSTRAINT gostarter_account_token_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_token_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_account_mfa
+    DROP CONSTRAINT gostarter_account_mfa_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_mfa_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_account_recovery_code
+    DROP CONSTRAINT gostarter_account_recovery_code_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_recovery_code_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_account_identity
+    DROP CONSTRAINT gostarter_account_identity_account_id_fkey,
+    ADD CONSTRAINT gostarter_account_identity_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_oauth_code
+    DROP CONSTRAINT gostarter_oauth_code_account_id_fkey,
+    ADD CONSTRAINT gostarter_oauth_code_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_oauth_token
+    DROP CONSTRAINT gostarter_oauth_token_account_id_fkey,
+    ADD CONSTRAINT gostarter_oauth_token_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_oauth_consent
+    DROP CONSTRAINT gostarter_oauth_consent_account_id_fkey,
+    ADD CONSTRAINT gostarter_oauth_consent_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
+
+ALTER TABLE gostarter_api_key
+    DROP CONSTRAINT gostarter_api_key_account_id_fkey,
+    ADD CONSTRAINT gostarter_api_key_account_id_fkey
+        FOREIGN KEY (account_id) REFERENCES gostarter_account (id) ON DELETE CASCADE;
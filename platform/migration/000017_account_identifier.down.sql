@@ -0,0 +1,14 @@
+-- Down
+DROP INDEX gostarter_account_username_normalized_key;
+DROP INDEX gostarter_account_email_normalized_key;
+
+ALTER TABLE gostarter_account
+    ADD CONSTRAINT gostarter_account_email_key UNIQUE (email);
+
+UPDATE gostarter_account
+SET username = email
+WHERE username = '';
+
+ALTER TABLE gostarter_account
+    DROP COLUMN username_normalized,
+    DROP COLUMN email_normalized;
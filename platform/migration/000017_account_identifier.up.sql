@@ -0,0 +1,50 @@
+-- Up
+-- Emails and usernames are compared in a normalized form, lowercased after NFKC normalization
+-- like utils.NormalizeIdentifier. normalize() needs Postgres 13 and a UTF8 database.
+ALTER TABLE gostarter_account
+    ADD COLUMN email_normalized    VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN username_normalized VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Usernames used to default to the email, those accounts are left without one
+UPDATE gostarter_account
+SET username = ''
+WHERE username = email;
+
+UPDATE gostarter_account
+SET email_normalized    = lower(normalize(btrim(email), NFKC)),
+    username_normalized = lower(normalize(btrim(username), NFKC));
+
+-- Accounts differing only in case or normalization cannot be told apart at login. They have to
+-- be merged or renamed by hand, then the dirty version forced back to 16 and the migration rerun.
+DO
+$$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(format('%s %s: accounts %s', kind, value, ids), E'\n' ORDER BY kind, value)
+    INTO duplicates
+    FROM (SELECT 'email' AS kind, email_normalized AS value, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+          FROM gostarter_account
+          GROUP BY email_normalized
+          HAVING COUNT(*) > 1
+          UNION ALL
+          SELECT 'username', username_normalized, string_agg(id::TEXT, ', ' ORDER BY id)
+          FROM gostarter_account
+          WHERE username_normalized <> ''
+          GROUP BY username_normalized
+          HAVING COUNT(*) > 1) AS duplicate;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'duplicate account identifiers:%', E'\n' || duplicates;
+    END IF;
+END
+$$;
+
+-- Deleted accounts keep their identifiers until they are purged, so a restore never conflicts
+ALTER TABLE gostarter_account
+    DROP CONSTRAINT gostarter_account_email_key,
+    ALTER COLUMN email_normalized DROP DEFAULT;
+
+CREATE UNIQUE INDEX gostarter_account_email_normalized_key ON gostarter_account (email_normalized);
+CREATE UNIQUE INDEX gostarter_account_username_normalized_key ON gostarter_account (username_normalized)
+    WHERE username_normalized <> '';
@@ -0,0 +1,5 @@
+-- Down
+DELETE FROM gostarter_account_token WHERE purpose = 'email_change';
+
+ALTER TABLE gostarter_account_token
+    DROP COLUMN payload;
@@ -0,0 +1,4 @@
+-- Up
+-- Email change tokens carry the new address until it is confirmed
+ALTER TABLE gostarter_account_token
+    ADD COLUMN payload VARCHAR(255) NOT NULL DEFAULT '';
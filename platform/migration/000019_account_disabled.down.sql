@@ -0,0 +1,3 @@
+-- Down
+ALTER TABLE gostarter_account
+    DROP COLUMN disabled_at;
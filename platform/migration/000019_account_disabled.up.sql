@@ -0,0 +1,4 @@
+-- Up
+-- Disabled accounts keep their data but cannot start sessions until an admin enables them
+ALTER TABLE gostarter_account
+    ADD COLUMN disabled_at TIMESTAMP WITH TIME ZONE;
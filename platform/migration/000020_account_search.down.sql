@@ -0,0 +1,8 @@
+-- Down
+-- pg_trgm is left installed, other database objects may have come to use it
+DROP INDEX gostarter_account_email_trgm_idx;
+DROP INDEX gostarter_account_username_trgm_idx;
+DROP INDEX gostarter_account_search_vector_idx;
+
+ALTER TABLE gostarter_account
+    DROP COLUMN search_vector;
@@ -0,0 +1,16 @@
+-- Up
+-- Account search matches the words of usernames and emails by prefix through search_vector,
+-- and misspelled words by trigram similarity. Both are kept on the normalized identifiers.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Emails are split into words, the parser would keep an address as a single token.
+-- Username words rank above email words.
+ALTER TABLE gostarter_account
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', regexp_replace(username_normalized, '[^[:alnum:]]+', ' ', 'g')), 'A') ||
+        setweight(to_tsvector('simple', regexp_replace(email_normalized, '[^[:alnum:]]+', ' ', 'g')), 'B')
+    ) STORED;
+
+CREATE INDEX gostarter_account_search_vector_idx ON gostarter_account USING GIN (search_vector);
+CREATE INDEX gostarter_account_username_trgm_idx ON gostarter_account USING GIN (username_normalized gin_trgm_ops);
+CREATE INDEX gostarter_account_email_trgm_idx ON gostarter_account USING GIN (email_normalized gin_trgm_ops);
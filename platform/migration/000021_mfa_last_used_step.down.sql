@@ -0,0 +1,3 @@
+-- Down
+ALTER TABLE gostarter_account_mfa
+    DROP COLUMN last_used_step;
@@ -0,0 +1,4 @@
+-- Up
+-- The TOTP time step of the last accepted code, a code is not accepted twice (RFC 6238 section 5.2)
+ALTER TABLE gostarter_account_mfa
+    ADD COLUMN last_used_step BIGINT NOT NULL DEFAULT 0;